@@ -0,0 +1,215 @@
+// Package analysis rates moves by playing games out from them with bots. It backs
+// the in-game hint and the list of mistakes shown after a game
+package analysis
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Analyzer estimates the win probability of moves from random deals of the cards
+// the player cannot see, each played out to the end by greedy bots
+type Analyzer struct {
+	Rollouts int // Deals played out per move, 0 for 100
+	MaxMoves int // Moves after which a rollout counts as half a win, 0 for 500
+
+	rng *rand.Rand
+}
+
+// MoveValue is a move with its estimated chance of winning the game
+type MoveValue struct {
+	Move    game.Move
+	WinRate float64
+}
+
+// Mistake is a move of a replay that was clearly worse than the best alternative
+type Mistake struct {
+	Index   int // Index of the move in the replay
+	Move    game.Move
+	WinRate float64
+	Best    MoveValue
+}
+
+// Loss returns how much winning chance the mistake gave away
+func (m Mistake) Loss() float64 {
+	return m.Best.WinRate - m.WinRate
+}
+
+// New creates an analyzer, the seed makes its estimates reproducible
+func New(seed uint64) *Analyzer {
+	return &Analyzer{rng: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+}
+
+// Rank rates every legal move of the view, best first
+// Every move is played out on the same deals so their values compare fairly
+func (a *Analyzer) Rank(view game.PlayerView) ([]MoveValue, error) {
+	if len(view.LegalMoves) == 0 {
+		return nil, errors.New("the player has no legal moves")
+	}
+
+	values := make([]MoveValue, len(view.LegalMoves))
+	for i, move := range view.LegalMoves {
+		values[i].Move = move
+	}
+
+	// A lone move needs no rollouts
+	if len(values) == 1 {
+		values[0].WinRate = a.winRate(view, values[0].Move)
+		return values, nil
+	}
+
+	rollouts := a.rollouts()
+	for range rollouts {
+		deal, err := game.Determinize(view, a.rng)
+		if err != nil {
+			return nil, err
+		}
+		seed := a.rng.Uint64()
+
+		for i := range values {
+			values[i].WinRate += a.playout(deal, view.Seat, values[i].Move, seed)
+		}
+	}
+
+	for i := range values {
+		values[i].WinRate /= float64(rollouts)
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].WinRate > values[j].WinRate
+	})
+	return values, nil
+}
+
+// Hint returns the best move for the view
+func (a *Analyzer) Hint(view game.PlayerView) (MoveValue, error) {
+	values, err := a.Rank(view)
+	if err != nil {
+		return MoveValue{}, err
+	}
+	return values[0], nil
+}
+
+// Mistakes goes through a replay and returns the moves whose estimated win rate was
+// at least margin below the best move available, judged from the mover's own view
+func (a *Analyzer) Mistakes(replay *game.Replay, margin float64) ([]Mistake, error) {
+	var mistakes []Mistake
+
+	err := replay.Walk(func(i int, state *game.GameState, move game.Move) error {
+		view := state.View(move.Player)
+		if len(view.LegalMoves) < 2 {
+			return nil
+		}
+
+		values, err := a.Rank(view)
+		if err != nil {
+			return err
+		}
+
+		for _, value := range values {
+			if sameMove(state, value.Move, move) {
+				if values[0].WinRate-value.WinRate >= margin {
+					mistakes = append(mistakes, Mistake{Index: i, Move: move, WinRate: value.WinRate, Best: values[0]})
+				}
+				return nil
+			}
+		}
+		return nil
+	})
+
+	return mistakes, err
+}
+
+func (a *Analyzer) rollouts() int {
+	if a.Rollouts <= 0 {
+		return 100
+	}
+	return a.Rollouts
+}
+
+// winRate estimates the win rate of a single move on fresh deals
+func (a *Analyzer) winRate(view game.PlayerView, move game.Move) float64 {
+	rollouts := a.rollouts()
+	total := 0.0
+	for range rollouts {
+		deal, err := game.Determinize(view, a.rng)
+		if err != nil {
+			return 0
+		}
+		total += a.playout(deal, view.Seat, move, a.rng.Uint64())
+	}
+	return total / float64(rollouts)
+}
+
+// playout makes the move on a copy of the deal and lets greedy bots finish the game
+// Returns 1 if the seat's team won, 0 if it lost and 0.5 if the game did not end
+func (a *Analyzer) playout(deal *game.GameState, seat int, move game.Move, seed uint64) float64 {
+	state := deal.Clone()
+	state.SetRandom(rand.New(rand.NewPCG(seed, seed)))
+
+	rules := game.NewGameRules()
+	if err := rules.Apply(state, move); err != nil {
+		return 0
+	}
+
+	seats := make([]bot.Bot, len(state.Players))
+	for i := range seats {
+		seats[i] = bot.NewGreedyBot()
+	}
+
+	table, err := bot.NewTable(state, rules, seats)
+	if err != nil {
+		return 0
+	}
+
+	maxMoves := a.MaxMoves
+	if maxMoves <= 0 {
+		maxMoves = 500
+	}
+	if _, err := table.Run(maxMoves); err != nil {
+		return 0
+	}
+
+	switch state.WinningTeam() {
+	case -1:
+		return 0.5
+	case state.TeamOf(seat):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// sameMove checks if two moves do the same thing in the state. Only the fields the
+// move uses count, a recorded move may carry leftover values in the others
+func sameMove(state *game.GameState, a, b game.Move) bool {
+	if a.Kind != b.Kind || a.Player != b.Player {
+		return false
+	}
+
+	switch a.Kind {
+	case game.MovePlay:
+		if a.CardIndex != b.CardIndex {
+			return false
+		}
+		card := state.Players[a.Player].Hand.At(a.CardIndex)
+		if card.Color != game.Wild {
+			return true
+		}
+		return a.Color == b.Color && (card.Type != game.WildSwapHands || a.Target == b.Target)
+	case game.MoveChooseColor:
+		top, err := state.DiscardPile.Top()
+		if err == nil && top.Type == game.WildSwapHands && state.LastPlayedBy != -1 {
+			return a.Color == b.Color && a.Target == b.Target
+		}
+		return a.Color == b.Color
+	case game.MoveChallenge:
+		return a.Target == b.Target
+	default:
+		return true
+	}
+}
@@ -0,0 +1,126 @@
+package analysis
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// stateWithHands deals fixed hands with top on the discard pile and the rest of the deck as draw pile
+func stateWithHands(hands [][]game.Card, top game.Card) *game.GameState {
+	remaining := game.NewDeck().Cards()
+	take := func(card game.Card) {
+		for i, c := range remaining {
+			if c == card {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				return
+			}
+		}
+	}
+
+	state := &game.GameState{
+		DiscardPile:  game.CreateDiscardPile(top),
+		ActiveColor:  top.Color,
+		Phase:        game.PhasePlay,
+		LastPlayedBy: -1,
+	}
+	take(top)
+
+	for i, hand := range hands {
+		player := game.NewPlayer("Player")
+		for _, card := range hand {
+			take(card)
+			player.AddCard(card)
+		}
+		player.IsMyTurn = i == 0
+		state.Players = append(state.Players, player)
+	}
+
+	state.DrawPile = game.NewDeckOf(remaining)
+	return state
+}
+
+func TestHint(t *testing.T) {
+	// Playing the last card wins on the spot, drawing does not
+	state := stateWithHands([][]game.Card{
+		{{Color: game.Red, Type: game.Number, Value: 3}},
+		{{Color: game.Blue, Type: game.Number, Value: 1}, {Color: game.Blue, Type: game.Number, Value: 2}},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+	state.Players[0].HasCalledUno = true
+
+	analyzer := New(1)
+	analyzer.Rollouts = 20
+
+	values, err := analyzer.Rank(state.View(0))
+	if err != nil {
+		t.Fatalf("Expected no error ranking moves, got %v", err)
+	}
+
+	if values[0].Move.Kind != game.MovePlay || values[0].WinRate != 1 {
+		t.Errorf("Expected the winning play first with a win rate of 1, got %v", values[0])
+	}
+
+	for i := 1; i < len(values); i++ {
+		if values[i].WinRate > values[i-1].WinRate {
+			t.Errorf("Expected moves sorted best first, got %v", values)
+		}
+	}
+
+	hint, err := analyzer.Hint(state.View(0))
+	if err != nil || hint.Move != values[0].Move {
+		t.Errorf("Expected the hint to be the best move, got %v and %v", hint, err)
+	}
+
+	// Player 2 has nothing to do
+	if _, err := analyzer.Rank(state.View(1)); err == nil {
+		t.Error("Expected error ranking a view without legal moves")
+	}
+}
+
+func TestMistakes(t *testing.T) {
+	state := stateWithHands([][]game.Card{
+		{{Color: game.Red, Type: game.Number, Value: 3}},
+		{{Color: game.Blue, Type: game.Number, Value: 1}, {Color: game.Blue, Type: game.Number, Value: 2}},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+	state.Players[0].HasCalledUno = true
+	state.SetRandom(rand.New(rand.NewPCG(1, 2)))
+
+	// Drawing instead of playing the winning card is a blunder
+	rules := game.NewGameRules()
+	replay := game.Record(state, rules)
+	table, err := bot.NewTable(state, rules, []bot.Bot{bot.NewGreedyBot(), bot.NewGreedyBot()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.Replay = replay
+
+	draw := game.Move{Kind: game.MoveDraw, Player: 0}
+	if err := rules.Apply(state, draw); err != nil {
+		t.Fatal(err)
+	}
+	replay.Add(draw)
+
+	if _, err := table.Run(1000); err != nil {
+		t.Fatal(err)
+	}
+	if len(replay.Moves) < 2 {
+		t.Fatalf("Expected the bots' moves to be recorded, got %v", replay.Moves)
+	}
+
+	analyzer := New(2)
+	analyzer.Rollouts = 10
+	mistakes, err := analyzer.Mistakes(replay, 0.3)
+	if err != nil {
+		t.Fatalf("Expected no error analyzing the replay, got %v", err)
+	}
+
+	if len(mistakes) == 0 || mistakes[0].Index != 0 || mistakes[0].Move != draw {
+		t.Fatalf("Expected the draw to be the first mistake, got %v", mistakes)
+	}
+
+	if mistakes[0].Loss() < 0.3 || mistakes[0].Best.Move.Kind != game.MovePlay {
+		t.Errorf("Expected playing the last card as the better move, got %v", mistakes[0])
+	}
+}
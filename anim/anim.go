@@ -0,0 +1,134 @@
+// Package anim eases values over game ticks and strings animations together.
+// It knows nothing about drawing: a tween hands its value to a function every
+// tick, which moves whatever is on screen
+package anim
+
+// Animation advances a tick at a time until it is done
+type Animation interface {
+	Update()
+	Done() bool
+}
+
+// Tween eases a value from 0 to 1 over a number of ticks, passing it to apply every tick
+type Tween struct {
+	ticks   int
+	elapsed int
+	ease    Easing
+	apply   func(v float64)
+	done    []func()
+}
+
+// NewTween creates a tween lasting ticks, apply may be nil for a tween that only waits
+func NewTween(ticks int, ease Easing, apply func(v float64)) *Tween {
+	return &Tween{ticks: ticks, ease: ease, apply: apply}
+}
+
+// OnDone adds a function called in the tick the tween ends
+func (t *Tween) OnDone(f func()) *Tween {
+	t.done = append(t.done, f)
+	return t
+}
+
+// Update moves the tween on a tick, a tween of no ticks ending on its first update
+func (t *Tween) Update() {
+	if t.Done() {
+		return
+	}
+	t.elapsed++
+	if t.apply != nil {
+		t.apply(t.Value())
+	}
+	if t.Done() {
+		for _, f := range t.done {
+			f()
+		}
+	}
+}
+
+// Done reports whether the tween has run all its ticks
+func (t *Tween) Done() bool {
+	return t.elapsed > 0 && t.elapsed >= t.ticks
+}
+
+// Value returns the eased value at the current tick
+func (t *Tween) Value() float64 {
+	if t.ticks <= 0 {
+		return t.ease(1)
+	}
+	return t.ease(float64(t.elapsed) / float64(t.ticks))
+}
+
+// Wait does nothing for a number of ticks, to space out a sequence
+func Wait(ticks int) *Tween {
+	return NewTween(ticks, Linear, nil)
+}
+
+// Call runs f on its first update, for a callback in the middle of a sequence
+func Call(f func()) *Tween {
+	return Wait(0).OnDone(f)
+}
+
+// sequence runs animations one after the other
+type sequence struct {
+	anims []Animation
+}
+
+// Sequence runs animations one after the other, each starting the tick after
+// the one before it ends
+func Sequence(anims ...Animation) Animation {
+	return &sequence{anims: anims}
+}
+
+func (s *sequence) Update() {
+	if s.Done() {
+		return
+	}
+	s.anims[0].Update()
+	if s.anims[0].Done() {
+		s.anims = s.anims[1:]
+	}
+}
+
+func (s *sequence) Done() bool {
+	return len(s.anims) == 0
+}
+
+// parallel runs animations side by side
+type parallel struct {
+	anims []Animation
+}
+
+// Parallel runs animations side by side, ending when the longest does
+func Parallel(anims ...Animation) Animation {
+	return &parallel{anims: anims}
+}
+
+func (p *parallel) Update() {
+	for _, a := range p.anims {
+		if !a.Done() {
+			a.Update()
+		}
+	}
+}
+
+func (p *parallel) Done() bool {
+	for _, a := range p.anims {
+		if !a.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+// Stagger starts animations one after another, each a number of ticks after the
+// one before it, so they overlap like cards dealt in quick succession
+func Stagger(ticks int, anims ...Animation) Animation {
+	delayed := make([]Animation, len(anims))
+	for i, a := range anims {
+		delayed[i] = a
+		if i > 0 {
+			delayed[i] = Sequence(Wait(i*ticks), a)
+		}
+	}
+	return Parallel(delayed...)
+}
@@ -0,0 +1,139 @@
+package anim
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// run updates an animation until it is done, returning the number of ticks it took
+func run(t *testing.T, a Animation) int {
+	t.Helper()
+	ticks := 0
+	for !a.Done() {
+		if ticks > 1000 {
+			t.Fatal("Expected the animation to end")
+		}
+		a.Update()
+		ticks++
+	}
+	return ticks
+}
+
+func TestTween(t *testing.T) {
+	var values []float64
+	ended := 0
+	tween := NewTween(4, Linear, func(v float64) { values = append(values, v) }).OnDone(func() { ended++ })
+
+	if tween.Done() {
+		t.Error("Expected a new tween to be running")
+	}
+	if ticks := run(t, tween); ticks != 4 {
+		t.Errorf("Expected 4 ticks, got %d", ticks)
+	}
+	if !slices.Equal(values, []float64{0.25, 0.5, 0.75, 1}) {
+		t.Errorf("Expected the value to step to 1, got %v", values)
+	}
+	if ended != 1 {
+		t.Errorf("Expected the callback once, got %d", ended)
+	}
+
+	// Updating a finished tween changes nothing
+	tween.Update()
+	if len(values) != 4 || ended != 1 {
+		t.Error("Expected a finished tween to stay finished")
+	}
+}
+
+func TestTweenEasing(t *testing.T) {
+	tween := NewTween(2, InQuad, nil)
+	tween.Update()
+	if tween.Value() != 0.25 {
+		t.Errorf("Expected the eased value halfway, got %v", tween.Value())
+	}
+}
+
+func TestCall(t *testing.T) {
+	called := false
+	call := Call(func() { called = true })
+	if call.Done() || called {
+		t.Fatal("Expected the call to wait for its update")
+	}
+	if ticks := run(t, call); ticks != 1 || !called {
+		t.Errorf("Expected the call on the first tick, got %d ticks", ticks)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	var order []string
+	seq := Sequence(
+		NewTween(2, Linear, nil).OnDone(func() { order = append(order, "first") }),
+		Wait(3),
+		Call(func() { order = append(order, "call") }),
+		NewTween(1, Linear, nil).OnDone(func() { order = append(order, "last") }),
+	)
+
+	if ticks := run(t, seq); ticks != 2+3+1+1 {
+		t.Errorf("Expected the ticks of every step added up, got %d", ticks)
+	}
+	if !slices.Equal(order, []string{"first", "call", "last"}) {
+		t.Errorf("Expected the steps in order, got %v", order)
+	}
+	if !Sequence().Done() {
+		t.Error("Expected an empty sequence to be done")
+	}
+}
+
+func TestParallel(t *testing.T) {
+	short, long := NewTween(2, Linear, nil), NewTween(5, Linear, nil)
+	if ticks := run(t, Parallel(short, long)); ticks != 5 {
+		t.Errorf("Expected the longest animation's ticks, got %d", ticks)
+	}
+}
+
+func TestStagger(t *testing.T) {
+	var starts []int
+	tick := 0
+	anims := make([]Animation, 3)
+	for i := range anims {
+		started := false
+		anims[i] = NewTween(4, Linear, func(float64) {
+			if !started {
+				started = true
+				starts = append(starts, tick)
+			}
+		})
+	}
+
+	stagger := Stagger(2, anims...)
+	for !stagger.Done() {
+		tick++
+		stagger.Update()
+	}
+	if !slices.Equal(starts, []int{1, 3, 5}) || tick != 8 {
+		t.Errorf("Expected starts 2 ticks apart and the last ending at 8, got %v and %d", starts, tick)
+	}
+}
+
+func TestEasing(t *testing.T) {
+	for name, ease := range map[string]Easing{
+		"Linear": Linear, "InQuad": InQuad, "OutQuad": OutQuad, "InOutQuad": InOutQuad, "OutCubic": OutCubic, "OutBack": OutBack,
+	} {
+		if math.Abs(ease(0)) > 1e-9 || math.Abs(ease(1)-1) > 1e-9 {
+			t.Errorf("Expected %s to run from 0 to 1, got %v and %v", name, ease(0), ease(1))
+		}
+	}
+
+	if OutQuad(0.5) <= 0.5 || InQuad(0.5) >= 0.5 || InOutQuad(0.5) != 0.5 {
+		t.Error("Expected out easings ahead of linear and in easings behind")
+	}
+	if OutBack(0.8) <= 1 {
+		t.Error("Expected OutBack to overshoot")
+	}
+	if Pulse(0) != 0 || Pulse(0.5) != 1 || math.Abs(Pulse(1)) > 1e-9 {
+		t.Error("Expected Pulse to rise and fall back")
+	}
+	if Lerp(10, 20, 0.25) != 12.5 {
+		t.Errorf("Expected a quarter of the way, got %v", Lerp(10, 20, 0.25))
+	}
+}
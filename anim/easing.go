@@ -0,0 +1,52 @@
+package anim
+
+import "math"
+
+// Easing maps how far a tween is through its ticks, from 0 to 1, to how far its
+// value has moved, also from 0 to 1 but free to overshoot on the way
+type Easing func(t float64) float64
+
+// Linear moves at a steady pace
+func Linear(t float64) float64 {
+	return t
+}
+
+// InQuad starts slow and speeds up
+func InQuad(t float64) float64 {
+	return t * t
+}
+
+// OutQuad starts fast and slows down, for things coming to rest
+func OutQuad(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+// InOutQuad speeds up then slows down
+func InOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return 1 - 2*(1-t)*(1-t)
+}
+
+// OutCubic slows down harder than OutQuad, for cards landing
+func OutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+// OutBack overshoots the end a little and settles back, for things popping in
+func OutBack(t float64) float64 {
+	const overshoot = 1.70158
+	u := t - 1
+	return 1 + (overshoot+1)*u*u*u + overshoot*u*u
+}
+
+// Pulse rises to 1 halfway through and falls back to 0, for a beat on the spot
+func Pulse(t float64) float64 {
+	return math.Sin(math.Pi * t)
+}
+
+// Lerp returns the value the share t of the way from a to b
+func Lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
@@ -0,0 +1,64 @@
+package anim
+
+// Timeline runs the animations on screen. Blocking animations show something the
+// game has to wait for, like a card on its way to a hand, and play one after
+// another in the order they were queued. The others are only for show and run
+// side by side
+type Timeline struct {
+	shows    []Animation
+	blocking []Animation
+}
+
+// Play starts an animation the game does not wait for
+func (tl *Timeline) Play(a Animation) {
+	tl.shows = append(tl.shows, a)
+}
+
+// Block queues an animation the game waits for, starting once those queued before it end
+func (tl *Timeline) Block(a Animation) {
+	tl.blocking = append(tl.blocking, a)
+}
+
+// Update moves the animations on a tick and drops those that ended. Animations
+// started by callbacks during the update start moving on the next tick
+func (tl *Timeline) Update() {
+	shows := tl.shows
+	tl.shows = nil
+	for _, a := range shows {
+		a.Update()
+	}
+	if len(tl.blocking) > 0 {
+		tl.blocking[0].Update()
+		if tl.blocking[0].Done() {
+			tl.blocking = tl.blocking[1:]
+		}
+	}
+
+	// Keep the shows still going, then any started during the update
+	started := tl.shows
+	tl.shows = nil
+	for _, a := range shows {
+		if !a.Done() {
+			tl.shows = append(tl.shows, a)
+		}
+	}
+	tl.shows = append(tl.shows, started...)
+}
+
+// Busy reports whether any animation is running
+func (tl *Timeline) Busy() bool {
+	return len(tl.shows) > 0 || tl.Blocking()
+}
+
+// Blocking reports whether an animation the game waits for is running or queued
+func (tl *Timeline) Blocking() bool {
+	return len(tl.blocking) > 0
+}
+
+// Finish runs every animation to its end at once, callbacks included, for when
+// the table changes too much to animate
+func (tl *Timeline) Finish() {
+	for tl.Busy() {
+		tl.Update()
+	}
+}
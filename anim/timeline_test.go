@@ -0,0 +1,73 @@
+package anim
+
+import "testing"
+
+func TestTimeline(t *testing.T) {
+	var tl Timeline
+	if tl.Busy() || tl.Blocking() {
+		t.Fatal("Expected an empty timeline to be idle")
+	}
+
+	tl.Play(NewTween(5, Linear, nil))
+	tl.Block(NewTween(2, Linear, nil))
+	if !tl.Busy() || !tl.Blocking() {
+		t.Fatal("Expected both animations running")
+	}
+
+	tl.Update()
+	tl.Update()
+	if tl.Blocking() || !tl.Busy() {
+		t.Error("Expected only the animation for show left")
+	}
+	for range 3 {
+		tl.Update()
+	}
+	if tl.Busy() {
+		t.Error("Expected every animation to end")
+	}
+}
+
+func TestTimelineQueue(t *testing.T) {
+	var tl Timeline
+	var order []string
+	tl.Block(NewTween(3, Linear, func(float64) { order = append(order, "first") }))
+	tl.Block(NewTween(2, Linear, func(float64) { order = append(order, "second") }))
+
+	for tl.Blocking() {
+		tl.Update()
+	}
+	if len(order) != 5 || order[2] != "first" || order[3] != "second" {
+		t.Errorf("Expected the second animation to wait for the first, got %v", order)
+	}
+}
+
+func TestTimelineStartFromCallback(t *testing.T) {
+	var tl Timeline
+	moved := 0
+	tl.Block(Call(func() {
+		tl.Block(NewTween(2, Linear, func(float64) { moved++ }))
+	}))
+
+	tl.Update()
+	if moved != 0 || !tl.Blocking() {
+		t.Errorf("Expected the new animation to wait for the next tick, moved %d times", moved)
+	}
+	tl.Update()
+	tl.Update()
+	if moved != 2 || tl.Busy() {
+		t.Errorf("Expected the new animation to run through, moved %d times", moved)
+	}
+}
+
+func TestTimelineFinish(t *testing.T) {
+	var tl Timeline
+	landed := 0
+	for range 3 {
+		tl.Block(Sequence(Wait(10), Call(func() { landed++ })))
+	}
+
+	tl.Finish()
+	if landed != 3 || tl.Busy() {
+		t.Errorf("Expected every callback to run, got %d", landed)
+	}
+}
@@ -0,0 +1,309 @@
+// Package arena runs tournaments between bots and rates them, to tell whether a
+// new bot is actually an improvement
+package arena
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+	"github.com/vtigo/uno-clone/sim"
+)
+
+// Format is the way bots are paired
+type Format string
+
+const (
+	RoundRobin Format = "round-robin" // Everyone meets everyone once
+	Swiss      Format = "swiss"       // Bots with similar scores meet, for a set number of rounds
+)
+
+// Config describes a tournament. Bots meet head to head, every pairing plays its
+// deals twice with the seats swapped so both bots get both hands of every deal
+type Config struct {
+	Bots     []string // Bot names, see bot.Names
+	Format   Format
+	Rounds   int              // Swiss rounds, 0 for enough to tell the bots apart
+	Deals    int              // Deals per pairing, 0 for 1
+	Options  game.GameOptions // House rules for every game
+	Seed     uint64           // Deal i of the tournament is dealt with seed Seed+i
+	Workers  int              // Games played at once, 0 for one per CPU core
+	MaxMoves int              // Moves after which a game counts as unfinished, 0 for 10000
+
+	// NewBot creates the bot for a name, bot.ByName when nil
+	NewBot func(name string, seed uint64) (bot.Bot, error)
+}
+
+// pairing is two bots meeting in a round
+type pairing struct {
+	a, b string
+}
+
+// Run plays the tournament and returns its results
+// Results only depend on the seed, not on the number of workers
+func Run(cfg Config) (*Results, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	results := &Results{Format: cfg.Format, Bots: cfg.Bots, Options: cfg.Options, Seed: cfg.Seed}
+
+	rounds := cfg.Rounds
+	switch {
+	case cfg.Format == RoundRobin:
+		rounds = len(cfg.Bots) - 1 + len(cfg.Bots)%2
+	case rounds <= 0:
+		rounds = int(math.Ceil(math.Log2(float64(len(cfg.Bots))))) + 1
+	}
+
+	deal := uint64(0)
+	for round := range rounds {
+		var pairings []pairing
+		var bye string
+		if cfg.Format == RoundRobin {
+			pairings, bye = roundRobinRound(cfg.Bots, round)
+		} else {
+			pairings, bye = swissRound(results)
+		}
+
+		if bye != "" {
+			results.Byes = append(results.Byes, Bye{Round: round, Bot: bye})
+		}
+
+		games, err := cfg.playRound(round, pairings, deal)
+		if err != nil {
+			return nil, err
+		}
+		results.Games = append(results.Games, games...)
+		deal += uint64(len(pairings) * cfg.deals())
+	}
+
+	return results, nil
+}
+
+func (cfg Config) validate() error {
+	if len(cfg.Bots) < 2 {
+		return errors.New("a tournament needs at least two bots")
+	}
+
+	if cfg.Format != RoundRobin && cfg.Format != Swiss {
+		return fmt.Errorf("unknown format %q, expected %s or %s", cfg.Format, RoundRobin, Swiss)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range cfg.Bots {
+		if seen[name] {
+			return fmt.Errorf("bot %s is entered twice", name)
+		}
+		seen[name] = true
+
+		b, err := cfg.newBot(name, 0)
+		if err != nil {
+			return err
+		}
+		if closer, ok := b.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+	return nil
+}
+
+func (cfg Config) deals() int {
+	if cfg.Deals <= 0 {
+		return 1
+	}
+	return cfg.Deals
+}
+
+func (cfg Config) newBot(name string, seed uint64) (bot.Bot, error) {
+	if cfg.NewBot != nil {
+		return cfg.NewBot(name, seed)
+	}
+	return bot.ByName(name, seed)
+}
+
+// simConfig returns the simulation settings for one pairing
+func (cfg Config) simConfig(p pairing) sim.Config {
+	return sim.Config{Bots: []string{p.a, p.b}, Options: cfg.Options, MaxMoves: cfg.MaxMoves, NewBot: cfg.newBot}
+}
+
+// playRound plays every deal of every pairing twice, the first deal numbered first
+func (cfg Config) playRound(round int, pairings []pairing, first uint64) ([]Game, error) {
+	deals := cfg.deals()
+	games := make([]Game, 2*deals*len(pairings))
+	errs := make([]error, len(games))
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				games[i], errs[i] = cfg.playGame(round, pairings[i/(2*deals)], first+uint64(i/2), i%2)
+			}
+		}()
+	}
+
+	for i := range games {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("round %d, game %d: %v", round+1, i+1, err)
+		}
+	}
+	return games, nil
+}
+
+// playGame plays one deal of a pairing, the bots moved shift seats along
+func (cfg Config) playGame(round int, p pairing, deal uint64, shift int) (Game, error) {
+	seed := cfg.Seed + deal
+	result, err := sim.PlayDeal(cfg.simConfig(p), seed, shift)
+	if err != nil {
+		return Game{}, err
+	}
+
+	g := Game{Round: round, Seed: seed, Moves: result.Moves, Bots: [2]string{p.a, p.b}}
+	if shift%2 == 1 {
+		g.Bots = [2]string{p.b, p.a}
+	}
+	if len(result.Winners) == 1 {
+		g.Winner = []string{p.a, p.b}[result.Winners[0]]
+	}
+	return g, nil
+}
+
+// roundRobinRound pairs the bots for one round of the circle method: the first
+// bot stays put while the others rotate around it. With an odd number of bots
+// the one drawn against the empty seat gets a bye
+func roundRobinRound(bots []string, round int) ([]pairing, string) {
+	circle := append([]string(nil), bots...)
+	if len(circle)%2 == 1 {
+		circle = append(circle, "")
+	}
+
+	n := len(circle)
+	rotated := make([]string, n)
+	rotated[0] = circle[0]
+	for i := 1; i < n; i++ {
+		rotated[i] = circle[1+(i-1+round)%(n-1)]
+	}
+
+	var pairings []pairing
+	bye := ""
+	for i := range n / 2 {
+		a, b := rotated[i], rotated[n-1-i]
+		switch {
+		case a == "":
+			bye = b
+		case b == "":
+			bye = a
+		default:
+			pairings = append(pairings, pairing{a, b})
+		}
+	}
+	return pairings, bye
+}
+
+// swissRound pairs bots with similar match points, avoiding rematches where
+// possible. A pairing is won by taking more of its games and a bye counts as a
+// win. With an odd number of bots the lowest ranked bot without a bye sits out
+func swissRound(results *Results) ([]pairing, string) {
+	// Sum up every pairing of every round from both sides
+	type match struct {
+		round int
+		bot   string
+		other string
+	}
+	scored := make(map[match]float64)
+	met := make(map[pairing]bool)
+	for _, g := range results.Games {
+		a, b := g.Bots[0], g.Bots[1]
+		scored[match{g.Round, a, b}] += scoreOf(g, a)
+		scored[match{g.Round, b, a}] += scoreOf(g, b)
+		met[pairing{a, b}] = true
+		met[pairing{b, a}] = true
+	}
+
+	points := make(map[string]float64)
+	for m, score := range scored {
+		other := scored[match{m.round, m.other, m.bot}]
+		switch {
+		case score > other:
+			points[m.bot]++
+		case score == other:
+			points[m.bot] += 0.5
+		}
+	}
+
+	hadBye := make(map[string]bool)
+	for _, bye := range results.Byes {
+		points[bye.Bot]++
+		hadBye[bye.Bot] = true
+	}
+
+	// Rank by points, then by rating
+	ratings := results.Ratings()
+	ranked := append([]string(nil), results.Bots...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if points[ranked[i]] != points[ranked[j]] {
+			return points[ranked[i]] > points[ranked[j]]
+		}
+		return ratings[ranked[i]].Rating > ratings[ranked[j]].Rating
+	})
+
+	bye := ""
+	if len(ranked)%2 == 1 {
+		at := len(ranked) - 1
+		for i := len(ranked) - 1; i >= 0; i-- {
+			if !hadBye[ranked[i]] {
+				at = i
+				break
+			}
+		}
+		bye = ranked[at]
+		ranked = append(ranked[:at], ranked[at+1:]...)
+	}
+
+	var pairings []pairing
+	paired := make([]bool, len(ranked))
+	for i := range ranked {
+		if paired[i] {
+			continue
+		}
+
+		// The closest ranked bot not met yet, or simply the closest
+		opponent := -1
+		for j := i + 1; j < len(ranked); j++ {
+			if paired[j] {
+				continue
+			}
+			if opponent == -1 {
+				opponent = j
+			}
+			if !met[pairing{ranked[i], ranked[j]}] {
+				opponent = j
+				break
+			}
+		}
+
+		paired[i], paired[opponent] = true, true
+		pairings = append(pairings, pairing{ranked[i], ranked[opponent]})
+	}
+	return pairings, bye
+}
@@ -0,0 +1,123 @@
+package arena
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vtigo/uno-clone/bot"
+)
+
+// aliases lets one bot enter a tournament several times as "greedy-2" and so on
+func aliases(name string, seed uint64) (bot.Bot, error) {
+	base, _, _ := strings.Cut(name, "-")
+	return bot.ByName(base, seed)
+}
+
+func TestRoundRobin(t *testing.T) {
+	cfg := Config{Bots: []string{"greedy", "random", "greedy-2"}, Format: RoundRobin, Deals: 3, Seed: 1, NewBot: aliases}
+
+	results, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error running the tournament, got %v", err)
+	}
+
+	// Three bots play three rounds, each sitting one out
+	if len(results.Games) != 3*2*3 || len(results.Byes) != 3 {
+		t.Fatalf("Expected 18 games and 3 byes, got %d and %d", len(results.Games), len(results.Byes))
+	}
+
+	met := make(map[[2]string]int)
+	seeds := make(map[uint64][]Game)
+	for _, g := range results.Games {
+		pair := g.Bots
+		if pair[0] > pair[1] {
+			pair[0], pair[1] = pair[1], pair[0]
+		}
+		met[pair]++
+		seeds[g.Seed] = append(seeds[g.Seed], g)
+	}
+	if len(met) != 3 {
+		t.Errorf("Expected every pair to meet, got %v", met)
+	}
+
+	// Every deal is played once from each side
+	for seed, games := range seeds {
+		if len(games) != 2 || games[0].Bots[0] != games[1].Bots[1] || games[0].Bots[1] != games[1].Bots[0] {
+			t.Errorf("Expected deal %d to be played with swapped seats, got %v", seed, games)
+		}
+	}
+
+	// The same seed gives the same tournament whatever the number of workers
+	cfg.Workers = 1
+	again, err := Run(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(results, again) {
+		t.Error("Expected the same results with one worker")
+	}
+}
+
+func TestSwiss(t *testing.T) {
+	cfg := Config{
+		Bots:   []string{"greedy", "random", "greedy-2", "random-2", "greedy-3"},
+		Format: Swiss,
+		Rounds: 4,
+		Deals:  2,
+		Seed:   5,
+		NewBot: aliases,
+	}
+
+	results, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error running the tournament, got %v", err)
+	}
+
+	byes := make(map[string]bool)
+	for _, bye := range results.Byes {
+		if byes[bye.Bot] {
+			t.Errorf("Expected no bot to sit out twice, got %v", results.Byes)
+		}
+		byes[bye.Bot] = true
+	}
+	if len(results.Byes) != 4 {
+		t.Errorf("Expected a bye every round, got %v", results.Byes)
+	}
+
+	// Nobody plays twice in a round
+	for round := range 4 {
+		seen := make(map[string]int)
+		for _, g := range results.Games {
+			if g.Round == round {
+				seen[g.Bots[0]]++
+				seen[g.Bots[1]]++
+			}
+		}
+		for name, games := range seen {
+			if games != 2*cfg.Deals {
+				t.Errorf("Expected %s to play %d games in round %d, got %d", name, 2*cfg.Deals, round, games)
+			}
+		}
+	}
+
+	board := results.Leaderboard()
+	if strings.HasPrefix(board[len(board)-1].Bot, "greedy") {
+		t.Errorf("Expected a random bot at the bottom, got %+v", board)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	configs := map[string]Config{
+		"a single bot":   {Bots: []string{"greedy"}, Format: RoundRobin},
+		"a twin entry":   {Bots: []string{"greedy", "greedy"}, Format: RoundRobin},
+		"an unknown bot": {Bots: []string{"greedy", "cheater"}, Format: RoundRobin},
+		"no format":      {Bots: []string{"greedy", "random"}},
+	}
+
+	for name, cfg := range configs {
+		if _, err := Run(cfg); err == nil {
+			t.Errorf("Expected error for %s", name)
+		}
+	}
+}
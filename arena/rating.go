@@ -0,0 +1,64 @@
+package arena
+
+import "math"
+
+// Glicko-1 ratings: every bot has a rating and a rating deviation, the uncertainty
+// of the rating. Games of one round are rated together as one rating period
+// Bots do not change between games, so unlike people their deviation never grows back
+const (
+	initialRating    = 1500
+	initialDeviation = 350
+	minDeviation     = 30
+)
+
+var glickoQ = math.Ln10 / 400
+
+// Rating is a bot's Glicko rating
+type Rating struct {
+	Rating    float64
+	Deviation float64
+}
+
+// Interval returns the bounds of the 95% confidence interval of the rating
+func (r Rating) Interval() (float64, float64) {
+	return r.Rating - 1.96*r.Deviation, r.Rating + 1.96*r.Deviation
+}
+
+// outcome is one game from one bot's side: the opponent's rating before the
+// period and the score, 1 for a win, 0.5 for an unfinished game and 0 for a loss
+type outcome struct {
+	opponent Rating
+	score    float64
+}
+
+// g dampens the weight of a result by the uncertainty of the opponent's rating
+func g(deviation float64) float64 {
+	return 1 / math.Sqrt(1+3*glickoQ*glickoQ*deviation*deviation/(math.Pi*math.Pi))
+}
+
+// expected returns the expected score of a rating against an opponent
+func expected(r, opponent Rating) float64 {
+	return 1 / (1 + math.Pow(10, -g(opponent.Deviation)*(r.Rating-opponent.Rating)/400))
+}
+
+// update rates one period of games
+func (r Rating) update(outcomes []outcome) Rating {
+	if len(outcomes) == 0 {
+		return r
+	}
+
+	variance, change := 0.0, 0.0
+	for _, o := range outcomes {
+		weight := g(o.opponent.Deviation)
+		e := expected(r, o.opponent)
+		variance += weight * weight * e * (1 - e)
+		change += weight * (o.score - e)
+	}
+	dSquared := 1 / (glickoQ * glickoQ * variance)
+
+	precision := 1/(r.Deviation*r.Deviation) + 1/dSquared
+	return Rating{
+		Rating:    r.Rating + glickoQ/precision*change,
+		Deviation: math.Max(math.Sqrt(1/precision), minDeviation),
+	}
+}
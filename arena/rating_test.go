@@ -0,0 +1,29 @@
+package arena
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGlicko(t *testing.T) {
+	// The worked example from Glickman's paper on the Glicko system
+	player := Rating{Rating: 1500, Deviation: 200}
+	updated := player.update([]outcome{
+		{opponent: Rating{Rating: 1400, Deviation: 30}, score: 1},
+		{opponent: Rating{Rating: 1550, Deviation: 100}, score: 0},
+		{opponent: Rating{Rating: 1700, Deviation: 300}, score: 0},
+	})
+
+	if math.Abs(updated.Rating-1464) > 1 || math.Abs(updated.Deviation-151.4) > 1 {
+		t.Errorf("Expected a rating of 1464 with a deviation of 151.4, got %+v", updated)
+	}
+
+	if same := player.update(nil); same != player {
+		t.Errorf("Expected no change without games, got %+v", same)
+	}
+
+	low, high := updated.Interval()
+	if low >= updated.Rating || high <= updated.Rating || math.Abs(high-low-2*1.96*updated.Deviation) > 1e-9 {
+		t.Errorf("Expected an interval around the rating, got %f to %f", low, high)
+	}
+}
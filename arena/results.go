@@ -0,0 +1,181 @@
+package arena
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Game is the record of one tournament game
+type Game struct {
+	Round  int       `json:"round"`
+	Bots   [2]string `json:"bots"` // The bot at each seat
+	Seed   uint64    `json:"seed"`
+	Winner string    `json:"winner,omitempty"` // Empty if the game did not finish
+	Moves  int       `json:"moves"`
+}
+
+// Results is everything a tournament played, saved as JSON between runs
+type Results struct {
+	Format  Format           `json:"format"`
+	Bots    []string         `json:"bots"`
+	Options game.GameOptions `json:"options"`
+	Seed    uint64           `json:"seed"`
+	Games   []Game           `json:"games"`
+	Byes    []Bye            `json:"byes,omitempty"`
+}
+
+// Bye is a round a bot sat out for lack of an opponent
+type Bye struct {
+	Round int    `json:"round"`
+	Bot   string `json:"bot"`
+}
+
+// Standing is a bot's line on the leaderboard
+type Standing struct {
+	Bot                 string
+	Rating              Rating
+	Wins, Draws, Losses int
+}
+
+// Games returns the number of games the bot played
+func (s Standing) Games() int {
+	return s.Wins + s.Draws + s.Losses
+}
+
+// Score returns the share of points the bot took, unfinished games count half
+func (s Standing) Score() float64 {
+	if s.Games() == 0 {
+		return 0
+	}
+	return (float64(s.Wins) + float64(s.Draws)/2) / float64(s.Games())
+}
+
+// Save writes the results to a JSON file
+func (r *Results) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Load reads results saved with Save
+func Load(path string) (*Results, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var results Results
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse results file %s: %v", path, err)
+	}
+	return &results, nil
+}
+
+// Ratings rates every bot from the games, one rating period per round
+func (r *Results) Ratings() map[string]Rating {
+	ratings := make(map[string]Rating, len(r.Bots))
+	for _, name := range r.Bots {
+		ratings[name] = Rating{Rating: initialRating, Deviation: initialDeviation}
+	}
+
+	rounds := make(map[int][]Game)
+	order := make([]int, 0)
+	for _, g := range r.Games {
+		if _, ok := rounds[g.Round]; !ok {
+			order = append(order, g.Round)
+		}
+		rounds[g.Round] = append(rounds[g.Round], g)
+	}
+	sort.Ints(order)
+
+	for _, round := range order {
+		outcomes := make(map[string][]outcome)
+		for _, g := range rounds[round] {
+			for seat, name := range g.Bots {
+				opponent := g.Bots[1-seat]
+				outcomes[name] = append(outcomes[name], outcome{opponent: ratings[opponent], score: scoreOf(g, name)})
+			}
+		}
+
+		// Everyone is rated against the ratings from before the round
+		updated := make(map[string]Rating, len(ratings))
+		for name, rating := range ratings {
+			updated[name] = rating.update(outcomes[name])
+		}
+		ratings = updated
+	}
+
+	return ratings
+}
+
+// Leaderboard returns every bot's standing, best rating first
+func (r *Results) Leaderboard() []Standing {
+	ratings := r.Ratings()
+
+	standings := make(map[string]*Standing, len(r.Bots))
+	for _, name := range r.Bots {
+		standings[name] = &Standing{Bot: name, Rating: ratings[name]}
+	}
+
+	for _, g := range r.Games {
+		for _, name := range g.Bots {
+			standing, ok := standings[name]
+			if !ok {
+				continue
+			}
+
+			switch scoreOf(g, name) {
+			case 1:
+				standing.Wins++
+			case 0:
+				standing.Losses++
+			default:
+				standing.Draws++
+			}
+		}
+	}
+
+	board := make([]Standing, 0, len(standings))
+	for _, name := range r.Bots {
+		board = append(board, *standings[name])
+	}
+	sort.SliceStable(board, func(i, j int) bool {
+		return board[i].Rating.Rating > board[j].Rating.Rating
+	})
+	return board
+}
+
+// Print writes the leaderboard
+func (r *Results) Print(w io.Writer) {
+	fmt.Fprintf(w, "%s tournament, %d games\n\n", r.Format, len(r.Games))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tBot\tRating\t95% interval\tGames\tW-D-L\tScore")
+	for i, standing := range r.Leaderboard() {
+		low, high := standing.Rating.Interval()
+		fmt.Fprintf(tw, "%d\t%s\t%.0f\t%.0f - %.0f\t%d\t%d-%d-%d\t%.1f%%\n",
+			i+1, standing.Bot, standing.Rating.Rating, low, high, standing.Games(),
+			standing.Wins, standing.Draws, standing.Losses, 100*standing.Score())
+	}
+	tw.Flush()
+}
+
+// scoreOf returns the bot's points from a game it played
+func scoreOf(g Game, name string) float64 {
+	switch g.Winner {
+	case "":
+		return 0.5
+	case name:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,55 @@
+package arena
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResults(t *testing.T) {
+	results := &Results{
+		Format: RoundRobin,
+		Bots:   []string{"greedy", "random"},
+		Games: []Game{
+			{Round: 0, Bots: [2]string{"greedy", "random"}, Seed: 1, Winner: "greedy"},
+			{Round: 0, Bots: [2]string{"random", "greedy"}, Seed: 1, Winner: "greedy"},
+			{Round: 1, Bots: [2]string{"greedy", "random"}, Seed: 2},
+		},
+	}
+
+	board := results.Leaderboard()
+	if board[0].Bot != "greedy" || board[0].Wins != 2 || board[0].Draws != 1 || board[0].Losses != 0 {
+		t.Errorf("Expected greedy on top with 2 wins and a draw, got %+v", board[0])
+	}
+	if board[1].Losses != 2 || board[1].Score() != 0.5/3 {
+		t.Errorf("Expected random with 2 losses, got %+v", board[1])
+	}
+	if board[0].Rating.Deviation >= initialDeviation {
+		t.Errorf("Expected games to make the rating more certain, got %+v", board[0].Rating)
+	}
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := results.Save(path); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error loading, got %v", err)
+	}
+	if !reflect.DeepEqual(results, loaded) {
+		t.Errorf("Expected the saved results back, got %+v", loaded)
+	}
+
+	var out bytes.Buffer
+	loaded.Print(&out)
+	if !strings.Contains(out.String(), "1  greedy") || !strings.Contains(out.String(), "2-1-0") {
+		t.Errorf("Expected greedy to lead the leaderboard, got:\n%s", out.String())
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+}
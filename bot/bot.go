@@ -0,0 +1,144 @@
+// Package bot provides computer players that choose moves from a redacted player view
+package bot
+
+import (
+	"fmt"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Bot chooses moves for a computer controlled seat
+// ChooseMove is only called when the view lists at least one legal move,
+// and must return one of them
+type Bot interface {
+	Name() string
+	ChooseMove(view game.PlayerView) game.Move
+}
+
+// Observer is implemented by bots that want to follow the public events of the game
+// Tables subscribe observing bots to their rules
+type Observer interface {
+	Observe(event game.Event)
+}
+
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+	Expert
+)
+
+func (d Difficulty) String() string {
+	switch d {
+	case Easy:
+		return "Easy"
+	case Medium:
+		return "Medium"
+	case Hard:
+		return "Hard"
+	case Expert:
+		return "Expert"
+	default:
+		return "Unknown"
+	}
+}
+
+// New creates the bot matching a difficulty level
+// The seed makes the bot's random choices reproducible. Each level wins most of
+// its games against the one below it, which is why greedy ranks above lookahead
+func New(difficulty Difficulty, seed uint64) Bot {
+	switch difficulty {
+	case Easy:
+		return NewRandomBot(seed)
+	case Medium:
+		return NewLookaheadBot()
+	case Hard:
+		return NewGreedyBot()
+	default:
+		return NewISMCTSBot(seed)
+	}
+}
+
+// Names lists the names ByName accepts, from weakest to strongest
+var Names = []string{"random", "lookahead", "greedy", "ismcts"}
+
+// ByName creates the bot whose Name matches the given name
+func ByName(name string, seed uint64) (Bot, error) {
+	for difficulty, known := range Names {
+		if name == known {
+			return New(Difficulty(difficulty), seed), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown bot %q, expected one of %v", name, Names)
+}
+
+// urgentMove returns an UNO call or challenge if one is available
+// Both are always worth making, so the non-random bots take them first
+func urgentMove(view game.PlayerView) (game.Move, bool) {
+	for _, move := range view.LegalMoves {
+		if move.Kind == game.MoveCallUno || move.Kind == game.MoveChallenge {
+			return move, true
+		}
+	}
+	return game.Move{}, false
+}
+
+// swapsWithPartner checks if a move gives the bot's hand to its own teammate with
+// Wild Swap Hands. Every such move has a twin aimed at an opponent, so leaving
+// them out never leaves a bot without a move
+func swapsWithPartner(view game.PlayerView, move game.Move) bool {
+	if view.Teams == nil || move.Target == view.Seat || view.Teams[move.Target] != view.Teams[view.Seat] {
+		return false
+	}
+
+	switch move.Kind {
+	case game.MovePlay:
+		return view.MyHand()[move.CardIndex].Type == game.WildSwapHands
+	case game.MoveChooseColor:
+		// A starting Wild Swap Hands only sets the color
+		return view.TopCard.Type == game.WildSwapHands && view.LastPlayedBy != -1
+	default:
+		return false
+	}
+}
+
+// mostCommonColor returns the color the hand holds the most cards of, skipping one hand index
+// Wild cards don't count, and Red is returned for a hand without colored cards
+func mostCommonColor(hand []game.Card, skip int) game.CardColor {
+	counts := make([]int, game.Wild)
+	for i, card := range hand {
+		if i != skip && card.Color != game.Wild {
+			counts[card.Color]++
+		}
+	}
+
+	best := game.Red
+	for color := game.Red; color <= game.Yellow; color++ {
+		if counts[color] > counts[best] {
+			best = color
+		}
+	}
+	return best
+}
+
+// opponentThreat checks if any opponent is close to going out
+func opponentThreat(view game.PlayerView) bool {
+	for _, seat := range view.Opponents() {
+		if view.HandSizes[seat] <= 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// isAttack checks if a card hurts or skips the next player
+func isAttack(card game.Card) bool {
+	switch card.Type {
+	case game.Skip, game.Reverse, game.DrawTwo, game.WildDrawFour:
+		return true
+	default:
+		return false
+	}
+}
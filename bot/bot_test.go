@@ -0,0 +1,251 @@
+package bot
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Helper function to build a two player state where the first player holds the given hand
+func createTestState(hand []game.Card, top game.Card) *game.GameState {
+	players := []*game.Player{game.NewPlayer("Bot"), game.NewPlayer("Opponent")}
+	players[0].AddCardsToHand(hand)
+	players[0].IsMyTurn = true
+	players[1].AddCardsToHand([]game.Card{
+		{Color: game.Yellow, Type: game.Number, Value: 1},
+		{Color: game.Yellow, Type: game.Number, Value: 2},
+		{Color: game.Yellow, Type: game.Number, Value: 3},
+	})
+
+	return &game.GameState{
+		Players:      players,
+		DrawPile:     game.NewDeck(),
+		DiscardPile:  game.CreateDiscardPile(top),
+		ActiveColor:  top.Color,
+		Phase:        game.PhasePlay,
+		LastPlayedBy: -1,
+	}
+}
+
+func TestMostCommonColor(t *testing.T) {
+	hand := []game.Card{
+		{Color: game.Blue, Type: game.Number, Value: 1},
+		{Color: game.Green, Type: game.Number, Value: 2},
+		{Color: game.Green, Type: game.Skip},
+		{Color: game.Wild, Type: game.WildCard},
+	}
+
+	if color := mostCommonColor(hand, -1); color != game.Green {
+		t.Errorf("Expected Green, got %v", color)
+	}
+
+	// Skipping a card changes the count
+	if color := mostCommonColor(hand[1:], 0); color != game.Green {
+		t.Errorf("Expected Green after skipping one, got %v", color)
+	}
+
+	if color := mostCommonColor([]game.Card{{Color: game.Wild, Type: game.WildCard}}, -1); color != game.Red {
+		t.Errorf("Expected Red for a hand of wilds, got %v", color)
+	}
+}
+
+func TestRandomBotChoosesLegalMoves(t *testing.T) {
+	state := createTestState([]game.Card{
+		{Color: game.Red, Type: game.Number, Value: 7},
+		{Color: game.Wild, Type: game.WildCard},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+
+	b := NewRandomBot(42)
+	view := state.View(0)
+	for range 50 {
+		move := b.ChooseMove(view)
+		if !game.IsLegalMove(state, move) {
+			t.Fatalf("Expected a legal move, got %v", move)
+		}
+	}
+
+	// The same seed gives the same choices
+	first, second := NewRandomBot(7), NewRandomBot(7)
+	for range 20 {
+		if first.ChooseMove(view) != second.ChooseMove(view) {
+			t.Fatal("Expected bots with the same seed to choose the same moves")
+		}
+	}
+}
+
+func TestGreedyBot(t *testing.T) {
+	b := NewGreedyBot()
+
+	// Dumps the high value card and holds the wild
+	state := createTestState([]game.Card{
+		{Color: game.Red, Type: game.Number, Value: 2},
+		{Color: game.Wild, Type: game.WildCard},
+		{Color: game.Red, Type: game.DrawTwo},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+
+	move := b.ChooseMove(state.View(0))
+	if move.Kind != game.MovePlay || move.CardIndex != 2 {
+		t.Errorf("Expected the Draw Two to be played, got %v", move)
+	}
+
+	// Plays the wild only when nothing else fits, naming the most common color
+	state = createTestState([]game.Card{
+		{Color: game.Green, Type: game.Number, Value: 2},
+		{Color: game.Wild, Type: game.WildCard},
+		{Color: game.Green, Type: game.Number, Value: 8},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+
+	move = b.ChooseMove(state.View(0))
+	if move.Kind != game.MovePlay || move.CardIndex != 1 || move.Color != game.Green {
+		t.Errorf("Expected the Wild to be played as Green, got %v", move)
+	}
+
+	// Draws when nothing can be played
+	state = createTestState([]game.Card{
+		{Color: game.Blue, Type: game.Number, Value: 2},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+
+	if move := b.ChooseMove(state.View(0)); move.Kind != game.MoveDraw {
+		t.Errorf("Expected a draw, got %v", move)
+	}
+}
+
+func TestBotsCallUnoAndChallenge(t *testing.T) {
+	state := createTestState([]game.Card{
+		{Color: game.Red, Type: game.Number, Value: 2},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+	state.Players[1].Hand = game.NewHand(game.Card{Color: game.Blue, Type: game.Number, Value: 4})
+
+	// Both players have one card left, neither called UNO yet
+	state.DrawPile = game.NewDeck()
+	rules := game.NewGameRules()
+	state.Players[0].AddCard(game.Card{Color: game.Red, Type: game.Number, Value: 3})
+	if err := rules.HandlePlayCard(state.Players[0], 1, state, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, b := range []Bot{NewGreedyBot(), NewLookaheadBot()} {
+		move := b.ChooseMove(state.View(0))
+		if move.Kind != game.MoveCallUno && move.Kind != game.MoveChallenge {
+			t.Errorf("Expected %s bot to call UNO or challenge first, got %v", b.Name(), move)
+		}
+	}
+}
+
+func TestLookaheadBotFindsWinningChain(t *testing.T) {
+	// Only Draw Two, then Red Skip, then Blue Skip empties the hand
+	state := createTestState([]game.Card{
+		{Color: game.Red, Type: game.Skip},
+		{Color: game.Red, Type: game.DrawTwo},
+		{Color: game.Blue, Type: game.Skip},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+
+	move := NewLookaheadBot().ChooseMove(state.View(0))
+	if move.Kind != game.MovePlay || move.CardIndex != 1 {
+		t.Errorf("Expected the Draw Two to be played first, got %v", move)
+	}
+}
+
+func TestNewDifficulty(t *testing.T) {
+	expected := map[Difficulty]string{Easy: "random", Medium: "lookahead", Hard: "greedy", Expert: "ismcts"}
+
+	for difficulty, name := range expected {
+		if b := New(difficulty, 1); b.Name() != name {
+			t.Errorf("Expected %v to create the %s bot, got %s", difficulty, name, b.Name())
+		}
+	}
+}
+
+// playMatch plays seeded two player games between two difficulties, each going
+// first in half of them, and returns how many the first one won
+func playMatch(t *testing.T, difficulty, against Difficulty, games int) int {
+	wins := 0
+	for i := range games {
+		seed := uint64(i + 1)
+		seats := []Bot{New(difficulty, 2*seed), New(against, 2*seed+1)}
+		mine := i % 2
+		if mine == 1 {
+			seats[0], seats[1] = seats[1], seats[0]
+		}
+
+		players := []*game.Player{game.NewPlayer("A"), game.NewPlayer("B")}
+		state, err := game.NewGameStateWithRandom(players, game.GameOptions{}, rand.New(rand.NewPCG(seed, seed)))
+		if err != nil {
+			t.Fatalf("Expected no error creating game, got %v", err)
+		}
+		table, err := NewTable(state, game.NewGameRules(), seats)
+		if err != nil {
+			t.Fatalf("Expected no error creating table, got %v", err)
+		}
+		if _, err := table.Run(10000); err != nil {
+			t.Fatalf("Expected no error playing %v against %v, got %v", difficulty, against, err)
+		}
+		if state.Players[mine].HasWon() {
+			wins++
+		}
+	}
+	return wins
+}
+
+// Every difficulty wins most of its games against the one below it
+func TestDifficultyLadder(t *testing.T) {
+	for difficulty := Medium; difficulty <= Expert; difficulty++ {
+		// The search takes milliseconds a move, fewer games keep the test quick
+		games := 400
+		if difficulty == Expert {
+			if debugChecks {
+				continue
+			}
+			games = 30
+		}
+
+		if wins := playMatch(t, difficulty, difficulty-1, games); wins <= games/2 {
+			t.Errorf("Expected %v to beat %v, won %d of %d games", difficulty, difficulty-1, wins, games)
+		}
+	}
+}
+
+func TestByName(t *testing.T) {
+	for _, name := range Names {
+		b, err := ByName(name, 1)
+		if err != nil {
+			t.Errorf("Expected no error creating %s, got %v", name, err)
+			continue
+		}
+		if b.Name() != name {
+			t.Errorf("Expected the %s bot, got %s", name, b.Name())
+		}
+	}
+
+	if _, err := ByName("cheater", 1); err == nil {
+		t.Error("Expected error for an unknown bot name")
+	}
+}
+
+func TestBotsNeverSwapWithPartner(t *testing.T) {
+	// The partner across the table holds the smallest hand
+	for _, phase := range []game.GamePhase{game.PhasePlay, game.PhaseColorSelection} {
+		builder := game.NewBuilder("Bot", "Left", "Partner", "Right").
+			Options(game.GameOptions{Teams: true, SwapHandsCards: 1}).
+			Hand(1, "Y1 Y2 Y3").
+			Hand(2, "G1").
+			Hand(3, "B1 B2 B3 B4")
+		if phase == game.PhasePlay {
+			builder.Hand(0, "WSW R1 R2 R3 R4 R5").Discard("B9")
+		} else {
+			builder.Hand(0, "R1 R2 R3 R4 R5").Discard("B9 WSW").LastPlayedBy(0).Phase(phase)
+		}
+		state, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Expected no error building the state, got %v", err)
+		}
+
+		for _, b := range []Bot{NewGreedyBot(), NewLookaheadBot()} {
+			move := b.ChooseMove(state.View(0))
+			if move.Target != 1 {
+				t.Errorf("Expected %s bot to swap with the smallest opponent hand in phase %d, got %v", b.Name(), phase, move)
+			}
+		}
+	}
+}
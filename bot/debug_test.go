@@ -0,0 +1,7 @@
+//go:build debug
+
+package bot
+
+// debugChecks tells tests the rules validate the state after every operation, which
+// makes searching bots too slow to play many games
+const debugChecks = true
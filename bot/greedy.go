@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"math"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// GreedyBot plays the best looking card right now: it dumps high-value cards,
+// holds wilds for when nothing else fits and picks the most common color in its hand
+type GreedyBot struct{}
+
+func NewGreedyBot() *GreedyBot {
+	return &GreedyBot{}
+}
+
+func (b *GreedyBot) Name() string {
+	return "greedy"
+}
+
+func (b *GreedyBot) ChooseMove(view game.PlayerView) game.Move {
+	if move, ok := urgentMove(view); ok {
+		return move
+	}
+
+	best := view.LegalMoves[0]
+	bestScore := math.MinInt
+	for _, move := range view.LegalMoves {
+		if swapsWithPartner(view, move) {
+			continue
+		}
+		if score := b.score(view, move); score > bestScore {
+			best = move
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// score rates a move, higher is better
+func (b *GreedyBot) score(view game.PlayerView, move game.Move) int {
+	hand := view.MyHand()
+
+	switch move.Kind {
+	case game.MovePlay:
+		card := hand[move.CardIndex]
+		score := card.Points()
+
+		// Hold wilds for when nothing else fits
+		if card.Color == game.Wild {
+			score -= 100
+			if move.Color == mostCommonColor(hand, move.CardIndex) {
+				score += 5
+			}
+		}
+
+		// Slow down an opponent about to go out
+		if opponentThreat(view) && isAttack(card) {
+			score += 50
+		}
+
+		// Take the smallest hand of an opponent when swapping
+		if card.Type == game.WildSwapHands {
+			score -= view.HandSizes[move.Target]
+		}
+		return score
+	case game.MoveChooseColor:
+		score := 0
+		if move.Color == mostCommonColor(hand, -1) {
+			score += 5
+		}
+		if view.TopCard.Type == game.WildSwapHands {
+			score -= view.HandSizes[move.Target]
+		}
+		return score
+	default:
+		// Drawing or passing only when there is nothing to play
+		return -1000
+	}
+}
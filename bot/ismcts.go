@@ -0,0 +1,351 @@
+package bot
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/vtigo/uno-clone/game"
+	"github.com/vtigo/uno-clone/knowledge"
+)
+
+// ISMCTSBot searches with information set Monte Carlo tree search. Every iteration
+// samples the hidden hands and draw order from what the bot has seen, walks one
+// shared tree with UCB, plays a few turns on and credits the moves along the way.
+// The most visited move at the root is played
+type ISMCTSBot struct {
+	Iterations  int           // Maximum iterations per move, 0 for no limit
+	TimeLimit   time.Duration // Maximum thinking time per move, 0 for no limit
+	Exploration float64       // UCB exploration constant
+	MaxPlayout  int           // Moves after which a playout stops and is rated by the hands, see reward
+
+	rng     *rand.Rand
+	rules   *game.GameRules
+	moves   []game.Move // Buffer reused for every move generation during the search
+	keys    []moveKey   // Buffer for the keys of the moves of one tree node
+	tracker *knowledge.Tracker
+}
+
+// NewISMCTSBot creates a search bot with a default budget of 2000 iterations per move
+func NewISMCTSBot(seed uint64) *ISMCTSBot {
+	return &ISMCTSBot{
+		Iterations:  2000,
+		Exploration: 0.7,
+		MaxPlayout:  20,
+		rng:         rand.New(rand.NewPCG(seed, seed^0x2545f4914f6cdd1d)),
+		rules:       game.NewGameRules(),
+		tracker:     knowledge.New(),
+	}
+}
+
+func (b *ISMCTSBot) Name() string {
+	return "ismcts"
+}
+
+// Observe follows the game so hidden hands can be sampled from what the opponents revealed
+func (b *ISMCTSBot) Observe(event game.Event) {
+	b.tracker.Observe(event)
+}
+
+// searchNode is one move in the shared tree. Moves are keyed by card rather than
+// hand index because hidden hands are dealt in a different order every iteration
+type searchNode struct {
+	key      moveKey
+	move     game.Move
+	player   int // Seat that made the move leading to this node
+	parent   *searchNode
+	children []*searchNode
+	visits   float64
+	wins     float64
+	avail    float64 // How often this move was legal when its parent was visited
+}
+
+type moveKey struct {
+	kind   game.MoveKind
+	card   game.Card
+	color  game.CardColor
+	target int
+}
+
+func keyOf(state *game.GameState, move game.Move) moveKey {
+	key := moveKey{kind: move.Kind, color: move.Color, target: move.Target}
+	if move.Kind == game.MovePlay {
+		key.card = state.Players[move.Player].Hand.At(move.CardIndex)
+	}
+	return key
+}
+
+func (b *ISMCTSBot) ChooseMove(view game.PlayerView) game.Move {
+	b.tracker.Update(view)
+
+	if move, ok := urgentMove(view); ok {
+		return move
+	}
+
+	if len(view.LegalMoves) == 1 {
+		return view.LegalMoves[0]
+	}
+
+	iterations := b.Iterations
+	if iterations == 0 && b.TimeLimit == 0 {
+		iterations = 2000
+	}
+	deadline := time.Now().Add(b.TimeLimit)
+
+	root := &searchNode{player: -1}
+	for i := 0; iterations == 0 || i < iterations; i++ {
+		if b.TimeLimit > 0 && time.Now().After(deadline) {
+			break
+		}
+
+		state, err := b.determinize(view)
+		if err != nil {
+			break
+		}
+		state.SetRandom(b.rng)
+		b.iterate(root, state)
+	}
+
+	var best *searchNode
+	for _, child := range root.children {
+		if best == nil || child.visits > best.visits {
+			best = child
+		}
+	}
+
+	// Our own hand is the same in every determinization, so the root move is valid as is
+	if best == nil {
+		return NewGreedyBot().ChooseMove(view)
+	}
+	return best.move
+}
+
+// determinize samples the hidden information, using what the tracker learned from
+// the events when the bot sits at a table and uniformly at random otherwise
+func (b *ISMCTSBot) determinize(view game.PlayerView) (*game.GameState, error) {
+	if state, err := b.tracker.Determinize(b.rng); err == nil {
+		return state, nil
+	}
+	return game.Determinize(view, b.rng)
+}
+
+// iterate runs one selection, expansion, playout and backpropagation pass
+func (b *ISMCTSBot) iterate(root *searchNode, state *game.GameState) {
+	node := root
+
+	for state.Phase != game.PhaseGameOver {
+		callUno(b.rules, state)
+
+		b.moves = turnMoves(b.moves[:0], state)
+		moves := b.moves
+		if len(moves) == 0 {
+			break
+		}
+
+		// Find the children legal in this determinization and the moves not tried yet
+		// A child's own move may point at another hand index here, so keep this one's
+		legal := make([]*searchNode, 0, len(node.children))
+		legalMoves := make([]game.Move, 0, len(node.children))
+		untried := make([]game.Move, 0)
+		b.keys = b.keys[:0]
+		for _, move := range moves {
+			// Duplicate cards in the hand make the same move
+			key := keyOf(state, move)
+			if slices.Contains(b.keys, key) {
+				continue
+			}
+			b.keys = append(b.keys, key)
+
+			found := false
+			for _, child := range node.children {
+				if child.key == key {
+					legal = append(legal, child)
+					legalMoves = append(legalMoves, move)
+					found = true
+					break
+				}
+			}
+			if !found {
+				untried = append(untried, move)
+			}
+		}
+
+		for _, child := range legal {
+			child.avail++
+		}
+
+		if len(untried) > 0 {
+			move := untried[b.rng.IntN(len(untried))]
+			child := &searchNode{key: keyOf(state, move), move: move, player: state.CurrentPlayer, parent: node, avail: 1}
+			node.children = append(node.children, child)
+
+			if b.rules.Apply(state, move) != nil {
+				return
+			}
+			node = child
+			break
+		}
+
+		best := 0
+		bestScore := math.Inf(-1)
+		for i, child := range legal {
+			score := child.wins/child.visits + b.Exploration*math.Sqrt(math.Log(child.avail)/child.visits)
+			if score > bestScore {
+				best = i
+				bestScore = score
+			}
+		}
+
+		if b.rules.Apply(state, legalMoves[best]) != nil {
+			return
+		}
+		node = legal[best]
+	}
+
+	b.playout(state)
+
+	for n := node; n.parent != nil; n = n.parent {
+		n.visits++
+		n.wins += reward(state, n.player)
+	}
+}
+
+// reward rates the end of a playout for the player's team: 1 for a win, 0 for a
+// loss and, for a game cut short, the share of cards held by the closest other
+// team, so holding fewer cards than every other team is worth more than half
+func reward(state *game.GameState, player int) float64 {
+	if team := state.WinningTeam(); team != -1 {
+		if state.TeamOf(player) == team {
+			return 1
+		}
+		return 0
+	}
+
+	mine, theirs := math.MaxInt, math.MaxInt
+	for seat, p := range state.Players {
+		if state.AreTeammates(seat, player) {
+			mine = min(mine, p.HandSize())
+		} else {
+			theirs = min(theirs, p.HandSize())
+		}
+	}
+	if mine+theirs == 0 || theirs == math.MaxInt {
+		return 0.5
+	}
+	return float64(theirs) / float64(mine+theirs)
+}
+
+// playout plays the game on for at most MaxPlayout moves with the light heuristic
+// of playoutMove. Playing to the end takes a hundred moves or more and tells the
+// search little more than how the hands stand a few turns ahead
+func (b *ISMCTSBot) playout(state *game.GameState) {
+	for range b.MaxPlayout {
+		if state.Phase == game.PhaseGameOver {
+			return
+		}
+		callUno(b.rules, state)
+
+		b.moves = turnMoves(b.moves[:0], state)
+		moves := b.moves
+		if len(moves) == 0 {
+			return
+		}
+
+		if b.rules.Apply(state, b.playoutMove(state, moves)) != nil {
+			return
+		}
+	}
+}
+
+// playoutMove picks the playout policy's move from the current player's moves:
+// the colored card worth the most points, ties broken at random, else a wild in
+// the color the hand holds most of, else drawing or passing
+func (b *ISMCTSBot) playoutMove(state *game.GameState, moves []game.Move) game.Move {
+	hand := &state.Players[state.CurrentPlayer].Hand
+
+	best, ties, wilds := -1, 0, 0
+	for i, move := range moves {
+		switch {
+		case move.Kind == game.MoveChooseColor:
+			wilds++
+		case move.Kind != game.MovePlay:
+		case hand.At(move.CardIndex).Color == game.Wild:
+			wilds++
+		case best == -1 || hand.At(move.CardIndex).Points() > hand.At(moves[best].CardIndex).Points():
+			best, ties = i, 1
+		case hand.At(move.CardIndex).Points() == hand.At(moves[best].CardIndex).Points():
+			ties++
+			if b.rng.IntN(ties) == 0 {
+				best = i
+			}
+		}
+	}
+	if best != -1 {
+		return moves[best]
+	}
+
+	if wilds > 0 {
+		// Name the color we hold most of, the card and target are left to chance
+		pick := b.rng.IntN(wilds)
+		var move game.Move
+		for _, m := range moves {
+			if m.Kind == game.MoveChooseColor || m.Kind == game.MovePlay {
+				if pick == 0 {
+					move = m
+					break
+				}
+				pick--
+			}
+		}
+
+		skip := -1
+		if move.Kind == game.MovePlay {
+			skip = move.CardIndex
+		}
+		color := mostCommonColor(hand.Cards(), skip)
+		for _, wild := range moves {
+			if wild.Kind == move.Kind && wild.CardIndex == move.CardIndex && wild.Target == move.Target && wild.Color == color {
+				return wild
+			}
+		}
+		return move
+	}
+
+	return moves[b.rng.IntN(len(moves))]
+}
+
+// callUno has every player who needs to call UNO do so, the search assumes nobody forgets
+func callUno(rules *game.GameRules, state *game.GameState) {
+	for i, player := range state.Players {
+		if player.ShouldCallUno() && !player.HasCalledUno {
+			rules.HandleUnoCall(i, state)
+		}
+	}
+}
+
+// turnMoves appends the current player's moves without UNO calls and challenges to buf
+// Drawing while a card can be played is left out too: it is almost never right, and
+// searching it spreads the iterations over a move that only makes the game longer
+func turnMoves(buf []game.Move, state *game.GameState) []game.Move {
+	moves := game.AppendLegalMoves(buf, state, state.CurrentPlayer)
+	turn := moves[:0]
+	canPlay := false
+	for _, move := range moves {
+		if move.Kind != game.MoveCallUno && move.Kind != game.MoveChallenge {
+			turn = append(turn, move)
+			canPlay = canPlay || move.Kind == game.MovePlay
+		}
+	}
+	if !canPlay {
+		return turn
+	}
+
+	plays := turn[:0]
+	for _, move := range turn {
+		if move.Kind != game.MoveDraw {
+			plays = append(plays, move)
+		}
+	}
+	return plays
+}
@@ -0,0 +1,112 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+func TestISMCTSBotFindsWinningChain(t *testing.T) {
+	// Only Draw Two, then Red Skip, then Blue Skip empties the hand
+	state := createTestState([]game.Card{
+		{Color: game.Red, Type: game.Skip},
+		{Color: game.Red, Type: game.DrawTwo},
+		{Color: game.Blue, Type: game.Skip},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+
+	// Account for every card so the view is consistent with the deck
+	state.DrawPile = game.NewDeck()
+	removeCards(state.DrawPile, state)
+
+	b := NewISMCTSBot(1)
+	b.Iterations = 300
+
+	move := b.ChooseMove(state.View(0))
+	if move.Kind != game.MovePlay || move.CardIndex != 1 {
+		t.Errorf("Expected the Draw Two to be played first, got %v", move)
+	}
+}
+
+func TestISMCTSBotPlaysFullGame(t *testing.T) {
+	players := []*game.Player{game.NewPlayer("A"), game.NewPlayer("B")}
+	state, err := game.NewGameStateWithOptions(players, game.ModernOptions())
+	if err != nil {
+		t.Fatalf("Expected no error creating game, got %v", err)
+	}
+
+	expert := NewISMCTSBot(3)
+	expert.Iterations = 50
+
+	table, err := NewTable(state, game.NewGameRules(), []Bot{expert, NewGreedyBot()})
+	if err != nil {
+		t.Fatalf("Expected no error creating table, got %v", err)
+	}
+
+	if _, err := table.Run(5000); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if state.Phase != game.PhaseGameOver {
+		t.Errorf("Expected the game to finish, phase is %d", state.Phase)
+	}
+}
+
+func TestTurnMovesOnlyDrawWhenStuck(t *testing.T) {
+	state := createTestState([]game.Card{
+		{Color: game.Red, Type: game.Number, Value: 7},
+		{Color: game.Blue, Type: game.Number, Value: 1},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+
+	for _, move := range turnMoves(nil, state) {
+		if move.Kind == game.MoveDraw {
+			t.Error("Expected no draw while a card can be played")
+		}
+	}
+
+	state.ActiveColor = game.Green
+	state.DiscardPile = game.CreateDiscardPile(game.Card{Color: game.Green, Type: game.Number, Value: 3})
+	moves := turnMoves(nil, state)
+	if len(moves) != 1 || moves[0].Kind != game.MoveDraw {
+		t.Errorf("Expected only a draw with nothing to play, got %v", moves)
+	}
+}
+
+func TestReward(t *testing.T) {
+	state := createTestState([]game.Card{
+		{Color: game.Red, Type: game.Number, Value: 7},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+
+	// One card against three is a game cut short well ahead
+	if got := reward(state, 0); got != 0.75 {
+		t.Errorf("Expected 0.75 for the smaller hand, got %v", got)
+	}
+	if got := reward(state, 1); got != 0.25 {
+		t.Errorf("Expected 0.25 for the bigger hand, got %v", got)
+	}
+
+	if err := game.NewGameRules().Apply(state, game.Move{Kind: game.MovePlay, Player: 0, CardIndex: 0}); err != nil {
+		t.Fatal(err)
+	}
+	if reward(state, 0) != 1 || reward(state, 1) != 0 {
+		t.Errorf("Expected the whole reward for the winner, got %v and %v", reward(state, 0), reward(state, 1))
+	}
+}
+
+// removeCards takes every card in the hands and on the discard pile out of the deck
+func removeCards(deck *game.Deck, state *game.GameState) {
+	known := append([]game.Card(nil), state.DiscardPile.Cards()...)
+	for _, player := range state.Players {
+		known = append(known, player.Hand.Cards()...)
+	}
+
+	rest := append([]game.Card(nil), deck.Cards()...)
+	for _, card := range known {
+		for i := range rest {
+			if rest[i] == card {
+				rest = append(rest[:i], rest[i+1:]...)
+				break
+			}
+		}
+	}
+	deck.Replace(rest)
+}
@@ -0,0 +1,167 @@
+package bot
+
+import (
+	"math"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// LookaheadBot searches the chains of plays it can make before the turn passes
+// In a two player game Skip, Reverse and the draw cards keep the turn, so the
+// order the cards go down in decides how many of them can be shed at once
+type LookaheadBot struct {
+	Depth int // How many cards ahead to search within one turn
+}
+
+func NewLookaheadBot() *LookaheadBot {
+	return &LookaheadBot{Depth: 4}
+}
+
+func (b *LookaheadBot) Name() string {
+	return "lookahead"
+}
+
+func (b *LookaheadBot) ChooseMove(view game.PlayerView) game.Move {
+	if move, ok := urgentMove(view); ok {
+		return move
+	}
+
+	best := view.LegalMoves[0]
+	bestScore := math.MinInt
+	for _, move := range view.LegalMoves {
+		if swapsWithPartner(view, move) {
+			continue
+		}
+		if score := b.evaluate(view, move); score > bestScore {
+			best = move
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// evaluate rates a move by the best sequence of plays it starts, higher is better
+func (b *LookaheadBot) evaluate(view game.PlayerView, move game.Move) int {
+	hand := view.MyHand()
+
+	switch move.Kind {
+	case game.MovePlay:
+		card := hand[move.CardIndex]
+		rest := withoutCard(hand, move.CardIndex)
+		if len(rest) == 0 {
+			return winScore
+		}
+
+		score := b.cardValue(view, card)
+
+		switch card.Type {
+		case game.WildSwapHands:
+			score += (len(rest) - view.HandSizes[move.Target]) * 20
+		case game.WildShuffleHands:
+			total := -1
+			for _, size := range view.HandSizes {
+				total += size
+			}
+			score += (len(rest) - total/len(view.HandSizes)) * 20
+		}
+
+		color := card.Color
+		if color == game.Wild {
+			color = move.Color
+		}
+		return score + b.followUp(view, rest, card, color, b.Depth-1)
+	case game.MoveChooseColor:
+		score := 0
+		for _, card := range hand {
+			if card.Color == move.Color {
+				score += 10
+			}
+		}
+		if view.TopCard.Type == game.WildSwapHands {
+			score -= view.HandSizes[move.Target] * 20
+		}
+		return score
+	default:
+		// Drawing or passing only when there is nothing to play
+		return -1000
+	}
+}
+
+// winScore outranks any sequence that does not empty the hand
+const winScore = 100000
+
+// followUp returns the value of the best plays left after a card goes down
+// When the card passes the turn, it rates how many cards still match the chosen color
+func (b *LookaheadBot) followUp(view game.PlayerView, hand []game.Card, top game.Card, color game.CardColor, depth int) int {
+	if !keepsTurn(view, top) || depth <= 0 {
+		score := 0
+		for _, card := range hand {
+			if card.Color == color {
+				score += 5
+			}
+		}
+		return score
+	}
+
+	held := game.NewHand(hand...)
+
+	// Stopping here is always possible by drawing
+	best := 0
+	for i, card := range hand {
+		if !game.IsPlayable(card, &held, top, color) {
+			continue
+		}
+
+		rest := withoutCard(hand, i)
+		if len(rest) == 0 {
+			return winScore
+		}
+
+		colors := []game.CardColor{card.Color}
+		if card.Color == game.Wild {
+			colors = []game.CardColor{game.Red, game.Blue, game.Green, game.Yellow}
+		}
+
+		for _, next := range colors {
+			score := b.cardValue(view, card) + b.followUp(view, rest, card, next, depth-1)
+			if score > best {
+				best = score
+			}
+		}
+	}
+	return best
+}
+
+// cardValue rates shedding a single card
+func (b *LookaheadBot) cardValue(view game.PlayerView, card game.Card) int {
+	score := 30 + card.Points()
+
+	// Wilds are worth more kept for later
+	if card.Color == game.Wild {
+		score -= 60
+	}
+
+	if opponentThreat(view) && isAttack(card) {
+		score += 50
+	}
+	return score
+}
+
+// keepsTurn checks if playing the card gives the same player another turn
+func keepsTurn(view game.PlayerView, card game.Card) bool {
+	if len(view.HandSizes) != 2 {
+		return false
+	}
+
+	if card.Type == game.WildCustomizable {
+		return view.Options.CustomRule == game.CustomRuleSkip || view.Options.CustomRule == game.CustomRuleDrawTwo
+	}
+	return isAttack(card)
+}
+
+// withoutCard returns a copy of the hand with one card removed
+func withoutCard(hand []game.Card, index int) []game.Card {
+	rest := make([]game.Card, 0, len(hand)-1)
+	rest = append(rest, hand[:index]...)
+	return append(rest, hand[index+1:]...)
+}
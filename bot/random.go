@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"math/rand/v2"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// RandomBot picks uniformly among the legal moves, only drawing
+// or passing when it has nothing else to do
+type RandomBot struct {
+	rng *rand.Rand
+}
+
+// NewRandomBot creates a random bot with a reproducible seed
+func NewRandomBot(seed uint64) *RandomBot {
+	return &RandomBot{rng: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+}
+
+func (b *RandomBot) Name() string {
+	return "random"
+}
+
+func (b *RandomBot) ChooseMove(view game.PlayerView) game.Move {
+	candidates := make([]game.Move, 0, len(view.LegalMoves))
+	for _, move := range view.LegalMoves {
+		if move.Kind != game.MoveDraw && move.Kind != game.MovePass {
+			candidates = append(candidates, move)
+		}
+	}
+
+	if len(candidates) == 0 {
+		candidates = view.LegalMoves
+	}
+	return candidates[b.rng.IntN(len(candidates))]
+}
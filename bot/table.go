@@ -0,0 +1,187 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Table seats bots at a game and lets them take their turns
+// Seats left nil belong to people, the table waits for them to act
+type Table struct {
+	State *game.GameState
+	Rules *game.GameRules
+	Seats []Bot
+
+	// Replay, if set, gets every move the bots make, see game.Record
+	Replay *game.Replay
+
+	moves []game.Move // Buffer for checking if a waiting bot has anything to do
+
+	observers []Observer
+	thought   *Thought     // The move being chosen in the background, see Think
+	held      []game.Event // Events kept from the observers until the thought ends
+}
+
+// Thought is a move a bot is choosing on its own goroutine
+type Thought struct {
+	seat int
+	bot  Bot
+	move chan game.Move
+}
+
+// NewTable creates a table with one entry in seats per player
+// Bots that implement Observer are subscribed to the rules' events
+func NewTable(state *game.GameState, rules *game.GameRules, seats []Bot) (*Table, error) {
+	if len(seats) != len(state.Players) {
+		return nil, fmt.Errorf("expected %d seats, got %d", len(state.Players), len(seats))
+	}
+
+	t := &Table{State: state, Rules: rules, Seats: seats}
+	for _, b := range seats {
+		if observer, ok := b.(Observer); ok {
+			t.observers = append(t.observers, observer)
+		}
+	}
+	if len(t.observers) > 0 {
+		rules.Subscribe(t.notify)
+	}
+	return t, nil
+}
+
+// notify passes an event on to the observing bots, or keeps it while one thinks
+// so nothing a bot reads changes under it
+func (t *Table) notify(event game.Event) {
+	if t.thought != nil {
+		t.held = append(t.held, event)
+		return
+	}
+	for _, observer := range t.observers {
+		observer.Observe(event)
+	}
+}
+
+// Step lets one bot make a move. A bot down to one card gets to call UNO before
+// anyone can challenge it, then bots waiting for their turn get to challenge and
+// the bot whose turn it is plays
+// Returns false when no bot has anything to do, for example while waiting on a person
+func (t *Table) Step() (bool, error) {
+	seat, b, view, ok := t.next()
+	if !ok {
+		return false, nil
+	}
+	return true, t.apply(seat, b, b.ChooseMove(view))
+}
+
+// Think is Step for a game window: the bot chooses its move on its own goroutine
+// and Poll applies it, so a searching bot does not hold up the frames
+// Returns nil when no bot has anything to do
+func (t *Table) Think() *Thought {
+	seat, b, view, ok := t.next()
+	if !ok {
+		return nil
+	}
+
+	t.thought = &Thought{seat: seat, bot: b, move: make(chan game.Move, 1)}
+	go func(th *Thought) {
+		th.move <- th.bot.ChooseMove(view)
+	}(t.thought)
+	return t.thought
+}
+
+// Poll returns false while the bot is still choosing, then applies its move
+// A choice made on a view that moves made meanwhile left out of date is dropped,
+// the next Think asks again
+func (t *Table) Poll(th *Thought) (bool, error) {
+	var move game.Move
+	select {
+	case move = <-th.move:
+	default:
+		return false, nil
+	}
+
+	t.thought = nil
+	stale := len(t.held) > 0
+	for _, event := range t.held {
+		t.notify(event)
+	}
+	t.held = t.held[:0]
+
+	if stale {
+		return true, nil
+	}
+	return true, t.apply(th.seat, th.bot, move)
+}
+
+// next picks the bot that Step moves and builds its view
+func (t *Table) next() (int, Bot, game.PlayerView, bool) {
+	if t.State.Phase == game.PhaseGameOver {
+		return 0, nil, game.PlayerView{}, false
+	}
+
+	// Starting with the seat whose turn it is, which may keep its turn with a Skip
+	for i := range t.Seats {
+		seat := (t.State.CurrentPlayer + i) % len(t.Seats)
+		player := t.State.Players[seat]
+		if b := t.Seats[seat]; b != nil && player.ShouldCallUno() && !player.HasCalledUno {
+			return seat, b, t.State.View(seat), true
+		}
+	}
+
+	for seat, b := range t.Seats {
+		if b == nil || seat == t.State.CurrentPlayer {
+			continue
+		}
+
+		// Building a view is costly, most of the time a waiting bot has nothing to do
+		t.moves = game.AppendLegalMoves(t.moves[:0], t.State, seat)
+		if len(t.moves) > 0 {
+			return seat, b, t.State.View(seat), true
+		}
+	}
+
+	seat := t.State.CurrentPlayer
+	b := t.Seats[seat]
+	if b == nil {
+		return 0, nil, game.PlayerView{}, false
+	}
+
+	view := t.State.View(seat)
+	if len(view.LegalMoves) == 0 {
+		return 0, nil, game.PlayerView{}, false
+	}
+	return seat, b, view, true
+}
+
+// Run steps until a person has to act, the game ends or maxMoves bot moves were made
+// Returns the number of moves the bots made
+func (t *Table) Run(maxMoves int) (int, error) {
+	moves := 0
+	for moves < maxMoves {
+		moved, err := t.Step()
+		if err != nil {
+			return moves, err
+		}
+		if !moved {
+			return moves, nil
+		}
+		moves++
+	}
+	return moves, nil
+}
+
+func (t *Table) apply(seat int, b Bot, move game.Move) error {
+	if move.Player != seat {
+		return errors.New(b.Name() + " bot tried to move for another seat")
+	}
+
+	if err := t.Rules.Apply(t.State, move); err != nil {
+		return fmt.Errorf("%s bot made an invalid move: %v", b.Name(), err)
+	}
+
+	if t.Replay != nil {
+		t.Replay.Add(move)
+	}
+	return nil
+}
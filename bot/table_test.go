@@ -0,0 +1,187 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+func TestTablePlaysFullGames(t *testing.T) {
+	matchups := [][]Bot{
+		{NewRandomBot(1), NewRandomBot(2)},
+		{NewGreedyBot(), NewRandomBot(3)},
+		{NewLookaheadBot(), NewGreedyBot()},
+	}
+
+	for _, seats := range matchups {
+		for range 10 {
+			players := []*game.Player{game.NewPlayer("A"), game.NewPlayer("B")}
+			state, err := game.NewGameStateWithOptions(players, game.ModernOptions())
+			if err != nil {
+				t.Fatalf("Expected no error creating game, got %v", err)
+			}
+
+			table, err := NewTable(state, game.NewGameRules(), seats)
+			if err != nil {
+				t.Fatalf("Expected no error creating table, got %v", err)
+			}
+
+			if _, err := table.Run(10000); err != nil {
+				t.Fatalf("Expected no error running %s vs %s, got %v", seats[0].Name(), seats[1].Name(), err)
+			}
+
+			if state.Phase != game.PhaseGameOver {
+				t.Errorf("Expected %s vs %s to finish, phase is %d", seats[0].Name(), seats[1].Name(), state.Phase)
+			}
+		}
+	}
+}
+
+func TestTableWaitsForPeople(t *testing.T) {
+	state := createTestState([]game.Card{
+		{Color: game.Red, Type: game.Number, Value: 7},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+
+	table, err := NewTable(state, game.NewGameRules(), []Bot{nil, NewGreedyBot()})
+	if err != nil {
+		t.Fatalf("Expected no error creating table, got %v", err)
+	}
+
+	moved, err := table.Step()
+	if moved || err != nil {
+		t.Errorf("Expected the table to wait for the person, got moved=%t err=%v", moved, err)
+	}
+
+	// The bot catches the person who forgot to call UNO
+	state.Players[0].AddCard(game.Card{Color: game.Blue, Type: game.Number, Value: 1})
+	if err := table.Rules.HandlePlayCard(state.Players[0], 0, state, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	moved, err = table.Step()
+	if !moved || err != nil {
+		t.Fatalf("Expected the bot to act, got moved=%t err=%v", moved, err)
+	}
+
+	if state.Players[0].HandSize() != 3 {
+		t.Errorf("Expected the person to draw 2 penalty cards, has %d cards", state.Players[0].HandSize())
+	}
+
+	if _, err := NewTable(state, table.Rules, []Bot{nil}); err == nil {
+		t.Error("Expected error when the number of seats does not match the players")
+	}
+}
+
+// A bot keeping its turn with a Skip calls UNO before its opponent can challenge
+func TestTableCallsUnoBeforeChallenges(t *testing.T) {
+	state := createTestState([]game.Card{
+		{Color: game.Red, Type: game.Skip},
+		{Color: game.Blue, Type: game.Number, Value: 3},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+
+	table, err := NewTable(state, game.NewGameRules(), []Bot{NewGreedyBot(), NewGreedyBot()})
+	if err != nil {
+		t.Fatalf("Expected no error creating table, got %v", err)
+	}
+
+	for range 2 {
+		if moved, err := table.Step(); !moved || err != nil {
+			t.Fatalf("Expected a bot to act, got moved=%t err=%v", moved, err)
+		}
+	}
+
+	if state.CurrentPlayer != 0 || !state.Players[0].HasCalledUno {
+		t.Errorf("Expected the bot to keep its turn and call UNO, current player is %d", state.CurrentPlayer)
+	}
+	if state.Players[0].HandSize() != 1 {
+		t.Errorf("Expected the bot to be left with one card, has %d", state.Players[0].HandSize())
+	}
+}
+
+// slowBot is a greedy bot that chooses only once released, recording the events it sees
+type slowBot struct {
+	GreedyBot
+	release chan struct{}
+	events  []game.Event
+}
+
+func (b *slowBot) ChooseMove(view game.PlayerView) game.Move {
+	<-b.release
+	return b.GreedyBot.ChooseMove(view)
+}
+
+func (b *slowBot) Observe(event game.Event) {
+	b.events = append(b.events, event)
+}
+
+// poll waits for the bot thinking at the table to choose
+func poll(t *testing.T, table *Table, thought *Thought) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		done, err := table.Poll(thought)
+		if err != nil {
+			t.Fatalf("Expected no error polling, got %v", err)
+		}
+		if done {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the bot to choose")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTableThink(t *testing.T) {
+	state := createTestState([]game.Card{
+		{Color: game.Red, Type: game.Number, Value: 7},
+		{Color: game.Blue, Type: game.Number, Value: 1},
+	}, game.Card{Color: game.Red, Type: game.Number, Value: 5})
+	b := &slowBot{release: make(chan struct{})}
+	table, err := NewTable(state, game.NewGameRules(), []Bot{nil, b})
+	if err != nil {
+		t.Fatalf("Expected no error creating table, got %v", err)
+	}
+
+	// The person forgets UNO, the bot starts thinking about a challenge
+	if err := table.Rules.HandlePlayCard(state.Players[0], 0, state, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	seen := len(b.events)
+	thought := table.Think()
+	if thought == nil {
+		t.Fatal("Expected the bot to think")
+	}
+	if done, _ := table.Poll(thought); done {
+		t.Error("Expected the bot to still be thinking")
+	}
+
+	// Calling UNO meanwhile leaves the bot's view out of date
+	if err := table.Rules.Apply(state, game.Move{Kind: game.MoveCallUno, Player: 0}); err != nil {
+		t.Fatalf("Expected no error calling UNO, got %v", err)
+	}
+	if len(b.events) != seen {
+		t.Error("Expected events held from the bot while it thinks")
+	}
+
+	close(b.release)
+	poll(t, table, thought)
+	if len(b.events) == seen {
+		t.Error("Expected the held events passed on once the bot chose")
+	}
+	if state.Players[0].HandSize() != 1 {
+		t.Errorf("Expected the stale challenge dropped, the person has %d cards", state.Players[0].HandSize())
+	}
+
+	// Asked again, the bot without a red card draws
+	thought = table.Think()
+	if thought == nil {
+		t.Fatal("Expected the bot to think about its turn")
+	}
+	poll(t, table, thought)
+	if state.Players[1].HandSize() != 4 {
+		t.Errorf("Expected the bot to have drawn, has %d cards", state.Players[1].HandSize())
+	}
+}
@@ -0,0 +1,234 @@
+// Package cardart draws placeholder card images in code, to the size and palette of
+// art-guidelines.md, until the pixel art exists. It only uses the standard image
+// packages, the game turns the images into textures when it loads
+package cardart
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Size of every card, front and back
+const (
+	Width  = 64
+	Height = 96
+)
+
+// Shades are the tones of a card color, Shadow and Highlight give the pixel art depth
+type Shades struct {
+	Primary   color.RGBA
+	Shadow    color.RGBA
+	Highlight color.RGBA
+}
+
+// Colors hold the shades of every card color, wild cards being black
+type Colors map[game.CardColor]Shades
+
+// Palette holds the built in card colors
+var Palette = Colors{
+	game.Red:    {Primary: rgb(0xff0000), Shadow: rgb(0xc00000), Highlight: rgb(0xff6666)},
+	game.Blue:   {Primary: rgb(0x0000ff), Shadow: rgb(0x0000c0), Highlight: rgb(0x6666ff)},
+	game.Green:  {Primary: rgb(0x00cc00), Shadow: rgb(0x009900), Highlight: rgb(0x66ff66)},
+	game.Yellow: {Primary: rgb(0xffcc00), Shadow: rgb(0xcc9900), Highlight: rgb(0xffee66)},
+	game.Wild:   {Primary: rgb(0x333333), Shadow: rgb(0x000000), Highlight: rgb(0x666666)},
+}
+
+var white = rgb(0xffffff)
+
+// wildQuadrants color the oval of wild cards, clockwise from the top left
+var wildQuadrants = []game.CardColor{game.Red, game.Blue, game.Green, game.Yellow}
+
+// The oval in the middle of every card, tilted like on the printed deck
+const (
+	ovalRadiusX = 20
+	ovalRadiusY = 36
+	ovalTilt    = math.Pi / 6
+)
+
+// Glyph scales for the symbol in the middle and the indices in the corners
+const (
+	symbolScale = 4
+	indexScale  = 2
+	indexMargin = 5
+)
+
+// Shade derives the shadow and highlight of a color, for palettes given only the
+// primary tones
+func Shade(primary color.RGBA) Shades {
+	darken := func(v uint8) uint8 { return v / 2 }
+	lighten := func(v uint8) uint8 { return uint8(int(v) + (0xff-int(v))*2/5) }
+	return Shades{
+		Primary:   primary,
+		Shadow:    color.RGBA{R: darken(primary.R), G: darken(primary.G), B: darken(primary.B), A: primary.A},
+		Highlight: color.RGBA{R: lighten(primary.R), G: lighten(primary.G), B: lighten(primary.B), A: primary.A},
+	}
+}
+
+func rgb(hex uint32) color.RGBA {
+	return color.RGBA{R: uint8(hex >> 16), G: uint8(hex >> 8), B: uint8(hex), A: 0xff}
+}
+
+// Cards lists every different card face: numbers, Skip, Reverse and Draw Two in
+// every color, then every kind of wild card
+func Cards() []game.Card {
+	var cards []game.Card
+	for _, c := range []game.CardColor{game.Red, game.Blue, game.Green, game.Yellow} {
+		for value := 0; value <= 9; value++ {
+			cards = append(cards, game.Card{Color: c, Type: game.Number, Value: value})
+		}
+		for _, t := range []game.CardType{game.Skip, game.Reverse, game.DrawTwo} {
+			cards = append(cards, game.Card{Color: c, Type: t})
+		}
+	}
+	for _, t := range []game.CardType{game.WildCard, game.WildDrawFour, game.WildShuffleHands, game.WildSwapHands, game.WildCustomizable} {
+		cards = append(cards, game.Card{Color: game.Wild, Type: t})
+	}
+	return cards
+}
+
+// Face draws the front of a card in the built in colors
+func Face(card game.Card) (*image.RGBA, error) {
+	return Palette.Face(card)
+}
+
+// Face draws the front of a card in these colors
+func (p Colors) Face(card game.Card) (*image.RGBA, error) {
+	// Cards without notation have no face either
+	if _, err := card.Notation(); err != nil {
+		return nil, err
+	}
+
+	shades := p[card.Color]
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	p.frame(img, shades)
+
+	symbol := label(card)
+	if card.Color == game.Wild {
+		oval(img, func(x, y float64) color.RGBA {
+			quadrant := 0
+			switch {
+			case x >= 0 && y < 0:
+				quadrant = 1
+			case x >= 0 && y >= 0:
+				quadrant = 2
+			case x < 0 && y >= 0:
+				quadrant = 3
+			}
+			return p[wildQuadrants[quadrant]].Primary
+		})
+		if card.Type != game.WildCard {
+			centered(img, symbol, white, shades.Shadow)
+		}
+	} else {
+		oval(img, func(x, y float64) color.RGBA { return white })
+		centered(img, symbol, shades.Primary, shades.Shadow)
+	}
+
+	// Indices read upright from either end of the card
+	for _, upsideDown := range []bool{false, true} {
+		p := image.Pt(indexMargin, indexMargin)
+		if upsideDown {
+			p = image.Pt(Width-indexMargin, Height-indexMargin)
+		}
+		drawText(img, symbol, p.Add(image.Pt(1, 1)), indexScale, shades.Shadow, upsideDown)
+		drawText(img, symbol, p, indexScale, white, upsideDown)
+	}
+	return img, nil
+}
+
+// Back draws the back shared by every card, in grays that match no card color
+func Back() *image.RGBA {
+	return Palette.Back()
+}
+
+// Back draws the back shared by every card in the shades of wild cards
+func (p Colors) Back() *image.RGBA {
+	shades := p[game.Wild]
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	p.frame(img, shades)
+
+	// A checkered oval, so the back reads as a pattern rather than a color
+	oval(img, func(x, y float64) color.RGBA {
+		if (int(x+Width)/2+int(y+Height)/2)%2 == 0 {
+			return shades.Highlight
+		}
+		return shades.Primary
+	})
+	centered(img, "UNO", white, shades.Shadow)
+	return img
+}
+
+// label returns the text in the corners of a card
+func label(card game.Card) string {
+	switch card.Type {
+	case game.Number:
+		return strconv.Itoa(card.Value)
+	case game.Skip:
+		return string(skipSymbol)
+	case game.Reverse:
+		return string(reverseSymbol)
+	case game.DrawTwo:
+		return "+2"
+	case game.WildCard:
+		return "W"
+	case game.WildDrawFour:
+		return "+4"
+	case game.WildShuffleHands:
+		return "SH"
+	case game.WildSwapHands:
+		return "SW"
+	default:
+		return "?"
+	}
+}
+
+// frame fills the card with its color inside a black outline with rounded corners,
+// lit from the top left: highlight along the top and left, shadow along the bottom and right
+func (p Colors) frame(img *image.RGBA, shades Shades) {
+	outline := p[game.Wild].Shadow
+	for y := range Height {
+		for x := range Width {
+			edgeX := x == 0 || x == Width-1
+			edgeY := y == 0 || y == Height-1
+			switch {
+			case edgeX && edgeY:
+				// Corners stay transparent
+			case edgeX || edgeY:
+				img.SetRGBA(x, y, outline)
+			case x == 1 || y == 1:
+				img.SetRGBA(x, y, shades.Highlight)
+			case x == Width-2 || y == Height-2:
+				img.SetRGBA(x, y, shades.Shadow)
+			default:
+				img.SetRGBA(x, y, shades.Primary)
+			}
+		}
+	}
+}
+
+// oval paints the tilted oval in the middle of the card, asking fill for the color
+// of every pixel by its position from the middle along the oval's own axes
+func oval(img *image.RGBA, fill func(x, y float64) color.RGBA) {
+	sin, cos := math.Sincos(ovalTilt)
+	for y := range Height {
+		for x := range Width {
+			dx, dy := float64(x)-Width/2+0.5, float64(y)-Height/2+0.5
+			ox, oy := dx*cos+dy*sin, -dx*sin+dy*cos
+			if (ox*ox)/(ovalRadiusX*ovalRadiusX)+(oy*oy)/(ovalRadiusY*ovalRadiusY) <= 1 {
+				img.SetRGBA(x, y, fill(ox, oy))
+			}
+		}
+	}
+}
+
+// centered draws the text large in the middle of the card, with a drop shadow
+// toward the bottom right
+func centered(img *image.RGBA, text string, clr, shadow color.RGBA) {
+	p := image.Pt((Width-textWidth(text, symbolScale))/2, (Height-glyphHeight*symbolScale)/2)
+	drawText(img, text, p.Add(image.Pt(1, 1)), symbolScale, shadow, false)
+	drawText(img, text, p, symbolScale, clr, false)
+}
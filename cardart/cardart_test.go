@@ -0,0 +1,156 @@
+package cardart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+func TestCards(t *testing.T) {
+	cards := Cards()
+	if len(cards) != 4*13+5 {
+		t.Errorf("Expected 57 different cards, got %d", len(cards))
+	}
+
+	// Every card of the biggest deck has a face
+	seen := make(map[game.Card]bool)
+	for _, card := range cards {
+		seen[card] = true
+	}
+	for _, card := range game.NewDeckWithOptions(game.ModernOptions()).Cards() {
+		if !seen[card] {
+			t.Errorf("Expected a face for %v", card)
+		}
+	}
+}
+
+func TestFace(t *testing.T) {
+	allowed := map[color.RGBA]bool{white: true, {}: true}
+	for _, shades := range Palette {
+		allowed[shades.Primary] = true
+		allowed[shades.Shadow] = true
+		allowed[shades.Highlight] = true
+	}
+
+	faces := make(map[string]game.Card)
+	for _, card := range Cards() {
+		img, err := Face(card)
+		if err != nil {
+			t.Fatalf("Expected a face for %v, got %v", card, err)
+		}
+		if img.Bounds() != image.Rect(0, 0, Width, Height) {
+			t.Errorf("Expected %v to be %dx%d, got %v", card, Width, Height, img.Bounds())
+		}
+
+		// Rounded corners, the card's color at the edge and nothing off the palette
+		if img.RGBAAt(0, 0).A != 0 || img.RGBAAt(Width-1, Height-1).A != 0 {
+			t.Errorf("Expected transparent corners on %v", card)
+		}
+		if got := img.RGBAAt(3, Height/2); got != Palette[card.Color].Primary {
+			t.Errorf("Expected %v to be %v, got %v", card, Palette[card.Color].Primary, got)
+		}
+		for i := 0; i < len(img.Pix); i += 4 {
+			pixel := color.RGBA{img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3]}
+			if !allowed[pixel] {
+				t.Errorf("Expected only palette colors on %v, got %v", card, pixel)
+				break
+			}
+		}
+
+		if other, ok := faces[string(img.Pix)]; ok {
+			t.Errorf("Expected %v and %v to look different", card, other)
+		}
+		faces[string(img.Pix)] = card
+	}
+
+	if _, err := Face(game.Card{Color: game.Red, Type: game.WildCard}); err == nil {
+		t.Error("Expected error for a red wild card")
+	}
+}
+
+func TestWildQuadrants(t *testing.T) {
+	img, err := Face(game.Card{Color: game.Wild, Type: game.WildCard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Points in each quarter of the oval, away from the middle
+	for i, p := range []image.Point{{24, 34}, {40, 38}, {40, 62}, {24, 58}} {
+		expected := Palette[wildQuadrants[i]].Primary
+		if got := img.RGBAAt(p.X, p.Y); got != expected {
+			t.Errorf("Expected %v at %v, got %v", wildQuadrants[i], p, got)
+		}
+	}
+}
+
+func TestBack(t *testing.T) {
+	back := Back()
+	if back.Bounds() != image.Rect(0, 0, Width, Height) {
+		t.Errorf("Expected the back to be %dx%d, got %v", Width, Height, back.Bounds())
+	}
+
+	for _, card := range Cards() {
+		face, _ := Face(card)
+		if bytes.Equal(face.Pix, back.Pix) {
+			t.Errorf("Expected the back to differ from %v", card)
+		}
+	}
+
+	// No card color shows on the back
+	for _, c := range []game.CardColor{game.Red, game.Blue, game.Green, game.Yellow} {
+		for i := 0; i < len(back.Pix); i += 4 {
+			pixel := color.RGBA{back.Pix[i], back.Pix[i+1], back.Pix[i+2], back.Pix[i+3]}
+			if pixel == Palette[c].Primary {
+				t.Errorf("Expected no %v on the back", c)
+				break
+			}
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if got := textWidth("7", 2); got != 6 {
+		t.Errorf("Expected one glyph 6 pixels wide, got %d", got)
+	}
+	if got := textWidth("+2", 4); got != 28 {
+		t.Errorf("Expected two glyphs and a gap 28 pixels wide, got %d", got)
+	}
+	if got := textWidth(string(skipSymbol), 1); got != 5 {
+		t.Errorf("Expected the skip symbol 5 pixels wide, got %d", got)
+	}
+}
+
+func TestColors(t *testing.T) {
+	colors := Colors{
+		game.Red:    Shade(rgb(0xcc0000)),
+		game.Blue:   Shade(rgb(0x0044ff)),
+		game.Green:  Shade(rgb(0x008800)),
+		game.Yellow: Shade(rgb(0xffdd00)),
+		game.Wild:   Shade(rgb(0x000000)),
+	}
+	if shades := colors[game.Red]; shades.Shadow != rgb(0x660000) || shades.Highlight != rgb(0xe06666) {
+		t.Errorf("Expected red shaded darker and lighter, got %+v", shades)
+	}
+
+	img, err := colors.Face(game.Card{Color: game.Red, Type: game.Number, Value: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.RGBAAt(3, Height/2); got != rgb(0xcc0000) {
+		t.Errorf("Expected the card in its own red, got %v", got)
+	}
+	if got := img.RGBAAt(Width/2, 0); got != rgb(0x000000) {
+		t.Errorf("Expected the outline in the shadow of wild cards, got %v", got)
+	}
+
+	wild, _ := colors.Face(game.Card{Color: game.Wild, Type: game.WildCard})
+	if got := wild.RGBAAt(40, 38); got != rgb(0x0044ff) {
+		t.Errorf("Expected the oval of wild cards in the card colors, got %v", got)
+	}
+	if back := colors.Back(); back.RGBAAt(3, Height/2) != rgb(0x000000) {
+		t.Errorf("Expected the back in the shades of wild cards, got %v", back.RGBAAt(3, Height/2))
+	}
+}
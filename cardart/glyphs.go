@@ -0,0 +1,82 @@
+package cardart
+
+import (
+	"image"
+	"image/color"
+)
+
+// glyphs are the pixel letters drawn on cards, '#' is a set pixel
+var glyphs = map[rune][]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", ".##", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+	'?': {"###", "..#", ".##", "...", ".#."},
+	'C': {"###", "#..", "#..", "#..", "###"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'N': {"#..#", "##.#", "#.##", "#..#", "#..#"},
+	'O': {"###", "#.#", "#.#", "#.#", "###"},
+	'S': {"###", "#..", "###", "..#", "###"},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'W': {"#...#", "#...#", "#.#.#", "##.##", "#...#"},
+
+	// Symbols for the action cards
+	skipSymbol:    {".###.", "#..##", "#.#.#", "##..#", ".###."},
+	reverseSymbol: {".#...", "#####", ".....", "#####", "...#."},
+}
+
+// Symbols get runes from the private use area so they never clash with letters
+const (
+	skipSymbol    = '\uE000'
+	reverseSymbol = '\uE001'
+)
+
+// glyphHeight is the height of every glyph, in pixels before scaling
+const glyphHeight = 5
+
+// textWidth returns the width of text drawn at the scale, with a pixel between glyphs
+func textWidth(text string, scale int) int {
+	width := 0
+	for _, r := range text {
+		if width > 0 {
+			width += scale
+		}
+		width += len(glyphs[r][0]) * scale
+	}
+	return width
+}
+
+// drawText draws text with its top left corner at p, every glyph pixel a scale
+// by scale square. Upside down text is drawn rotated half a turn around p, for the
+// index in the bottom right corner
+func drawText(img *image.RGBA, text string, p image.Point, scale int, clr color.RGBA, upsideDown bool) {
+	x := 0
+	for _, r := range text {
+		glyph := glyphs[r]
+		for row, line := range glyph {
+			for col, pixel := range line {
+				if pixel != '#' {
+					continue
+				}
+				dx, dy := x+col*scale, row*scale
+				for sy := range scale {
+					for sx := range scale {
+						if upsideDown {
+							img.SetRGBA(p.X-dx-sx-1, p.Y-dy-sy-1, clr)
+						} else {
+							img.SetRGBA(p.X+dx+sx, p.Y+dy+sy, clr)
+						}
+					}
+				}
+			}
+		}
+		x += (len(glyph[0]) + 1) * scale
+	}
+}
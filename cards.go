@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/png"
+	"io/fs"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/vtigo/uno-clone/cardart"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// atlasColumns is how many cards sit side by side in the atlas, a color to a row
+const atlasColumns = 13
+
+// backImage is the file of the card back, next to the card faces
+const backImage = "back.png"
+
+// cardColors color the placeholder cards and the color choices, switched along
+// with the theme
+var cardColors = cardart.Palette
+
+// CardAtlas holds every card image packed into one texture, so drawing a hand
+// never switches textures
+type CardAtlas struct {
+	texture *ebiten.Image
+	faces   map[game.Card]*ebiten.Image
+	back    *ebiten.Image
+}
+
+// NewCardAtlas packs card images into a texture, taking every image from the
+// first source holding it and drawing cards no source holds with cardart in colors
+func NewCardAtlas(colors cardart.Colors, sources ...fs.FS) (*CardAtlas, error) {
+	img, err := packCards(colors, sources...)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &CardAtlas{texture: ebiten.NewImageFromImage(img), faces: make(map[game.Card]*ebiten.Image)}
+	cards := cardart.Cards()
+	for i, card := range cards {
+		a.faces[card] = a.texture.SubImage(atlasCell(i)).(*ebiten.Image)
+	}
+	a.back = a.texture.SubImage(atlasCell(len(cards))).(*ebiten.Image)
+	return a, nil
+}
+
+// Face returns the image of a card, the back for a card without a face
+func (a *CardAtlas) Face(card game.Card) *ebiten.Image {
+	if img, ok := a.faces[card]; ok {
+		return img
+	}
+	return a.back
+}
+
+// Back returns the image of the back of the cards
+func (a *CardAtlas) Back() *ebiten.Image {
+	return a.back
+}
+
+// DrawFace draws the front of a card into the rectangle
+func (a *CardAtlas) DrawFace(dst *ebiten.Image, card game.Card, rect image.Rectangle) {
+	drawTexture(dst, a.Face(card), rect)
+}
+
+// DrawBack draws the back of a card, for hidden hands and the draw pile
+func (a *CardAtlas) DrawBack(dst *ebiten.Image, rect image.Rectangle) {
+	drawTexture(dst, a.back, rect)
+}
+
+// atlasCell returns where the card at the index of cardart.Cards sits in the
+// atlas, the back coming after the last card
+func atlasCell(index int) image.Rectangle {
+	p := image.Pt(index%atlasColumns*CardWidth, index/atlasColumns*CardHeight)
+	return image.Rectangle{Min: p, Max: p.Add(image.Pt(CardWidth, CardHeight))}
+}
+
+// packCards draws every card face and the back into one image, laid out by atlasCell.
+// Sources are folders of card images named by notation
+func packCards(colors cardart.Colors, sources ...fs.FS) (*image.RGBA, error) {
+	cards := cardart.Cards()
+	rows := (len(cards) + atlasColumns) / atlasColumns
+	atlas := image.NewRGBA(image.Rect(0, 0, atlasColumns*CardWidth, rows*CardHeight))
+
+	for i, card := range cards {
+		name, err := card.Notation()
+		if err != nil {
+			return nil, err
+		}
+		img, err := loadCardImage(sources, name+".png", func() image.Image {
+			face, _ := colors.Face(card)
+			return face
+		})
+		if err != nil {
+			return nil, err
+		}
+		draw.Draw(atlas, atlasCell(i), img, img.Bounds().Min, draw.Src)
+	}
+
+	back, err := loadCardImage(sources, backImage, func() image.Image { return colors.Back() })
+	if err != nil {
+		return nil, err
+	}
+	draw.Draw(atlas, atlasCell(len(cards)), back, back.Bounds().Min, draw.Src)
+	return atlas, nil
+}
+
+// loadCardImage reads the named image from the first source holding it, or makes
+// the placeholder when none does
+func loadCardImage(sources []fs.FS, name string, placeholder func() image.Image) (image.Image, error) {
+	for _, fsys := range sources {
+		f, err := fsys.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		img, _, err := image.Decode(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		if err := checkCardSize(name, img.Bounds().Size()); err != nil {
+			return nil, err
+		}
+		return img, nil
+	}
+	return placeholder(), nil
+}
+
+// checkCardSize makes sure a card image has the size of art-guidelines.md
+func checkCardSize(name string, size image.Point) error {
+	if size != image.Pt(CardWidth, CardHeight) {
+		return fmt.Errorf("%s is %dx%d, cards are %dx%d", name, size.X, size.Y, CardWidth, CardHeight)
+	}
+	return nil
+}
+
+// cardRect returns the rectangle of a card with its top left corner at p
+func cardRect(p image.Point) image.Rectangle {
+	return image.Rectangle{Min: p, Max: p.Add(image.Pt(CardWidth, CardHeight))}
+}
+
+// drawTexture draws the texture stretched over the rectangle
+func drawTexture(dst, img *ebiten.Image, rect image.Rectangle) {
+	bounds := img.Bounds()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(rect.Dx())/float64(bounds.Dx()), float64(rect.Dy())/float64(bounds.Dy()))
+	op.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+	dst.DrawImage(img, op)
+}
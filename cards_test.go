@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/vtigo/uno-clone/cardart"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// pngFile encodes a plain image of the size and color as a file for a test filesystem
+func pngFile(t *testing.T, width, height int, clr color.Color) *fstest.MapFile {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(clr), image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return &fstest.MapFile{Data: buf.Bytes()}
+}
+
+func TestPackCards(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	fsys := fstest.MapFS{
+		"R7.png":  pngFile(t, CardWidth, CardHeight, red),
+		backImage: pngFile(t, CardWidth, CardHeight, color.Black),
+	}
+	atlas, err := packCards(cardart.Palette, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cards := cardart.Cards()
+	for i, card := range cards {
+		cell := atlasCell(i)
+		switch card {
+		case game.Card{Color: game.Red, Type: game.Number, Value: 7}:
+			if got := atlas.RGBAAt(cell.Min.X+CardWidth/2, cell.Min.Y+CardHeight/2); got != red {
+				t.Errorf("Expected the image file for R7, got %v", got)
+			}
+		case game.Card{Color: game.Blue, Type: game.Skip}:
+			// Cards without a file get the placeholder
+			face, _ := cardart.Face(card)
+			if !bytes.Equal(atlas.SubImage(cell).(*image.RGBA).Pix[:4*CardWidth], face.Pix[:4*CardWidth]) {
+				t.Error("Expected the placeholder for BS")
+			}
+		}
+	}
+
+	back := atlasCell(len(cards))
+	if got := atlas.RGBAAt(back.Min.X, back.Min.Y); got != (color.RGBA{A: 0xff}) {
+		t.Errorf("Expected the image file for the back, got %v", got)
+	}
+
+	// The first source holding an image wins
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	atlas, err = packCards(cardart.Palette, fstest.MapFS{"R7.png": pngFile(t, CardWidth, CardHeight, blue)}, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r7 := atlasCell(slices.Index(cards, game.Card{Color: game.Red, Type: game.Number, Value: 7}))
+	if got := atlas.RGBAAt(r7.Min.X, r7.Min.Y); got != blue {
+		t.Errorf("Expected R7 from the first source, got %v", got)
+	}
+	if got := atlas.RGBAAt(back.Min.X, back.Min.Y); got != (color.RGBA{A: 0xff}) {
+		t.Errorf("Expected the back from the second source, got %v", got)
+	}
+
+	// Cards of the wrong size are refused
+	fsys["G2.png"] = pngFile(t, 32, 48, red)
+	if _, err := packCards(cardart.Palette, fsys); err == nil {
+		t.Error("Expected error for a card of the wrong size")
+	}
+}
+
+func TestAtlasCell(t *testing.T) {
+	seen := make(map[image.Point]bool)
+	for i := range len(cardart.Cards()) + 1 {
+		cell := atlasCell(i)
+		if cell.Dx() != CardWidth || cell.Dy() != CardHeight || seen[cell.Min] {
+			t.Errorf("Expected a card sized cell of its own at %d, got %v", i, cell)
+		}
+		seen[cell.Min] = true
+	}
+}
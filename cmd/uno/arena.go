@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vtigo/uno-clone/arena"
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/engine"
+	"github.com/vtigo/uno-clone/sim"
+)
+
+// runArena plays a tournament between bots, saves the results and prints the leaderboard
+// Usage: uno arena -bots greedy,lookahead,ismcts -format swiss -out results.json
+func runArena(args []string) error {
+	flags := flag.NewFlagSet("arena", flag.ContinueOnError)
+	bots := flags.String("bots", strings.Join(bot.Names, ","), "comma separated bots, from "+strings.Join(bot.Names, ", ")+" or "+engine.Prefix+"<command>")
+	format := flags.String("format", string(arena.RoundRobin), "pairings, "+string(arena.RoundRobin)+" or "+string(arena.Swiss))
+	rounds := flags.Int("rounds", 0, "swiss rounds, 0 for enough to tell the bots apart")
+	deals := flags.Int("deals", 50, "deals per pairing, each played from both seats")
+	rules := flags.String("rules", "", "JSON file with the house rules")
+	seed := flags.Uint64("seed", uint64(time.Now().UnixNano()), "seed for dealing and bot choices")
+	workers := flags.Int("workers", 0, "games played at once, 0 for one per CPU core")
+	maxMoves := flags.Int("max-moves", 10000, "moves after which a game counts as unfinished")
+	out := flags.String("out", "arena.json", "file to save the results to")
+	show := flags.String("show", "", "print the leaderboard of a saved results file instead of playing")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *show != "" {
+		results, err := arena.Load(*show)
+		if err != nil {
+			return err
+		}
+		results.Print(os.Stdout)
+		return nil
+	}
+
+	cfg := arena.Config{
+		Bots:     strings.Split(*bots, ","),
+		Format:   arena.Format(*format),
+		Rounds:   *rounds,
+		Deals:    *deals,
+		Seed:     *seed,
+		Workers:  *workers,
+		MaxMoves: *maxMoves,
+		NewBot:   engine.ByName,
+	}
+
+	if *rules != "" {
+		opts, err := sim.LoadOptions(*rules)
+		if err != nil {
+			return err
+		}
+		cfg.Options = opts
+	}
+
+	start := time.Now()
+	results, err := arena.Run(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := results.Save(*out); err != nil {
+		return err
+	}
+
+	results.Print(os.Stdout)
+	fmt.Printf("\nSeed %d, %v, saved to %s\n", cfg.Seed, time.Since(start).Round(time.Millisecond), *out)
+	return nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/engine"
+)
+
+// runEngine plays a built-in bot through the engine protocol on stdin and stdout
+// Usage: uno engine -bot ismcts
+func runEngine(args []string) error {
+	flags := flag.NewFlagSet("engine", flag.ContinueOnError)
+	name := flags.String("bot", "greedy", "bot to play, one of "+strings.Join(bot.Names, ", "))
+	seed := flags.Uint64("seed", 1, "seed for the bot's choices")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	b, err := bot.ByName(*name, *seed)
+	if err != nil {
+		return err
+	}
+	return engine.Serve(b, os.Stdin, os.Stdout)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/env"
+	"github.com/vtigo/uno-clone/sim"
+)
+
+// runEnv serves the reinforcement learning environment as JSON lines over stdin and stdout
+// Usage: uno env -opponents greedy -rules rules.json
+func runEnv(args []string) error {
+	flags := flag.NewFlagSet("env", flag.ContinueOnError)
+	opponents := flags.String("opponents", "greedy", "comma separated bot for every other seat, one of "+strings.Join(bot.Names, ", "))
+	rules := flags.String("rules", "", "JSON file with the house rules")
+	seat := flags.Int("seat", 0, "the agent's seat")
+	maxSteps := flags.Int("max-steps", 1000, "agent steps after which a game is cut off")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := env.Config{
+		Opponents: strings.Split(*opponents, ","),
+		Seat:      *seat,
+		MaxSteps:  *maxSteps,
+	}
+
+	if *rules != "" {
+		opts, err := sim.LoadOptions(*rules)
+		if err != nil {
+			return err
+		}
+		cfg.Options = opts
+	}
+
+	e, err := env.New(cfg)
+	if err != nil {
+		return err
+	}
+	return env.Serve(e, os.Stdin, os.Stdout)
+}
@@ -0,0 +1,52 @@
+// Command uno runs everything that needs no game window: bot simulations and
+// tournaments, the protocols for engines and training agents, and playing in a
+// terminal. It does not link Ebiten, so it builds without cgo and runs on
+// headless machines
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// commands maps each command to the function running it with the arguments after its name
+var commands = map[string]func(args []string) error{
+	"arena":    runArena,
+	"engine":   runEngine,
+	"env":      runEnv,
+	"simulate": runSimulate,
+	"ssh":      runSSH,
+	"tui":      runTUI,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+	}
+
+	err := run(os.Args[2:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// usage lists the commands and exits
+func usage() {
+	names := slices.Sorted(maps.Keys(commands))
+	fmt.Fprintf(os.Stderr, "Usage: uno <command> [flags]\n\nCommands: %s\nRun uno <command> -h for the flags of a command\n", strings.Join(names, ", "))
+	os.Exit(2)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/engine"
+	"github.com/vtigo/uno-clone/sim"
+)
+
+// runSimulate plays bot-vs-bot games without opening a window and prints the statistics
+// Usage: uno simulate -games 100000 -bots greedy,random -rules rules.json
+func runSimulate(args []string) error {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	games := flags.Int("games", 1000, "number of games to play")
+	bots := flags.String("bots", "greedy,random", "comma separated bot for each seat, one of "+strings.Join(bot.Names, ", ")+" or "+engine.Prefix+"<command>")
+	rules := flags.String("rules", "", "JSON file with the house rules")
+	seed := flags.Uint64("seed", uint64(time.Now().UnixNano()), "seed for dealing and bot choices")
+	workers := flags.Int("workers", 0, "games played at once, 0 for one per CPU core")
+	maxMoves := flags.Int("max-moves", 10000, "moves after which a game is abandoned")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := sim.Config{
+		Games:    *games,
+		Bots:     strings.Split(*bots, ","),
+		Seed:     *seed,
+		Workers:  *workers,
+		MaxMoves: *maxMoves,
+		NewBot:   engine.ByName,
+	}
+
+	if *rules != "" {
+		opts, err := sim.LoadOptions(*rules)
+		if err != nil {
+			return err
+		}
+		cfg.Options = opts
+	}
+
+	start := time.Now()
+	stats, err := sim.Run(cfg)
+	if err != nil {
+		return err
+	}
+
+	stats.Print(os.Stdout)
+	fmt.Printf("\nSeed %d, %v\n", cfg.Seed, time.Since(start).Round(time.Millisecond))
+	return nil
+}
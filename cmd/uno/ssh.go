@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+	"github.com/vtigo/uno-clone/lobby"
+	"github.com/vtigo/uno-clone/sim"
+)
+
+// runSSH serves games to anyone connecting with an SSH client. There is no
+// authentication, anyone who reaches the address gets in under any name
+// Usage: uno ssh -addr :2222 -rules rules.json
+func runSSH(args []string) error {
+	flags := flag.NewFlagSet("ssh", flag.ContinueOnError)
+	addr := flags.String("addr", ":2222", "address to listen on, every network interface by default. Anyone reaching it can play without a password, use 127.0.0.1:2222 to keep the server to this machine")
+	hostKey := flags.String("host-key", "uno_host_key", "file with the server's private key, created on first run")
+	rules := flags.String("rules", "", "JSON file with the house rules")
+	botName := flags.String("bot", "greedy", "bot to play against and to take over seats people leave, one of "+strings.Join(bot.Names, ", "))
+	seed := flags.Uint64("seed", uint64(time.Now().UnixNano()), "seed for dealing")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var opts game.GameOptions
+	if *rules != "" {
+		var err error
+		if opts, err = sim.LoadOptions(*rules); err != nil {
+			return err
+		}
+	}
+
+	key, err := lobby.LoadHostKey(*hostKey)
+	if err != nil {
+		return err
+	}
+	server, err := lobby.New(lobby.Config{Options: opts, Bot: *botName, Seed: *seed}, key)
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Listening on %s\n", l.Addr())
+	fmt.Fprintln(os.Stderr, "Warning: there is no authentication, anyone who can reach this address can connect under any name. Only serve networks you trust")
+	return server.Serve(l)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+	"github.com/vtigo/uno-clone/lobby"
+	"github.com/vtigo/uno-clone/sim"
+	"github.com/vtigo/uno-clone/tui"
+)
+
+// runTUI plays in the terminal, for machines where the game window cannot open,
+// against bots or in the lobby of an ssh server
+// Usage: uno tui -opponents greedy -rules rules.json
+//
+//	uno tui -connect uno.local:2222
+func runTUI(args []string) error {
+	flags := flag.NewFlagSet("tui", flag.ContinueOnError)
+	name := flags.String("name", defaultName(), "your name at the table")
+	opponents := flags.String("opponents", "greedy", "comma separated bot for every other seat, one of "+strings.Join(bot.Names, ", "))
+	rules := flags.String("rules", "", "JSON file with the house rules")
+	seed := flags.Uint64("seed", uint64(time.Now().UnixNano()), "seed for dealing and bot choices")
+	plain := flags.Bool("plain", os.Getenv("NO_COLOR") != "", "draw without colors")
+	connect := flags.String("connect", "", "host:port of an ssh server to play on instead of against local bots")
+	knownHosts := flags.String("known-hosts", defaultKnownHosts(), "known_hosts file checked for the server's key with -connect")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *connect != "" {
+		return join(*connect, *name, *knownHosts)
+	}
+
+	var opts game.GameOptions
+	if *rules != "" {
+		var err error
+		if opts, err = sim.LoadOptions(*rules); err != nil {
+			return err
+		}
+	}
+
+	g, err := tui.NewLocal(*name, strings.Split(*opponents, ","), opts, *seed)
+	if err != nil {
+		return err
+	}
+	return tui.New(g, tui.NewLineTerminal(os.Stdin, os.Stdout), !*plain).Run()
+}
+
+// join plays in the lobby of the server at addr. The server edits lines itself,
+// so a terminal is switched to raw mode for the session
+func join(addr, name, knownHosts string) error {
+	hostKey, err := lobby.KnownHosts(knownHosts, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	width, height := 0, 0
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		if width, height, err = term.GetSize(int(os.Stdout.Fd())); err != nil {
+			width, height = 80, 24
+		}
+
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer term.Restore(fd, state)
+	}
+	return lobby.Join(addr, name, hostKey, os.Stdin, os.Stdout, width, height)
+}
+
+// defaultName returns the user's login name, which is what people go by on shared machines
+func defaultName() string {
+	for _, key := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return name
+		}
+	}
+	return "You"
+}
+
+// defaultKnownHosts returns the known_hosts file of OpenSSH
+func defaultKnownHosts() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "known_hosts"
+	}
+	return filepath.Join(home, ".ssh", "known_hosts")
+}
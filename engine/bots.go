@@ -0,0 +1,27 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/vtigo/uno-clone/bot"
+)
+
+// Prefix marks a bot name as a command to start an engine with
+// "engine:./mybot --fast" runs ./mybot with the argument --fast
+const Prefix = "engine:"
+
+// ByName creates a built-in bot like bot.ByName, or starts an engine for names
+// with the engine prefix. Engines must be closed after the game
+func ByName(name string, seed uint64) (bot.Bot, error) {
+	command, ok := strings.CutPrefix(name, Prefix)
+	if !ok {
+		return bot.ByName(name, seed)
+	}
+
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, errors.New("missing command after " + Prefix)
+	}
+	return Start(fields[0], fields[1:]...)
+}
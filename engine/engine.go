@@ -0,0 +1,333 @@
+// Package engine lets bots written in any language play through a line based text
+// protocol over stdin and stdout, in the spirit of the UCI protocol for chess engines
+//
+// The game sends, one command per line:
+//
+//	uno <version>    Start of the session, version being ProtocolVersion. The engine
+//	                 answers with optional "id name <name>" and "id author <author>"
+//	                 lines, then "unook"
+//	isready          The engine answers "readyok" once it can take commands
+//	newgame          A new game starts
+//	event <json>     A public game.Event as JSON, nothing to answer
+//	view <json>      The engine's game.PlayerView as JSON, legal moves included
+//	go movetime <ms> The engine answers "move <i>" within the time, i being the
+//	                 index of its choice in the LegalMoves of the last view
+//	quit             The engine should exit
+//
+// Engines may send "info <text>" lines at any time, they are ignored
+//
+// In the JSON, cards are strings in the compact notation of game.ParseCard, like
+// "R7", "BS", "Y+2" or "W+4", card colors are lowercase names ("red", "blue",
+// "green", "yellow" or "wild") and card types short names like "draw-two" or
+// "wild-draw-four". Phases and move kinds stay numbers. A view looks like
+//
+//	{"Seat":0,"Hands":[["R5","GR"],null],"HandSizes":[2,7],"TopCard":"B4",
+//	 "ActiveColor":"blue","LegalMoves":[{"Kind":1,"Player":0,"CardIndex":0,
+//	 "Color":"red","Target":0}],...}
+//
+// Version 1 of the protocol sent cards as {"Color":0,"Type":0,"Value":7} objects
+// and colors as numbers
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// ProtocolVersion is sent with the uno command, it changes whenever the messages do
+const ProtocolVersion = 2
+
+const (
+	handshakeTimeout = 5 * time.Second
+	moveGrace        = 250 * time.Millisecond // Allowance on top of the move time for the round trip
+	quitTimeout      = time.Second
+)
+
+var errClosed = errors.New("engine closed its output")
+
+// Engine is a bot played by an external program. It fits any seat of a bot.Table
+// When the program fails to answer in time or answers nonsense, the move is made
+// by the fallback bot and the error is kept for Err
+type Engine struct {
+	MoveTime time.Duration // Time the engine gets for a move, 0 for a second
+	Fallback bot.Bot       // Moves for a failing engine, greedy when nil
+
+	name   string
+	cmd    *exec.Cmd
+	w      io.Writer
+	lines  chan string
+	err    error
+	broken bool // The engine stopped talking, every move falls back
+
+	unanswered bool // The engine has not answered the last go yet
+}
+
+// Start runs the program and shakes hands with it
+func Start(command string, args ...string) (*Engine, error) {
+	cmd := exec.Command(command, args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start engine %s: %v", command, err)
+	}
+
+	e, err := newEngine(stdout, stdin, cmd)
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+	return e, nil
+}
+
+// New talks to an engine that reads commands from w and answers on r
+// and shakes hands with it
+func New(r io.Reader, w io.Writer) (*Engine, error) {
+	return newEngine(r, w, nil)
+}
+
+func newEngine(r io.Reader, w io.Writer, cmd *exec.Cmd) (*Engine, error) {
+	e := &Engine{name: "engine", cmd: cmd, w: w, lines: make(chan string, 16)}
+
+	// Read in the background so a silent engine can be timed out
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			e.lines <- strings.TrimSpace(scanner.Text())
+		}
+		close(e.lines)
+	}()
+
+	deadline := time.Now().Add(handshakeTimeout)
+	if err := e.send(fmt.Sprintf("uno %d", ProtocolVersion), deadline); err != nil {
+		return nil, err
+	}
+
+	for {
+		line, err := e.receive(deadline)
+		if err != nil {
+			return nil, fmt.Errorf("engine handshake failed: %v", err)
+		}
+
+		if name, ok := strings.CutPrefix(line, "id name "); ok {
+			e.name = name
+		}
+		if line == "unook" {
+			return e, nil
+		}
+	}
+}
+
+// Name returns the name the engine gave itself
+func (e *Engine) Name() string {
+	return e.name
+}
+
+// Err returns the last error the engine made, nil if it never failed
+func (e *Engine) Err() error {
+	return e.err
+}
+
+// NewGame tells the engine a new game starts
+func (e *Engine) NewGame() error {
+	return e.send("newgame", time.Now().Add(handshakeTimeout))
+}
+
+// Ready waits until the engine answers isready
+func (e *Engine) Ready() error {
+	deadline := time.Now().Add(handshakeTimeout)
+	if err := e.send("isready", deadline); err != nil {
+		return err
+	}
+
+	for {
+		line, err := e.receive(deadline)
+		if err != nil {
+			return err
+		}
+		if line == "readyok" {
+			return nil
+		}
+	}
+}
+
+// Observe forwards a public event to the engine
+func (e *Engine) Observe(event game.Event) {
+	if e.broken {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		e.fail(err)
+		return
+	}
+	if err := e.send("event "+string(data), time.Now().Add(handshakeTimeout)); err != nil {
+		e.fail(err)
+	}
+}
+
+// ChooseMove asks the engine for a move, falling back if it does not answer properly
+func (e *Engine) ChooseMove(view game.PlayerView) game.Move {
+	if !e.broken {
+		move, err := e.ask(view)
+		if err == nil {
+			return move
+		}
+		e.fail(err)
+	}
+
+	if e.Fallback == nil {
+		e.Fallback = bot.NewGreedyBot()
+	}
+	return e.Fallback.ChooseMove(view)
+}
+
+// ask sends the view and waits for the engine's move
+func (e *Engine) ask(view game.PlayerView) (game.Move, error) {
+	moveTime := e.MoveTime
+	if moveTime <= 0 {
+		moveTime = time.Second
+	}
+
+	// The engine answers in order, so a late answer to an earlier question
+	// comes before its readyok and is skipped by Ready
+	if e.unanswered {
+		if err := e.Ready(); err != nil {
+			return game.Move{}, err
+		}
+		e.unanswered = false
+	}
+
+	data, err := json.Marshal(view)
+	if err != nil {
+		return game.Move{}, err
+	}
+	deadline := time.Now().Add(moveTime + moveGrace)
+	if err := e.send("view "+string(data), deadline); err != nil {
+		return game.Move{}, err
+	}
+	if err := e.send(fmt.Sprintf("go movetime %d", moveTime.Milliseconds()), deadline); err != nil {
+		return game.Move{}, err
+	}
+
+	e.unanswered = true
+	for {
+		line, err := e.receive(deadline)
+		if err != nil {
+			return game.Move{}, err
+		}
+
+		answer, ok := strings.CutPrefix(line, "move ")
+		if !ok {
+			continue
+		}
+		e.unanswered = false
+
+		i, err := strconv.Atoi(strings.TrimSpace(answer))
+		if err != nil || i < 0 || i >= len(view.LegalMoves) {
+			return game.Move{}, fmt.Errorf("engine answered %q, expected a move between 0 and %d", line, len(view.LegalMoves)-1)
+		}
+		return view.LegalMoves[i], nil
+	}
+}
+
+// Close asks the engine to quit and stops its program if it does not
+func (e *Engine) Close() error {
+	if !e.broken {
+		e.send("quit", time.Now().Add(quitTimeout))
+	}
+	if closer, ok := e.w.(io.Closer); ok {
+		closer.Close()
+	}
+
+	if e.cmd == nil {
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- e.cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(quitTimeout):
+		e.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+// send writes one command unless the engine stops reading before the deadline
+// An engine that cannot be written to is broken
+func (e *Engine) send(line string, deadline time.Time) error {
+	written := make(chan error, 1)
+	go func() {
+		_, err := io.WriteString(e.w, line+"\n")
+		written <- err
+	}()
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case err := <-written:
+		if err != nil {
+			e.broken = true
+		}
+		return err
+	case <-timer.C:
+		// The write is still waiting, nothing else may be written after it
+		e.broken = true
+		return errors.New("engine did not read its input in time")
+	}
+}
+
+// receive returns the next line of the engine, skipping info lines
+func (e *Engine) receive(deadline time.Time) (string, error) {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	for {
+		select {
+		case line, ok := <-e.lines:
+			if !ok {
+				return "", errClosed
+			}
+			if line == "" || strings.HasPrefix(line, "info") {
+				continue
+			}
+			return line, nil
+		case <-timer.C:
+			return "", errors.New("engine did not answer in time")
+		}
+	}
+}
+
+// fail records an error. An engine that stopped talking is not asked again
+func (e *Engine) fail(err error) {
+	e.err = err
+	if errors.Is(err, errClosed) {
+		e.broken = true
+	}
+}
@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+	"github.com/vtigo/uno-clone/sim"
+)
+
+// The test binary doubles as an engine when started with UNO_TEST_ENGINE set
+func TestMain(m *testing.M) {
+	if os.Getenv("UNO_TEST_ENGINE") != "" {
+		if err := Serve(bot.NewGreedyBot(), os.Stdin, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// connect runs the fake engine on the other end of a pair of pipes
+func connect(t *testing.T, fake func(commands io.Reader, answers io.Writer)) *Engine {
+	commandsR, commandsW := io.Pipe()
+	answersR, answersW := io.Pipe()
+	go func() {
+		fake(commandsR, answersW)
+		answersW.Close()
+		io.Copy(io.Discard, commandsR)
+	}()
+
+	e, err := New(answersR, commandsW)
+	if err != nil {
+		t.Fatalf("Expected no error connecting, got %v", err)
+	}
+	return e
+}
+
+func newView(t *testing.T) game.PlayerView {
+	players := []*game.Player{game.NewPlayer("Player 1"), game.NewPlayer("Player 2")}
+	state, err := game.NewGameStateWithRandom(players, game.GameOptions{}, rand.New(rand.NewPCG(4, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state.View(state.CurrentPlayer)
+}
+
+func TestEngine(t *testing.T) {
+	e := connect(t, func(commands io.Reader, answers io.Writer) {
+		Serve(bot.NewGreedyBot(), commands, answers)
+	})
+
+	if e.Name() != "greedy" {
+		t.Errorf("Expected the engine to call itself greedy, got %q", e.Name())
+	}
+
+	if err := e.Ready(); err != nil {
+		t.Errorf("Expected the engine to be ready, got %v", err)
+	}
+
+	view := newView(t)
+	e.Observe(game.Event{Kind: game.EventDraw, Player: 1, Count: 1})
+	move := e.ChooseMove(view)
+	if expected := bot.NewGreedyBot().ChooseMove(view); move != expected {
+		t.Errorf("Expected the greedy move %v, got %v", expected, move)
+	}
+
+	if e.Err() != nil {
+		t.Errorf("Expected no error, got %v", e.Err())
+	}
+	e.Close()
+}
+
+func TestEngineFailures(t *testing.T) {
+	view := newView(t)
+	fallback := bot.NewGreedyBot().ChooseMove(view)
+
+	answers := map[string]string{
+		"nonsense": "move 9999",
+		"silent":   "",
+	}
+	for name, answer := range answers {
+		e := connect(t, func(commands io.Reader, answers io.Writer) {
+			fmt.Fprintln(answers, "unook")
+			scanner := bufio.NewScanner(commands)
+			scanner.Buffer(nil, 1024*1024)
+			for scanner.Scan() {
+				if strings.HasPrefix(scanner.Text(), "go") && answer != "" {
+					fmt.Fprintln(answers, answer)
+				}
+			}
+		})
+		e.MoveTime = 10 * time.Millisecond
+
+		if move := e.ChooseMove(view); move != fallback {
+			t.Errorf("Expected the fallback move from a %s engine, got %v", name, move)
+		}
+		if e.Err() == nil {
+			t.Errorf("Expected an error from a %s engine", name)
+		}
+		e.Close()
+	}
+
+	// An engine that quits is not asked again
+	e := connect(t, func(commands io.Reader, answers io.Writer) {
+		fmt.Fprintln(answers, "unook")
+	})
+	e.ChooseMove(view)
+	if !e.broken {
+		t.Error("Expected an engine that closed its output to be broken")
+	}
+
+	// The session starts with the protocol version
+	hello := make(chan string, 1)
+	e = connect(t, func(commands io.Reader, answers io.Writer) {
+		line, _ := bufio.NewReader(commands).ReadString('\n')
+		hello <- strings.TrimSpace(line)
+		fmt.Fprintln(answers, "unook")
+	})
+	if line := <-hello; line != fmt.Sprintf("uno %d", ProtocolVersion) {
+		t.Errorf("Expected the session to start with the protocol version, got %q", line)
+	}
+	e.Close()
+
+	// No handshake, no engine
+	_, err := New(strings.NewReader("hello\n"), io.Discard)
+	if err == nil {
+		t.Error("Expected error for an engine that never answers unook")
+	}
+}
+
+// Test that an answer coming after its time is not taken for the answer to the next question
+func TestEngineLateAnswer(t *testing.T) {
+	view := newView(t)
+	if len(view.LegalMoves) < 2 {
+		t.Fatalf("Expected a view with two legal moves, got %v", view.LegalMoves)
+	}
+
+	e := connect(t, func(commands io.Reader, answers io.Writer) {
+		fmt.Fprintln(answers, "unook")
+		scanner := bufio.NewScanner(commands)
+		scanner.Buffer(nil, 1024*1024)
+		late := true
+		for scanner.Scan() {
+			switch {
+			case strings.HasPrefix(scanner.Text(), "go") && late:
+				// The first answer comes well after the move time
+				time.Sleep(moveGrace + 100*time.Millisecond)
+				fmt.Fprintln(answers, "move 0")
+				late = false
+			case strings.HasPrefix(scanner.Text(), "go"):
+				fmt.Fprintln(answers, "move 1")
+			case scanner.Text() == "isready":
+				fmt.Fprintln(answers, "readyok")
+			}
+		}
+	})
+	defer e.Close()
+	e.MoveTime = 10 * time.Millisecond
+
+	e.ChooseMove(view)
+	if e.Err() == nil {
+		t.Fatal("Expected an error for the late answer")
+	}
+	if move := e.ChooseMove(view); move != view.LegalMoves[1] {
+		t.Errorf("Expected the answer to the second question %v, got %v", view.LegalMoves[1], move)
+	}
+}
+
+// Test that an engine that stops reading its commands cannot block the game
+func TestEngineNotReading(t *testing.T) {
+	view := newView(t)
+	stop := make(chan struct{})
+	e := connect(t, func(commands io.Reader, answers io.Writer) {
+		bufio.NewReader(commands).ReadString('\n')
+		fmt.Fprintln(answers, "unook")
+		<-stop
+	})
+	e.MoveTime = 10 * time.Millisecond
+
+	done := make(chan game.Move, 1)
+	go func() {
+		done <- e.ChooseMove(view)
+	}()
+
+	select {
+	case move := <-done:
+		if expected := bot.NewGreedyBot().ChooseMove(view); move != expected {
+			t.Errorf("Expected the fallback move %v, got %v", expected, move)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the move to fall back while the engine does not read")
+	}
+	if !e.broken {
+		t.Error("Expected an engine that does not read to be broken")
+	}
+
+	close(stop)
+	e.Close()
+}
+
+func TestStart(t *testing.T) {
+	t.Setenv("UNO_TEST_ENGINE", "1")
+
+	cfg := sim.Config{
+		Games:  4,
+		Bots:   []string{Prefix + os.Args[0], "random"},
+		Seed:   1,
+		NewBot: ByName,
+	}
+	stats, err := sim.Run(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error simulating with an engine, got %v", err)
+	}
+
+	if stats.Wins[0]+stats.Wins[1]+stats.Unfinished != 4 {
+		t.Errorf("Expected 4 games, got %+v", stats)
+	}
+
+	if _, err := ByName(Prefix, 0); err == nil {
+		t.Error("Expected error for a missing command")
+	}
+
+	if _, err := ByName(Prefix+"./no-such-engine", 0); err == nil {
+		t.Error("Expected error for a missing program")
+	}
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Serve plays the engine side of the protocol with a built-in bot, reading commands
+// from r and answering on w until quit or the end of r. It doubles as a reference
+// for engine authors and as an opponent to test engines against
+func Serve(b bot.Bot, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var view game.PlayerView
+	hasView := false
+
+	for scanner.Scan() {
+		command, arg, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+
+		var err error
+		switch command {
+		case "uno":
+			_, err = fmt.Fprintf(w, "id name %s\nunook\n", b.Name())
+		case "isready":
+			_, err = fmt.Fprintln(w, "readyok")
+		case "newgame":
+			hasView = false
+		case "event":
+			var event game.Event
+			if json.Unmarshal([]byte(arg), &event) == nil {
+				if observer, ok := b.(bot.Observer); ok {
+					observer.Observe(event)
+				}
+			}
+		case "view":
+			view = game.PlayerView{}
+			if err := json.Unmarshal([]byte(arg), &view); err != nil {
+				fmt.Fprintf(w, "info invalid view: %v\n", err)
+				hasView = false
+				continue
+			}
+			hasView = true
+		case "go":
+			if !hasView || len(view.LegalMoves) == 0 {
+				_, err = fmt.Fprintln(w, "info no view to move in")
+				break
+			}
+			_, err = fmt.Fprintf(w, "move %d\n", moveIndex(view, b.ChooseMove(view)))
+		case "quit":
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// moveIndex returns the index of the move among the view's legal moves
+func moveIndex(view game.PlayerView, move game.Move) int {
+	for i, legal := range view.LegalMoves {
+		if legal == move {
+			return i
+		}
+	}
+	return 0
+}
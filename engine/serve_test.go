@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vtigo/uno-clone/bot"
+)
+
+func TestServe(t *testing.T) {
+	view := newView(t)
+	data, err := json.Marshal(view)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := strings.Join([]string{
+		"uno 2",
+		"isready",
+		"go movetime 100",
+		"newgame",
+		"view " + string(data),
+		"go movetime 100",
+		"quit",
+		"isready",
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := Serve(bot.NewGreedyBot(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Expected no error serving, got %v", err)
+	}
+
+	expected := bot.NewGreedyBot().ChooseMove(view)
+	index := -1
+	for i, move := range view.LegalMoves {
+		if move == expected {
+			index = i
+			break
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines up to quit, got:\n%s", out.String())
+	}
+
+	if lines[0] != "id name greedy" || lines[1] != "unook" || lines[2] != "readyok" {
+		t.Errorf("Expected the handshake, got %q", lines[:3])
+	}
+
+	if !strings.HasPrefix(lines[3], "info") {
+		t.Errorf("Expected an info line for go without a view, got %q", lines[3])
+	}
+
+	if lines[4] != fmt.Sprintf("move %d", index) {
+		t.Errorf("Expected move %d, got %q", index, lines[4])
+	}
+}
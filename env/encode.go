@@ -0,0 +1,179 @@
+package env
+
+import "github.com/vtigo/uno-clone/game"
+
+// MaxPlayers is the largest table the encodings cover. Seats are encoded relative
+// to the agent: offset 0 is the agent, 1 the next seat in seat order and so on
+const MaxPlayers = 4
+
+// Observation layout, every block starts at the given offset
+// Card blocks count cards per kind, see game.KindOf
+const (
+	obsHand      = 0                             // Agent's hand
+	obsVisible   = obsHand + game.CardKinds      // Other hands the agent may see, partners' with PartnersSeeHands
+	obsDiscarded = obsVisible + game.CardKinds   // Discard pile, top card included
+	obsTop       = obsDiscarded + game.CardKinds // One-hot top card
+	obsColor     = obsTop + game.CardKinds       // One-hot active color
+	obsSizes     = obsColor + 4                  // Hand size per relative seat
+	obsUno       = obsSizes + MaxPlayers         // Called UNO per relative seat
+	obsSeated    = obsUno + MaxPlayers           // Whether a player sits at the relative seat
+	obsPartner   = obsSeated + MaxPlayers        // Whether the relative seat is on the agent's team
+	obsCurrent   = obsPartner + MaxPlayers       // One-hot relative seat of the current player
+	obsDrawPile  = obsCurrent + MaxPlayers       // Draw pile size
+	obsFlags     = obsDrawPile + 1               // Choosing a color, has drawn, reversed
+
+	// ObservationSize is the length of every encoded observation
+	ObservationSize = obsFlags + 3
+)
+
+// Action space. Plays of colored cards use the card's kind, wild plays and color
+// choices add the color and, for Wild Swap Hands, the relative target seat
+const (
+	actPlayWild   = 4 * 13                         // Wild, Draw Four, Shuffle Hands and Customizable by color
+	actPlaySwap   = actPlayWild + 4*4              // Swap Hands by color and target
+	actChoose     = actPlaySwap + 4*(MaxPlayers-1) // Color for a starting wild
+	actChooseSwap = actChoose + 4                  // Color and target for a starting Swap Hands
+	actDraw       = actChooseSwap + 4*(MaxPlayers-1)
+	actPass       = actDraw + 1
+	actCallUno    = actPass + 1
+	actChallenge  = actCallUno + 1 // Challenge by relative target
+
+	// ActionCount is the size of the action space
+	ActionCount = actChallenge + MaxPlayers - 1
+)
+
+// relative returns the seat's offset from the viewer, in seat order
+func relative(view game.PlayerView, seat int) int {
+	n := len(view.HandSizes)
+	return (seat - view.Seat + n) % n
+}
+
+// Encode writes the fixed-size encoding of the view into obs, which must hold
+// ObservationSize values. Counts are left as raw numbers
+func Encode(view game.PlayerView, obs []float32) {
+	clear(obs[:ObservationSize])
+
+	for seat, hand := range view.Hands {
+		block := obsVisible
+		if seat == view.Seat {
+			block = obsHand
+		}
+		for _, card := range hand {
+			obs[block+game.KindOf(card)]++
+		}
+	}
+
+	for _, card := range view.DiscardPile {
+		obs[obsDiscarded+game.KindOf(card)]++
+	}
+	obs[obsTop+game.KindOf(view.TopCard)] = 1
+	if view.ActiveColor < game.Wild {
+		obs[obsColor+int(view.ActiveColor)] = 1
+	}
+
+	for seat, size := range view.HandSizes {
+		offset := relative(view, seat)
+		if offset >= MaxPlayers {
+			continue
+		}
+
+		obs[obsSizes+offset] = float32(size)
+		obs[obsSeated+offset] = 1
+		if view.CalledUno[seat] {
+			obs[obsUno+offset] = 1
+		}
+		if view.Teams != nil && view.Teams[seat] == view.Teams[view.Seat] {
+			obs[obsPartner+offset] = 1
+		}
+	}
+
+	if offset := relative(view, view.CurrentPlayer); offset < MaxPlayers {
+		obs[obsCurrent+offset] = 1
+	}
+	obs[obsDrawPile] = float32(view.DrawPileSize)
+
+	if view.Phase == game.PhaseColorSelection {
+		obs[obsFlags] = 1
+	}
+	if view.HasDrawn {
+		obs[obsFlags+1] = 1
+	}
+	if view.Reversed {
+		obs[obsFlags+2] = 1
+	}
+}
+
+// Action returns the action index of a legal move of the view, or -1 if the move
+// does not fit the action space. Moves that only differ in which copy of a card
+// they play share an action
+func Action(view game.PlayerView, move game.Move) int {
+	target := relative(view, move.Target) - 1
+	if target < 0 || target >= MaxPlayers-1 {
+		target = -1
+	}
+
+	switch move.Kind {
+	case game.MovePlay:
+		hand := view.MyHand()
+		if move.CardIndex < 0 || move.CardIndex >= len(hand) {
+			return -1
+		}
+		card := hand[move.CardIndex]
+
+		switch {
+		case card.Color != game.Wild:
+			return game.KindOf(card)
+		case card.Type == game.WildSwapHands:
+			if target == -1 {
+				return -1
+			}
+			return actPlaySwap + int(move.Color)*(MaxPlayers-1) + target
+		default:
+			wild := int(card.Type - game.WildCard)
+			if card.Type > game.WildSwapHands {
+				wild-- // Swap Hands has its own block
+			}
+			return actPlayWild + wild*4 + int(move.Color)
+		}
+	case game.MoveChooseColor:
+		if view.TopCard.Type == game.WildSwapHands && view.LastPlayedBy != -1 {
+			if target == -1 {
+				return -1
+			}
+			return actChooseSwap + int(move.Color)*(MaxPlayers-1) + target
+		}
+		return actChoose + int(move.Color)
+	case game.MoveDraw:
+		return actDraw
+	case game.MovePass:
+		return actPass
+	case game.MoveCallUno:
+		return actCallUno
+	case game.MoveChallenge:
+		if target == -1 {
+			return -1
+		}
+		return actChallenge + target
+	default:
+		return -1
+	}
+}
+
+// Mask marks the legal actions of the view in mask, which must hold ActionCount
+// values, and returns for every legal action the move it stands for
+func Mask(view game.PlayerView, mask []bool) map[int]game.Move {
+	clear(mask[:ActionCount])
+
+	moves := make(map[int]game.Move, len(view.LegalMoves))
+	for _, move := range view.LegalMoves {
+		action := Action(view, move)
+		if action == -1 {
+			continue
+		}
+		if _, ok := moves[action]; !ok {
+			moves[action] = move
+			mask[action] = true
+		}
+	}
+	return moves
+}
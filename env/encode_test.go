@@ -0,0 +1,83 @@
+package env
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+func TestEncode(t *testing.T) {
+	players := []*game.Player{game.NewPlayer("Player 1"), game.NewPlayer("Player 2")}
+	state, err := game.NewGameStateWithRandom(players, game.GameOptions{}, rand.New(rand.NewPCG(1, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view := state.View(1)
+	obs := make([]float32, ObservationSize)
+	Encode(view, obs)
+
+	inHand := float32(0)
+	for kind := range game.CardKinds {
+		inHand += obs[obsHand+kind]
+	}
+	if int(inHand) != len(view.MyHand()) {
+		t.Errorf("Expected %d cards in the hand block, got %v", len(view.MyHand()), inHand)
+	}
+
+	if obs[obsTop+game.KindOf(view.TopCard)] != 1 {
+		t.Error("Expected the top card to be marked")
+	}
+
+	// The agent sits at offset 0, the other player at 1
+	if obs[obsSizes] != float32(view.HandSizes[1]) || obs[obsSizes+1] != float32(view.HandSizes[0]) {
+		t.Errorf("Expected hand sizes relative to the agent, got %v", obs[obsSizes:obsSizes+MaxPlayers])
+	}
+	if obs[obsSeated+2] != 0 || obs[obsSeated+1] != 1 {
+		t.Error("Expected only two seats to be marked")
+	}
+}
+
+func TestActions(t *testing.T) {
+	// Every legal move of a random modern team game gets an action of its own kind of move
+	for seed := uint64(1); seed <= 5; seed++ {
+		players := make([]*game.Player, 4)
+		for i := range players {
+			players[i] = game.NewPlayer("Player")
+		}
+		opts := game.ModernOptions()
+		opts.Teams = true
+		opts.SwapHandsCards = 2
+
+		r := rand.New(rand.NewPCG(seed, seed))
+		state, err := game.NewGameStateWithRandom(players, opts, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rules := game.NewGameRules()
+		mask := make([]bool, ActionCount)
+		for step := 0; step < 300 && state.Phase != game.PhaseGameOver; step++ {
+			for seat := range players {
+				view := state.View(seat)
+				moves := Mask(view, mask)
+
+				for _, move := range view.LegalMoves {
+					action := Action(view, move)
+					if action < 0 || action >= ActionCount || !mask[action] {
+						t.Fatalf("Expected a legal action for %v, got %d", move, action)
+					}
+					if moves[action].Kind != move.Kind {
+						t.Errorf("Expected action %d to stand for a %v move, got %v", action, move.Kind, moves[action])
+					}
+				}
+			}
+
+			legal := game.LegalMoves(state, state.CurrentPlayer)
+			if err := rules.Apply(state, legal[r.IntN(len(legal))]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
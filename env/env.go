@@ -0,0 +1,194 @@
+// Package env exposes the game as a reinforcement learning environment: one agent
+// seat plays against bots through fixed-size observations and a fixed action space
+package env
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Config describes the games of an environment
+type Config struct {
+	Opponents []string         // Bot name for every other seat, see bot.Names
+	Options   game.GameOptions // House rules for every game
+	Seat      int              // The agent's seat
+	MaxSteps  int              // Agent steps after which a game is cut off, 0 for 1000
+}
+
+// Observation is what the agent sees before acting
+type Observation struct {
+	Features []float32 // ObservationSize values, see Encode
+	Mask     []bool    // ActionCount values, true for the legal actions
+}
+
+// Env plays one game at a time with the agent at its seat
+// The agent acts whenever it has a legal move on its turn. Off-turn moves like
+// challenges are only offered when the game is waiting on the agent anyway, except
+// that a play leaving the agent one card is followed by a chance to call UNO
+// before any bot can challenge it
+type Env struct {
+	cfg Config
+
+	state *game.GameState
+	rules *game.GameRules
+	table *bot.Table
+	moves map[int]game.Move // Move behind every legal action of the current observation
+	steps int
+}
+
+// New checks the config and creates an environment, call Reset before Step
+func New(cfg Config) (*Env, error) {
+	players := len(cfg.Opponents) + 1
+	if players > MaxPlayers {
+		return nil, fmt.Errorf("at most %d players fit the encodings, got %d", MaxPlayers, players)
+	}
+
+	if cfg.Seat < 0 || cfg.Seat >= players {
+		return nil, fmt.Errorf("seat %d does not exist at a table of %d", cfg.Seat, players)
+	}
+
+	for _, name := range cfg.Opponents {
+		if _, err := bot.ByName(name, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Env{cfg: cfg}, nil
+}
+
+// Reset deals a new game and plays the bots until the agent has to act
+// The seed decides the deal, every shuffle and the bots' choices
+func (e *Env) Reset(seed uint64) (Observation, error) {
+	n := len(e.cfg.Opponents) + 1
+	players := make([]*game.Player, n)
+	seats := make([]bot.Bot, n)
+
+	opponent := 0
+	for seat := range n {
+		players[seat] = game.NewPlayer(fmt.Sprintf("Player %d", seat+1))
+		if seat == e.cfg.Seat {
+			continue
+		}
+
+		b, err := bot.ByName(e.cfg.Opponents[opponent], seed*uint64(n)+uint64(seat))
+		if err != nil {
+			return Observation{}, err
+		}
+		seats[seat] = b
+		opponent++
+	}
+
+	r := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	state, err := game.NewGameStateWithRandom(players, e.cfg.Options, r)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	e.state = state
+	e.rules = game.NewGameRules()
+	e.table, err = bot.NewTable(state, e.rules, seats)
+	if err != nil {
+		return Observation{}, err
+	}
+	e.steps = 0
+
+	obs, _, err := e.advance()
+	return obs, err
+}
+
+// Step makes the move behind the action and plays the bots until the agent has to
+// act again. The reward is 1 when the agent's team wins, -1 when another team
+// wins and 0 otherwise. Done is true once the game ended or was cut off
+func (e *Env) Step(action int) (Observation, float64, bool, error) {
+	if e.state == nil {
+		return Observation{}, 0, false, errors.New("reset the environment before stepping")
+	}
+	if e.state.Phase == game.PhaseGameOver {
+		return Observation{}, 0, true, errors.New("the game is over, reset the environment")
+	}
+
+	move, ok := e.moves[action]
+	if !ok {
+		return Observation{}, 0, false, fmt.Errorf("action %d is not legal", action)
+	}
+
+	if err := e.rules.Apply(e.state, move); err != nil {
+		return Observation{}, 0, false, err
+	}
+	e.steps++
+
+	// Bots challenge as soon as they move, so the agent calls UNO before they do
+	if move.Kind == game.MovePlay && e.mustCallUno() {
+		return e.observe(e.state.View(e.cfg.Seat)), 0, false, nil
+	}
+
+	obs, done, err := e.advance()
+	if err != nil {
+		return obs, 0, done, err
+	}
+	return obs, e.reward(), done, nil
+}
+
+// State returns the game being played, for inspection only
+func (e *Env) State() *game.GameState {
+	return e.state
+}
+
+// advance lets the bots move until the agent has a legal move or the game is done
+func (e *Env) advance() (Observation, bool, error) {
+	maxSteps := e.cfg.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = 1000
+	}
+
+	for {
+		moved, err := e.table.Step()
+		if err != nil {
+			return Observation{}, false, err
+		}
+
+		view := e.state.View(e.cfg.Seat)
+		if e.state.Phase == game.PhaseGameOver || e.steps >= maxSteps {
+			return e.observe(view), true, nil
+		}
+
+		if !moved {
+			if len(view.LegalMoves) == 0 {
+				return Observation{}, false, errors.New("the game is stuck with nobody to move")
+			}
+			return e.observe(view), false, nil
+		}
+	}
+}
+
+// mustCallUno checks if the agent is down to one card without having called UNO
+func (e *Env) mustCallUno() bool {
+	agent := e.state.Players[e.cfg.Seat]
+	return e.state.Phase != game.PhaseGameOver && agent.ShouldCallUno() && !agent.HasCalledUno
+}
+
+// observe encodes the view and remembers the moves behind its legal actions
+func (e *Env) observe(view game.PlayerView) Observation {
+	obs := Observation{
+		Features: make([]float32, ObservationSize),
+		Mask:     make([]bool, ActionCount),
+	}
+	Encode(view, obs.Features)
+	e.moves = Mask(view, obs.Mask)
+	return obs
+}
+
+func (e *Env) reward() float64 {
+	switch e.state.WinningTeam() {
+	case -1:
+		return 0
+	case e.state.TeamOf(e.cfg.Seat):
+		return 1
+	default:
+		return -1
+	}
+}
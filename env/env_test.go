@@ -0,0 +1,166 @@
+package env
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// playRandom plays a game with random legal actions and returns the final reward and the actions taken
+func playRandom(t *testing.T, e *Env, seed uint64) (float64, []int) {
+	obs, err := e.Reset(seed)
+	if err != nil {
+		t.Fatalf("Expected no error resetting, got %v", err)
+	}
+
+	r := rand.New(rand.NewPCG(seed, 0))
+	var actions []int
+	for {
+		var legal []int
+		for action, ok := range obs.Mask {
+			if ok {
+				legal = append(legal, action)
+			}
+		}
+		if len(legal) == 0 {
+			t.Fatal("Expected a legal action")
+		}
+
+		action := legal[r.IntN(len(legal))]
+		actions = append(actions, action)
+
+		var reward float64
+		var done bool
+		obs, reward, done, err = e.Step(action)
+		if err != nil {
+			t.Fatalf("Expected no error stepping, got %v", err)
+		}
+		if len(obs.Features) != ObservationSize || len(obs.Mask) != ActionCount {
+			t.Fatalf("Expected fixed-size observations, got %d and %d", len(obs.Features), len(obs.Mask))
+		}
+
+		if done {
+			return reward, actions
+		}
+		if reward != 0 {
+			t.Errorf("Expected no reward before the end, got %v", reward)
+		}
+	}
+}
+
+func TestEnv(t *testing.T) {
+	e, err := New(Config{Opponents: []string{"greedy"}, Options: game.ModernOptions(), Seat: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wins, losses := 0, 0
+	for seed := uint64(1); seed <= 20; seed++ {
+		reward, _ := playRandom(t, e, seed)
+		switch reward {
+		case 1:
+			wins++
+		case -1:
+			losses++
+		}
+	}
+	if wins+losses == 0 {
+		t.Error("Expected some games to end")
+	}
+
+	// The seed decides everything
+	_, first := playRandom(t, e, 7)
+	_, again := playRandom(t, e, 7)
+	if !reflect.DeepEqual(first, again) {
+		t.Error("Expected the same game for the same seed")
+	}
+
+	if _, _, _, err := e.Step(-1); err == nil {
+		t.Error("Expected error stepping a finished game")
+	}
+}
+
+func TestEnvErrors(t *testing.T) {
+	if _, err := New(Config{Opponents: []string{"cheater"}}); err == nil {
+		t.Error("Expected error for an unknown bot")
+	}
+
+	if _, err := New(Config{Opponents: []string{"greedy"}, Seat: 2}); err == nil {
+		t.Error("Expected error for a missing seat")
+	}
+
+	if _, err := New(Config{Opponents: []string{"greedy", "greedy", "greedy", "greedy"}}); err == nil {
+		t.Error("Expected error for too many players")
+	}
+
+	e, err := New(Config{Opponents: []string{"random"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := e.Step(0); err == nil {
+		t.Error("Expected error stepping before a reset")
+	}
+
+	obs, err := e.Reset(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for action, ok := range obs.Mask {
+		if !ok {
+			if _, _, _, err := e.Step(action); err == nil {
+				t.Errorf("Expected error for illegal action %d", action)
+			}
+			break
+		}
+	}
+}
+
+// Test that a play down to one card lets the agent call UNO before the bots can challenge
+func TestEnvUnoWindow(t *testing.T) {
+	e, err := New(Config{Opponents: []string{"greedy"}, Options: game.ModernOptions()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	for seed := uint64(1); seed <= 20; seed++ {
+		obs, err := e.Reset(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := rand.New(rand.NewPCG(seed, 1))
+		for done := false; !done; {
+			agent := e.State().Players[0]
+			if agent.ShouldCallUno() && !agent.HasCalledUno && e.State().Phase != game.PhaseGameOver {
+				if !obs.Mask[actCallUno] {
+					t.Fatalf("Expected the agent to be offered an UNO call in game %d", seed)
+				}
+				if obs, _, done, err = e.Step(actCallUno); err != nil {
+					t.Fatal(err)
+				}
+				if !agent.HasCalledUno && agent.Hand.Len() == 1 {
+					t.Fatalf("Expected the agent's UNO call to stand in game %d", seed)
+				}
+				calls++
+				continue
+			}
+
+			var legal []int
+			for action, ok := range obs.Mask {
+				if ok && action != actCallUno {
+					legal = append(legal, action)
+				}
+			}
+			if obs, _, done, err = e.Step(legal[r.IntN(len(legal))]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if calls == 0 {
+		t.Error("Expected the agent to get down to one card in some game")
+	}
+}
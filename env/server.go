@@ -0,0 +1,80 @@
+package env
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Request is one line sent to Serve
+//
+//	{"cmd": "spec"}
+//	{"cmd": "reset", "seed": 42}
+//	{"cmd": "step", "action": 7}
+type Request struct {
+	Cmd    string `json:"cmd"`
+	Seed   uint64 `json:"seed,omitempty"`
+	Action int    `json:"action,omitempty"`
+}
+
+// Response is the line Serve writes back for every request
+// Error is set instead of the other fields when the request failed
+type Response struct {
+	Observation     []float32 `json:"observation,omitempty"`
+	Mask            []bool    `json:"mask,omitempty"`
+	Reward          float64   `json:"reward"`
+	Done            bool      `json:"done"`
+	ObservationSize int       `json:"observationSize,omitempty"`
+	ActionCount     int       `json:"actionCount,omitempty"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Serve drives the environment with one JSON request per line from r and writes
+// one JSON response per line to w, until r ends. Training code in any language can
+// run the game as a child process this way
+func Serve(e *Env, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if err := encoder.Encode(e.handle(scanner.Bytes())); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// handle answers one request line
+func (e *Env) handle(line []byte) Response {
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return Response{Error: fmt.Sprintf("invalid request: %v", err)}
+	}
+
+	var obs Observation
+	var response Response
+	var err error
+
+	switch req.Cmd {
+	case "spec":
+		return Response{ObservationSize: ObservationSize, ActionCount: ActionCount}
+	case "reset":
+		obs, err = e.Reset(req.Seed)
+	case "step":
+		obs, response.Reward, response.Done, err = e.Step(req.Action)
+	default:
+		return Response{Error: fmt.Sprintf("unknown command %q, expected spec, reset or step", req.Cmd)}
+	}
+
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+	response.Observation = obs.Features
+	response.Mask = obs.Mask
+	return response
+}
@@ -0,0 +1,54 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	e, err := New(Config{Opponents: []string{"greedy"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := strings.Join([]string{
+		`{"cmd": "spec"}`,
+		`{"cmd": "reset", "seed": 5}`,
+		`{"cmd": "step", "action": -3}`,
+		`{"cmd": "fly"}`,
+		`not json`,
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := Serve(e, strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Expected no error serving, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 responses, got %d:\n%s", len(lines), out.String())
+	}
+
+	responses := make([]Response, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &responses[i]); err != nil {
+			t.Fatalf("Expected JSON responses, got %q", line)
+		}
+	}
+
+	if responses[0].ObservationSize != ObservationSize || responses[0].ActionCount != ActionCount {
+		t.Errorf("Expected the spec, got %+v", responses[0])
+	}
+
+	if len(responses[1].Observation) != ObservationSize || len(responses[1].Mask) != ActionCount || responses[1].Error != "" {
+		t.Errorf("Expected an observation after the reset, got %+v", responses[1])
+	}
+
+	for i := 2; i < 5; i++ {
+		if responses[i].Error == "" {
+			t.Errorf("Expected an error for request %d, got %+v", i+1, responses[i])
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// This file only uses what the engine offered before the allocation-free redesign,
+// so compare_baseline.sh can copy it into that revision and time the same games there
+
+// randomLegalMove calls UNO when needed, never challenges and draws or passes only
+// when nothing else is possible. Every play is as likely as the others
+func randomLegalMove(legal []Move, r *rand.Rand) Move {
+	first, plays := 0, 0
+	for i, candidate := range legal {
+		if candidate.Kind == MoveCallUno {
+			return candidate
+		}
+		if candidate.Kind == MovePlay || candidate.Kind == MoveChooseColor {
+			if plays == 0 {
+				first = i
+			}
+			plays++
+		}
+	}
+	if plays == 0 {
+		return legal[len(legal)-1]
+	}
+	return legal[first+r.IntN(plays)]
+}
+
+// BenchmarkLegalGame plays whole games through LegalMoves and Apply
+func BenchmarkLegalGame(b *testing.B) {
+	rules := NewGameRules()
+	r := rand.New(rand.NewPCG(1, 2))
+
+	b.ReportAllocs()
+	moves := 0
+	for b.Loop() {
+		players := []*Player{NewPlayer("Player 1"), NewPlayer("Player 2")}
+		state, err := NewGameStateWithRandom(players, ModernOptions(), r)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for n := 0; state.Phase != PhaseGameOver && n < 10000; n++ {
+			legal := LegalMoves(state, state.CurrentPlayer)
+			if len(legal) == 0 {
+				b.Fatal("Expected the current player to have a move")
+			}
+			if err := rules.Apply(state, randomLegalMove(legal, r)); err != nil {
+				b.Fatal(err)
+			}
+			moves++
+		}
+	}
+	b.ReportMetric(float64(moves)/float64(b.N), "moves/game")
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// playRandomGame plays one game with random legal moves through LegalMoves and Apply
+func playRandomGame(b *testing.B, rules *GameRules, r *rand.Rand) int {
+	players := []*Player{NewPlayer("Player 1"), NewPlayer("Player 2")}
+	state, err := NewGameStateWithRandom(players, ModernOptions(), r)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	moves := 0
+	var legal []Move
+	for state.Phase != PhaseGameOver && moves < 10000 {
+		legal = AppendLegalMoves(legal[:0], state, state.CurrentPlayer)
+		if len(legal) == 0 {
+			b.Fatal("Expected the current player to have a move")
+		}
+
+		if err := rules.Apply(state, randomLegalMove(legal, r)); err != nil {
+			b.Fatal(err)
+		}
+		moves++
+	}
+	return moves
+}
+
+func BenchmarkPlayGame(b *testing.B) {
+	rules := NewGameRules()
+	r := rand.New(rand.NewPCG(1, 2))
+
+	b.ReportAllocs()
+	moves := 0
+	for b.Loop() {
+		moves += playRandomGame(b, rules, r)
+	}
+	b.ReportMetric(float64(moves)/float64(b.N), "moves/game")
+}
+
+func BenchmarkValidPlays(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	state, err := NewGameStateWithRandom([]*Player{NewPlayer("Player 1"), NewPlayer("Player 2")}, ModernOptions(), r)
+	if err != nil {
+		b.Fatal(err)
+	}
+	player := state.Players[0]
+	top := Card{Color: Blue, Type: Number, Value: 7}
+
+	b.ReportAllocs()
+	var plays []int
+	for b.Loop() {
+		for color := Red; color <= Yellow; color++ {
+			plays = player.AppendValidPlays(plays[:0], &top, color)
+		}
+	}
+}
+
+// BenchmarkPileBothEnds puts a card under a discard pile of a whole game and takes one off the top
+func BenchmarkPileBothEnds(b *testing.B) {
+	pile := NewDeckWithOptions(ModernOptions())
+
+	b.ReportAllocs()
+	for b.Loop() {
+		card, _ := pile.Draw()
+		pile.AddToBottom(card)
+	}
+}
@@ -0,0 +1,277 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+)
+
+// Builder sets up a game in any position, for tests, puzzles, tutorials and bug
+// reports. Cards are given in compact notation, and every card of the deck that is
+// not placed anywhere goes under the draw pile, so the state holds the whole deck
+// just like a dealt one
+//
+//	state, err := NewBuilder("Alice", "Bob").
+//		Hand(0, "R7 W+4").
+//		Hand(1, "B3 G2").
+//		Discard("R5").
+//		DrawPile("G9 Y1").
+//		Build()
+//
+// Mistakes are remembered and the first one is returned by Build
+type Builder struct {
+	names         []string
+	hands         map[int][]Card
+	discard       []Card
+	drawPile      []Card
+	restInDiscard bool
+	color         CardColor
+	colorSet      bool
+	phase         GamePhase
+	turn          int
+	lastPlayedBy  int
+	played        map[int]bool
+	calledUno     map[int]bool
+	reversed      bool
+	drawn         bool
+	opts          GameOptions
+	random        *rand.Rand
+	err           error
+}
+
+// NewBuilder starts a game for the named players, on player 0's turn in the play phase
+func NewBuilder(names ...string) *Builder {
+	return &Builder{
+		names:        names,
+		hands:        make(map[int][]Card),
+		phase:        PhasePlay,
+		lastPlayedBy: -1, // Nobody played yet
+		played:       make(map[int]bool),
+		calledUno:    make(map[int]bool),
+	}
+}
+
+// fail remembers the first mistake
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// parse reads cards in compact notation, remembering a mistake
+func (b *Builder) parse(cards string) []Card {
+	parsed, err := ParseHand(cards)
+	if err != nil {
+		b.fail(err)
+	}
+	return parsed
+}
+
+// checkSeat remembers a mistake if the seat is not at the table
+func (b *Builder) checkSeat(seat int) bool {
+	if seat < 0 || seat >= len(b.names) {
+		b.fail(fmt.Errorf("no player at seat %d", seat))
+		return false
+	}
+	return true
+}
+
+// Options sets the house rules, which also decide the deck
+func (b *Builder) Options(opts GameOptions) *Builder {
+	b.opts = opts
+	return b
+}
+
+// Hand gives the player at the seat the cards, in hand order
+func (b *Builder) Hand(seat int, cards string) *Builder {
+	if b.checkSeat(seat) {
+		b.hands[seat] = b.parse(cards)
+	}
+	return b
+}
+
+// Discard sets the discard pile from the bottom up, the last card being on top
+func (b *Builder) Discard(cards string) *Builder {
+	b.discard = b.parse(cards)
+	return b
+}
+
+// DrawPile puts the cards on top of the draw pile in the order they will be drawn,
+// the first card being drawn first
+func (b *Builder) DrawPile(cards string) *Builder {
+	b.drawPile = b.parse(cards)
+	return b
+}
+
+// RestInDiscard puts the cards not placed anywhere under the discard pile instead of
+// the draw pile, so the draw pile holds exactly the cards given to DrawPile
+func (b *Builder) RestInDiscard() *Builder {
+	b.restInDiscard = true
+	return b
+}
+
+// Color sets the active color. It defaults to the color of the top card, or red
+// when that is a wild card waiting for its color
+func (b *Builder) Color(color CardColor) *Builder {
+	b.color = color
+	b.colorSet = true
+	return b
+}
+
+// Phase sets the phase of the game
+func (b *Builder) Phase(phase GamePhase) *Builder {
+	b.phase = phase
+	return b
+}
+
+// Turn makes it the turn of the player at the seat
+func (b *Builder) Turn(seat int) *Builder {
+	if b.checkSeat(seat) {
+		b.turn = seat
+	}
+	return b
+}
+
+// LastPlayedBy sets who played the top card, which also counts as them having played
+func (b *Builder) LastPlayedBy(seat int) *Builder {
+	if b.checkSeat(seat) {
+		b.lastPlayedBy = seat
+		b.played[seat] = true
+	}
+	return b
+}
+
+// Played marks the players at the seats as having played a card, so they can win
+// by emptying their hand and must call UNO with one card left
+func (b *Builder) Played(seats ...int) *Builder {
+	for _, seat := range seats {
+		if b.checkSeat(seat) {
+			b.played[seat] = true
+		}
+	}
+	return b
+}
+
+// CalledUno marks the players at the seats as having called UNO
+func (b *Builder) CalledUno(seats ...int) *Builder {
+	for _, seat := range seats {
+		if b.checkSeat(seat) {
+			b.calledUno[seat] = true
+		}
+	}
+	return b
+}
+
+// Reversed makes play run counter-clockwise
+func (b *Builder) Reversed() *Builder {
+	b.reversed = true
+	return b
+}
+
+// Drawn marks the current player as having drawn a card this turn, the last card
+// of their hand
+func (b *Builder) Drawn() *Builder {
+	b.drawn = true
+	return b
+}
+
+// Seed makes every shuffle during play come from a source seeded with seed
+func (b *Builder) Seed(seed uint64) *Builder {
+	b.random = rand.New(rand.NewPCG(seed, seed))
+	return b
+}
+
+// Build creates the game state
+func (b *Builder) Build() (*GameState, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if b.opts.Teams {
+		if len(b.names) != 4 {
+			return nil, errors.New("four players are required for a team game")
+		}
+	} else if len(b.names) != 2 {
+		return nil, errors.New("two players are required")
+	}
+
+	if len(b.discard) == 0 {
+		return nil, errors.New("the discard pile needs a top card")
+	}
+
+	// Take every placed card out of the deck, what is left is the rest
+	rest := NewDeckWithOptions(b.opts).Cards()
+	placed := append(append([]Card(nil), b.discard...), b.drawPile...)
+	for _, hand := range b.hands {
+		placed = append(placed, hand...)
+	}
+	for _, card := range placed {
+		found := false
+		for i := range rest {
+			if rest[i] == card {
+				rest = append(rest[:i], rest[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("the deck has no more %v", card)
+		}
+	}
+
+	// The top of a deck is the end of the slice
+	drawPile := make([]Card, 0, len(b.drawPile)+len(rest))
+	discard := append([]Card(nil), b.discard...)
+	if b.restInDiscard {
+		discard = append(rest, discard...)
+	} else {
+		drawPile = append(drawPile, rest...)
+	}
+	for i := len(b.drawPile) - 1; i >= 0; i-- {
+		drawPile = append(drawPile, b.drawPile[i])
+	}
+
+	color := b.color
+	if !b.colorSet {
+		color = b.discard[len(b.discard)-1].Color
+		if color == Wild {
+			color = Red // Like a wild starting card before its color is chosen
+		}
+	}
+	state := &GameState{
+		Players:       make([]*Player, len(b.names)),
+		CurrentPlayer: b.turn,
+		DrawPile:      newDeck(drawPile),
+		DiscardPile:   newDeck(discard),
+		ActiveColor:   color,
+		Phase:         b.phase,
+		LastPlayedBy:  b.lastPlayedBy,
+		Options:       b.opts,
+		Reversed:      b.reversed,
+		HasDrawn:      b.drawn,
+		random:        b.random,
+	}
+
+	for i, name := range b.names {
+		player := NewPlayer(name)
+		player.Hand = NewHand(b.hands[i]...)
+		player.HasCalledUno = b.calledUno[i]
+		player.IsMyTurn = i == b.turn
+		player.hasPlayedCard = b.played[i]
+		state.Players[i] = player
+	}
+
+	// Players sitting opposite each other are partners
+	if b.opts.Teams {
+		state.Teams = make([]int, len(b.names))
+		for i := range b.names {
+			state.Teams[i] = i % 2
+		}
+	}
+
+	if err := state.Validate(); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
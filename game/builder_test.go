@@ -0,0 +1,122 @@
+package game
+
+import "testing"
+
+func TestBuilder(t *testing.T) {
+	state, err := NewBuilder("Alice", "Bob").
+		Hand(0, "R7 W+4").
+		Hand(1, "B3").
+		Discard("G1 R5").
+		DrawPile("G9 Y1").
+		Turn(1).
+		LastPlayedBy(0).
+		Played(1).
+		Build()
+	if err != nil {
+		t.Fatalf("Expected the state to build, got %v", err)
+	}
+
+	if state.Players[0].Hand.Len() != 2 || state.Players[1].Hand.At(0) != (Card{Color: Blue, Type: Number, Value: 3}) {
+		t.Errorf("Expected the hands as given, got %v and %v", state.Players[0].Hand, state.Players[1].Hand)
+	}
+
+	top, _ := state.DiscardPile.Top()
+	if top != (Card{Color: Red, Type: Number, Value: 5}) || state.ActiveColor != Red {
+		t.Errorf("Expected R5 on top setting the color, got %v and %v", top, state.ActiveColor)
+	}
+
+	if !state.Players[1].IsMyTurn || state.Players[0].IsMyTurn || state.CurrentPlayer != 1 {
+		t.Error("Expected it to be Bob's turn")
+	}
+
+	// The rest of the deck goes under the draw pile
+	total := state.DrawPile.Size() + state.DiscardPile.Size() + 3
+	if total != 108 {
+		t.Errorf("Expected the whole deck in play, got %d cards", total)
+	}
+
+	first, _ := state.DrawPile.Draw()
+	second, _ := state.DrawPile.Draw()
+	if first != (Card{Color: Green, Type: Number, Value: 9}) || second != (Card{Color: Yellow, Type: Number, Value: 1}) {
+		t.Errorf("Expected G9 then Y1 to be drawn, got %v and %v", first, second)
+	}
+
+	// Bob has played before, so one card left means UNO is due
+	if !state.Players[1].ShouldCallUno() || state.Players[0].ShouldCallUno() {
+		t.Error("Expected only Bob to owe an UNO call")
+	}
+}
+
+func TestBuilderPlays(t *testing.T) {
+	state, err := NewBuilder("Alice", "Bob").
+		Hand(0, "R7").
+		Hand(1, "B3 B4").
+		Discard("R5").
+		Played(0).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Playing the last card wins
+	rules := NewGameRules()
+	if err := rules.Apply(state, Move{Kind: MovePlay, Player: 0, CardIndex: 0}); err != nil {
+		t.Fatalf("Expected the play to be legal, got %v", err)
+	}
+	if state.Winner() != 0 {
+		t.Errorf("Expected Alice to win, got %d", state.Winner())
+	}
+}
+
+func TestBuilderRestInDiscard(t *testing.T) {
+	state, err := NewBuilder("Alice", "Bob").
+		Hand(0, "R7").
+		Hand(1, "B3").
+		Discard("W").
+		DrawPile("G2").
+		RestInDiscard().
+		Phase(PhaseColorSelection).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.DrawPile.Size() != 1 || state.DiscardPile.Size() != 105 {
+		t.Errorf("Expected one card to draw and the rest under the discard pile, got %d and %d",
+			state.DrawPile.Size(), state.DiscardPile.Size())
+	}
+	top, _ := state.DiscardPile.Top()
+	if top.Type != WildCard || state.ActiveColor != Red {
+		t.Errorf("Expected the wild on top with red until chosen, got %v and %v", top, state.ActiveColor)
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+	}{
+		{"one player", NewBuilder("Alice").Discard("R5")},
+		{"three players", NewBuilder("A", "B", "C").Discard("R5")},
+		{"no discard", NewBuilder("Alice", "Bob")},
+		{"bad card", NewBuilder("Alice", "Bob").Hand(0, "R7 X9").Discard("R5")},
+		{"bad seat", NewBuilder("Alice", "Bob").Hand(2, "R7").Discard("R5")},
+		{"bad turn", NewBuilder("Alice", "Bob").Discard("R5").Turn(-1)},
+		{"too many cards", NewBuilder("Alice", "Bob").Hand(0, "R0 R0").Discard("R5")},
+		{"special card without the rule", NewBuilder("Alice", "Bob").Hand(0, "WSH").Discard("R5")},
+		{"wild color in play", NewBuilder("Alice", "Bob").Discard("W").Color(Wild)},
+		{"drawn without cards", NewBuilder("Alice", "Bob").Discard("R5").Drawn()},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.builder.Build(); err == nil {
+			t.Errorf("Expected error for %s", tt.name)
+		}
+	}
+
+	// Special cards come with their house rule
+	_, err := NewBuilder("Alice", "Bob").Options(ModernOptions()).Hand(0, "WSH").Discard("R5").Build()
+	if err != nil {
+		t.Errorf("Expected special cards with their house rule, got %v", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"errors"
 	"math/big"
+	"strconv"
+	mathrand "math/rand/v2"
 )
 
 type CardColor int
@@ -23,6 +25,9 @@ const (
 	DrawTwo
 	WildCard
 	WildDrawFour
+	WildShuffleHands
+	WildSwapHands
+	WildCustomizable
 )
 
 // Card represents a UNO card with a color, type, and value
@@ -32,9 +37,14 @@ type Card struct {
 	Value 	int // Only used for number cards (0-9)
 }
 
-// Deck represents a collection of UNO cards with thread-safe operations
+// Deck represents a pile of UNO cards, the top card last. Cards can be added at
+// either end without moving the others, and the pile keeps the hash of its cards
+// in order up to date, see pileHash. The zero value is an empty deck
 type Deck struct {
-	Cards []Card
+	cards []Card // The pile is cards[head:]
+	head  int    // Free slots below the bottom card
+	hash  uint64
+	power uint64 // pileBase to the power of the pile's size, 0 stands for 1 in a zero deck
 }
 
 func (c CardColor) String() string {
@@ -68,6 +78,12 @@ func (t CardType) String() string {
 		return "Wild"
 	case WildDrawFour:
 		return "Wild Draw Four"
+	case WildShuffleHands:
+		return "Wild Shuffle Hands"
+	case WildSwapHands:
+		return "Wild Swap Hands"
+	case WildCustomizable:
+		return "Wild Customizable"
 	default:
 		return "Unknown"
 	}
@@ -75,11 +91,27 @@ func (t CardType) String() string {
 
 func (c Card) String() string {
 	if c.Type == Number {
-		return c.Color.String() + " " + c.Type.String() + " " + string(rune('0'+c.Value))
+		return c.Color.String() + " " + c.Type.String() + " " + strconv.Itoa(c.Value)
 	}
 	return c.Color.String() + " " + c.Type.String()
 }
 
+// Points returns the card's value when scoring the cards left in a losing hand
+func (c Card) Points() int {
+	switch c.Type {
+	case Number:
+		return c.Value
+	case Skip, Reverse, DrawTwo:
+		return 20
+	case WildShuffleHands, WildSwapHands, WildCustomizable:
+		return 40
+	case WildCard, WildDrawFour:
+		return 50
+	default:
+		return 0
+	}
+}
+
 func (c Card) CanPlayOn(topCard Card, activeColor CardColor) bool {
 	// Wild cards and Wild Draw Four cards can always be played
 	if c.Color == Wild {
@@ -111,72 +143,157 @@ func (c Card) CanPlayOn(topCard Card, activeColor CardColor) bool {
 	return false
 }
 
-func IsWildDrawFourValid(hand []*Card, activeColor CardColor) bool {
-	for _, card := range hand {
-		if card.Color == activeColor {
-			return false
-		}
+// MatchesTop checks if a card can go on the discard pile, ignoring the Wild Draw Four restriction
+// Once a wild card has changed the color, only cards of the active color match
+func MatchesTop(card Card, topCard Card, activeColor CardColor) bool {
+	if card.Color == Wild {
+		return true
+	}
+
+	if topCard.Color != activeColor {
+		return card.Color == activeColor
+	}
+
+	return card.CanPlayOn(topCard, activeColor)
+}
+
+// IsPlayable checks if a card from the given hand can be played on the discard pile
+func IsPlayable(card Card, hand *Hand, topCard Card, activeColor CardColor) bool {
+	if !MatchesTop(card, topCard, activeColor) {
+		return false
+	}
+
+	if card.Type == WildDrawFour {
+		return IsWildDrawFourValid(hand, activeColor)
 	}
 
 	return true
 }
 
+func IsWildDrawFourValid(hand *Hand, activeColor CardColor) bool {
+	return !hand.HasColor(activeColor)
+}
+
 // NewDeck creates a new standard 108-card UNO deck
 func NewDeck() *Deck {
-	deck := &Deck{Cards: make([]Card, 0, 108)}
+	return NewDeckWithOptions(GameOptions{})
+}
+
+// NewDeckWithOptions creates the standard deck plus the special wild cards requested by the options
+func NewDeckWithOptions(opts GameOptions) *Deck {
+	cards := make([]Card, 0, 108+opts.specialWildCount())
 	
 	// Add number cards (0-9) for each color
 	for color := Red; color <= Yellow; color++ {
 		// Add one 0 card for each color
-		deck.Cards = append(deck.Cards, Card{Color: color, Type: Number, Value: 0})
+		cards = append(cards, Card{Color: color, Type: Number, Value: 0})
 
 		// Add two of each number 1-9 for each color
 		for i := 1; i <= 9; i++ {
-			deck.Cards = append(deck.Cards, Card{Color: color, Type: Number, Value: i})
-			deck.Cards = append(deck.Cards, Card{Color: color, Type: Number, Value: i})
+			cards = append(cards, Card{Color: color, Type: Number, Value: i})
+			cards = append(cards, Card{Color: color, Type: Number, Value: i})
 		}
 
 		// Add two of each action card (Skip, Reverse, Draw Two) for each color
 		for range 2 {
-			deck.Cards = append(deck.Cards, Card{Color: color, Type: Skip})
-			deck.Cards = append(deck.Cards, Card{Color: color, Type: Reverse})
-			deck.Cards = append(deck.Cards, Card{Color: color, Type: DrawTwo})
+			cards = append(cards, Card{Color: color, Type: Skip})
+			cards = append(cards, Card{Color: color, Type: Reverse})
+			cards = append(cards, Card{Color: color, Type: DrawTwo})
 		}
 	}
 
 	// Add Wild cards and Wild Draw Four cards
 	for range 4 {
-		deck.Cards = append(deck.Cards, Card{Color: Wild, Type: WildCard})
-		deck.Cards = append(deck.Cards, Card{Color: Wild, Type: WildDrawFour})
+		cards = append(cards, Card{Color: Wild, Type: WildCard})
+		cards = append(cards, Card{Color: Wild, Type: WildDrawFour})
+	}
+
+	// Add the special wild cards enabled for this game
+	for range opts.ShuffleHandsCards {
+		cards = append(cards, Card{Color: Wild, Type: WildShuffleHands})
+	}
+	for range opts.SwapHandsCards {
+		cards = append(cards, Card{Color: Wild, Type: WildSwapHands})
+	}
+	for range opts.CustomizableCards {
+		cards = append(cards, Card{Color: Wild, Type: WildCustomizable})
 	}
 	
-	return deck
+	return newDeck(cards)
 }
 
 // Shuffle randomizes the order of cards in the deck using a cryptographically secure random source
 func (d *Deck) Shuffle() {
 	// Fisher-Yates shuffle algorithm with crypto/rand
-	for i := len(d.Cards) - 1; i > 0; i-- {
-		// Generate a secure random number
-		nBig, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
-		if err != nil {
+	cards := d.Cards()
+	for i := len(cards) - 1; i > 0; i-- {
+		j, ok := secureIntn(i + 1)
+		if !ok {
 			// If crypto/rand fails, skip this iteration
 			continue
 		}
 		
-		j := nBig.Int64()
-		d.Cards[i], d.Cards[int(j)] = d.Cards[int(j)], d.Cards[i]
+		cards[i], cards[j] = cards[j], cards[i]
 	}
+	d.rehash()
+}
+
+// ShuffleWith randomizes the order of cards in the deck using the given source
+// Used where games must be reproducible or fast, such as simulations and bot search
+func (d *Deck) ShuffleWith(r *mathrand.Rand) {
+	shuffleCards(d.Cards(), r)
+	d.rehash()
 }
 
+// shuffleCards is r.Shuffle without a call per swap, drawing the same numbers
+func shuffleCards(cards []Card, r *mathrand.Rand) {
+	for i := len(cards) - 1; i > 0; i-- {
+		j := r.Uint64N(uint64(i + 1))
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+}
+
+// shuffleHand randomizes the order of a slice of cards in place
+// using the given source, or crypto/rand when it is nil
+func shuffleHand(cards []Card, r *mathrand.Rand) {
+	if r != nil {
+		shuffleCards(cards, r)
+		return
+	}
+
+	for i := len(cards) - 1; i > 0; i-- {
+		j, ok := secureIntn(i + 1)
+		if !ok {
+			continue
+		}
+
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+}
+
+// secureIntn returns a uniform random number in [0, n) read from crypto/rand
+func secureIntn(n int) (int, bool) {
+	nBig, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, false
+	}
+	return int(nBig.Int64()), true
+}
+
+// errEmptyDeck is made once, so Draw stays small enough to inline
+var errEmptyDeck = errors.New("cannot draw from an empty deck")
+
 // Draw removes and returns the top card from the deck
 func (d *Deck) Draw() (Card, error) {
-	if len(d.Cards) == 0 {
-		return Card{}, errors.New("cannot draw from an empty deck")
+	if d.IsEmpty() {
+		return Card{}, errEmptyDeck
 	}
 	
-	card := d.Cards[len(d.Cards)-1]
-	d.Cards = d.Cards[:len(d.Cards)-1]
+	card := d.cards[len(d.cards)-1]
+	d.cards = d.cards[:len(d.cards)-1]
+
+	d.power *= pileBaseInverse
+	d.hash -= pileKeys[KindOf(card)] * d.power
 	
 	return card, nil
 }
@@ -187,39 +304,109 @@ func (d *Deck) DrawN(n int) ([]Card, error) {
 		return nil, errors.New("cannot draw a non-positive number of cards")
 	}
 	
-	if len(d.Cards) < n {
+	if d.Size() < n {
 		return nil, errors.New("not enough cards in deck")
 	}
 	
 	cards := make([]Card, n)
-	for i := range n {
-		cards[i] = d.Cards[len(d.Cards)-1-i]
+	for i := range cards {
+		cards[i], _ = d.Draw()
 	}
 	
-	// Remove the drawn cards from the deck
-	d.Cards = d.Cards[:len(d.Cards)-n]
-	
 	return cards, nil
 }
 
+// AddToTop places a card on top of the deck, which is the end of the slice
+func (d *Deck) AddToTop(card Card) {
+	power := d.basePower()
+	d.cards = append(d.cards, card)
+	d.hash += pileKeys[KindOf(card)] * power
+	d.power = power * pileBase
+}
+
+// Top returns the top card of the deck without removing it
+func (d *Deck) Top() (Card, error) {
+	if d.IsEmpty() {
+		return Card{}, errors.New("deck is empty")
+	}
+	return d.cards[len(d.cards)-1], nil
+}
+
 // AddToBottom adds a card to the bottom of the deck
+// The deck keeps free slots below its bottom card, so the others are only moved
+// when those run out, and then the room doubles
 func (d *Deck) AddToBottom(card Card) {
-	// Prepend the card to the slice
-	d.Cards = append([]Card{card}, d.Cards...)
+	if d.head == 0 {
+		room := max(d.Size(), 8)
+		cards := make([]Card, room+d.Size())
+		copy(cards[room:], d.Cards())
+		d.cards, d.head = cards, room
+	}
+
+	d.head--
+	d.cards[d.head] = card
+	d.hash = d.hash*pileBase + pileKeys[KindOf(card)]
+	d.power = d.basePower() * pileBase
+}
+
+// Cards returns the cards of the deck from the bottom to the top
+// The slice belongs to the deck and must not be changed, use the deck's methods
+func (d *Deck) Cards() []Card {
+	return d.cards[d.head:]
+}
+
+// Replace swaps the cards of the deck for copies of the given ones, top card last
+func (d *Deck) Replace(cards []Card) {
+	d.cards = append(d.cards[:0], cards...)
+	d.head = 0
+	d.rehash()
 }
 
 // IsEmpty checks if the deck is empty
 func (d *Deck) IsEmpty() bool {
-	return len(d.Cards) == 0
+	return d.Size() == 0
 }
 
 // Size returns the number of cards in the deck
 func (d *Deck) Size() int {
-	return len(d.Cards)
+	return len(d.cards) - d.head
+}
+
+// basePower returns pileBase to the power of the deck's size
+func (d *Deck) basePower() uint64 {
+	if d.power == 0 {
+		return 1
+	}
+	return d.power
+}
+
+// rehash works out the hash of the deck from its cards, after they were moved around
+func (d *Deck) rehash() {
+	d.hash, d.power = pileHash(d.Cards())
+}
+
+// check compares the hash with the one worked out from the cards
+func (d *Deck) check() error {
+	hash, power := pileHash(d.Cards())
+	if hash != d.hash || power != d.basePower() {
+		return errors.New("the hash of the pile does not match its cards")
+	}
+	return nil
+}
+
+// newDeck creates a deck holding the given cards, top card last, and takes over the slice
+func newDeck(cards []Card) *Deck {
+	deck := &Deck{cards: cards}
+	deck.rehash()
+	return deck
+}
+
+// NewDeckOf creates a deck holding copies of the given cards, top card last
+func NewDeckOf(cards []Card) *Deck {
+	return newDeck(append([]Card(nil), cards...))
 }
 
 // CreateDiscardPile creates a new discard pile with a single card
 func CreateDiscardPile(initialCard Card) *Deck {
-	discardPile := &Deck{Cards: []Card{initialCard}}
-	return discardPile
+	return newDeck([]Card{initialCard})
 }
@@ -66,26 +66,26 @@ func TestCanPlayOn(t *testing.T) {
 
 func TestIsWildDrawFourValid(t *testing.T) {
 	// Create a hand with various cards
-	redFive := &Card{Color: Red, Type: Number, Value: 5}
-	blueSeven := &Card{Color: Blue, Type: Number, Value: 7}
-	greenSkip := &Card{Color: Green, Type: Skip}
-	wildCard := &Card{Color: Wild, Type: WildCard}
+	redFive := Card{Color: Red, Type: Number, Value: 5}
+	blueSeven := Card{Color: Blue, Type: Number, Value: 7}
+	greenSkip := Card{Color: Green, Type: Skip}
+	wildCard := Card{Color: Wild, Type: WildCard}
 	
 	// Test case 1: Hand with no cards of active color
-	hand1 := []*Card{blueSeven, greenSkip, wildCard}
-	if !IsWildDrawFourValid(hand1, Red) {
+	hand1 := NewHand(blueSeven, greenSkip, wildCard)
+	if !IsWildDrawFourValid(&hand1, Red) {
 		t.Error("Expected Wild Draw Four to be valid when hand has no cards of active color")
 	}
 	
 	// Test case 2: Hand with cards of active color
-	hand2 := []*Card{redFive, blueSeven, greenSkip, wildCard}
-	if IsWildDrawFourValid(hand2, Red) {
+	hand2 := NewHand(redFive, blueSeven, greenSkip, wildCard)
+	if IsWildDrawFourValid(&hand2, Red) {
 		t.Error("Expected Wild Draw Four to be invalid when hand has cards of active color")
 	}
 	
 	// Test case 3: Empty hand
-	var hand3 []*Card
-	if !IsWildDrawFourValid(hand3, Red) {
+	var hand3 Hand
+	if !IsWildDrawFourValid(&hand3, Red) {
 		t.Error("Expected Wild Draw Four to be valid with empty hand")
 	}
 }
@@ -100,7 +100,7 @@ func TestNewDeck(t *testing.T) {
 	typeCounts := make(map[CardType]int)
 	colorCounts := make(map[CardColor]int)
 
-	for _, card := range deck.Cards {
+	for _, card := range deck.Cards() {
 		typeCounts[card.Type] ++
 		colorCounts[card.Color] ++
 	}
@@ -211,10 +211,28 @@ func TestAddToBottom(t *testing.T) {
 	}
 	
 	// The card added should be at the bottom (index 0)
-	bottomCard := deck.Cards[0]
+	bottomCard := deck.Cards()[0]
 	if bottomCard.Color != card.Color || bottomCard.Type != card.Type || bottomCard.Value != card.Value {
 		t.Errorf("Expected bottom card to be %+v, got %+v", card, bottomCard)
 	}
+
+	// Cycling the whole deck through both ends keeps its order and its storage
+	want := append([]Card(nil), deck.Cards()...)
+	for range 3 * deck.Size() {
+		drawn, _ := deck.Draw()
+		deck.AddToBottom(drawn)
+	}
+	if cap(deck.cards) > 4*len(want) {
+		t.Errorf("Expected the deck to reuse its storage, it holds room for %d cards", cap(deck.cards))
+	}
+	for i, c := range deck.Cards() {
+		if c != want[i] {
+			t.Fatalf("Expected card %d to be %v after cycling the deck, got %v", i, want[i], c)
+		}
+	}
+	if err := deck.check(); err != nil {
+		t.Errorf("Expected the hash to survive cycling the deck: %v", err)
+	}
 }
 
 func TestIsEmpty(t *testing.T) {
@@ -245,7 +263,7 @@ func TestCreateDiscardPile(t *testing.T) {
 		t.Errorf("Expected discard pile to have 1 card, got %d", discardPile.Size())
 	}
 	
-	topCard := discardPile.Cards[0]
+	topCard := discardPile.Cards()[0]
 	if topCard.Color != initialCard.Color || topCard.Type != initialCard.Type || topCard.Value != initialCard.Value {
 		t.Errorf("Expected top card of discard pile to be %v, got %v", initialCard, topCard)
 	}
@@ -255,8 +273,8 @@ func TestShuffleRandomness(t *testing.T) {
 	deck := NewDeck()
 	
 	// Store the initial order
-	initialOrder := make([]Card, len(deck.Cards))
-	copy(initialOrder, deck.Cards)
+	initialOrder := make([]Card, len(deck.Cards()))
+	copy(initialOrder, deck.Cards())
 	
 	// Shuffle the deck
 	deck.Shuffle()
@@ -264,8 +282,8 @@ func TestShuffleRandomness(t *testing.T) {
 	// Check that the order has changed (this test could occasionally fail by chance,
 	// but the probability is extremely low with 108 cards)
 	samePosition := 0
-	for i := range deck.Cards {
-		if i < len(initialOrder) && deck.Cards[i] == initialOrder[i] {
+	for i := range deck.Cards() {
+		if i < len(initialOrder) && deck.Cards()[i] == initialOrder[i] {
 			samePosition++
 		}
 	}
@@ -275,3 +293,81 @@ func TestShuffleRandomness(t *testing.T) {
 		t.Errorf("Shuffle appears not to be sufficiently random. %d cards remained in the same position", samePosition)
 	}
 }
+
+func TestNewDeckWithOptions(t *testing.T) {
+	deck := NewDeckWithOptions(GameOptions{ShuffleHandsCards: 1, SwapHandsCards: 2, CustomizableCards: 3})
+
+	if deck.Size() != 114 {
+		t.Errorf("Expected deck to have 114 cards, got %d", deck.Size())
+	}
+
+	typeCounts := make(map[CardType]int)
+	for _, card := range deck.Cards() {
+		typeCounts[card.Type]++
+
+		if card.Type >= WildShuffleHands && card.Color != Wild {
+			t.Errorf("Expected special wild cards to be Wild colored, got %v", card)
+		}
+	}
+
+	if typeCounts[WildShuffleHands] != 1 {
+		t.Errorf("Expected 1 Wild Shuffle Hands card, got %d", typeCounts[WildShuffleHands])
+	}
+
+	if typeCounts[WildSwapHands] != 2 {
+		t.Errorf("Expected 2 Wild Swap Hands cards, got %d", typeCounts[WildSwapHands])
+	}
+
+	if typeCounts[WildCustomizable] != 3 {
+		t.Errorf("Expected 3 Wild Customizable cards, got %d", typeCounts[WildCustomizable])
+	}
+
+	modern := NewDeckWithOptions(ModernOptions())
+	if modern.Size() != 112 {
+		t.Errorf("Expected modern deck to have 112 cards, got %d", modern.Size())
+	}
+}
+
+func TestAddToTop(t *testing.T) {
+	deck := CreateDiscardPile(Card{Color: Blue, Type: Number, Value: 3})
+	card := Card{Color: Red, Type: Skip}
+
+	deck.AddToTop(card)
+
+	top, err := deck.Top()
+	if err != nil {
+		t.Fatalf("Expected no error reading the top card, got %v", err)
+	}
+
+	if top != card {
+		t.Errorf("Expected top card to be %v, got %v", card, top)
+	}
+
+	empty := &Deck{}
+	if _, err := empty.Top(); err == nil {
+		t.Error("Expected error reading the top card of an empty deck")
+	}
+}
+
+func TestPoints(t *testing.T) {
+	tests := []struct {
+		card     Card
+		expected int
+	}{
+		{Card{Color: Red, Type: Number, Value: 7}, 7},
+		{Card{Color: Blue, Type: Skip}, 20},
+		{Card{Color: Green, Type: Reverse}, 20},
+		{Card{Color: Yellow, Type: DrawTwo}, 20},
+		{Card{Color: Wild, Type: WildCard}, 50},
+		{Card{Color: Wild, Type: WildDrawFour}, 50},
+		{Card{Color: Wild, Type: WildShuffleHands}, 40},
+		{Card{Color: Wild, Type: WildSwapHands}, 40},
+		{Card{Color: Wild, Type: WildCustomizable}, 40},
+	}
+
+	for _, tt := range tests {
+		if tt.card.Points() != tt.expected {
+			t.Errorf("Expected %v to be worth %d points, got %d", tt.card, tt.expected, tt.card.Points())
+		}
+	}
+}
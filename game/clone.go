@@ -0,0 +1,59 @@
+package game
+
+// Clone returns a deep copy of the deck
+func (d *Deck) Clone() *Deck {
+	clone := *d
+	clone.cards = append([]Card(nil), d.Cards()...)
+	clone.head = 0
+	return &clone
+}
+
+// Clone returns a deep copy of the player
+func (p *Player) Clone() *Player {
+	clone := *p
+	clone.Hand = p.Hand.Clone()
+	return &clone
+}
+
+// Clone returns a deep copy of the game state that can be played on without
+// touching the original. The random source is shared
+// Players and their cards are copied into a few shared arrays, so a clone costs
+// the same handful of allocations however many cards the hands hold
+func (s *GameState) Clone() *GameState {
+	clone := *s
+
+	total := 0
+	for _, player := range s.Players {
+		total += player.Hand.Len()
+	}
+
+	players := make([]Player, len(s.Players))
+	cards := make([]Card, total)
+	order := make([]uint8, total)
+	clone.Players = make([]*Player, len(s.Players))
+
+	start := 0
+	for i, player := range s.Players {
+		players[i] = *player
+
+		// Cap the hand so adding a card never writes into the next player's hand
+		end := start + copy(cards[start:], player.Hand.cards)
+		copy(order[start:], player.Hand.order)
+		players[i].Hand.cards = cards[start:end:end]
+		players[i].Hand.order = order[start:end:end]
+		clone.Players[i] = &players[i]
+		start = end
+	}
+
+	if s.DrawPile != nil {
+		clone.DrawPile = s.DrawPile.Clone()
+	}
+	if s.DiscardPile != nil {
+		clone.DiscardPile = s.DiscardPile.Clone()
+	}
+	if s.Teams != nil {
+		clone.Teams = append([]int(nil), s.Teams...)
+	}
+
+	return &clone
+}
@@ -0,0 +1,72 @@
+package game
+
+import (
+	"testing"
+)
+
+// Test that a cloned state shares nothing with the original
+func TestCloneGameState(t *testing.T) {
+	state := createTestGameState()
+	state.Players[0].AddCardsToHand([]Card{{Color: Red, Type: Number, Value: 7}, {Color: Blue, Type: Skip}})
+	state.Players[1].AddCard(Card{Color: Green, Type: Number, Value: 2})
+	state.Players[0].hasPlayedCard = true
+	state.Teams = []int{0, 1}
+
+	clone := state.Clone()
+
+	if clone.Players[0].HandSize() != 2 || clone.Players[0].Hand.At(0) != state.Players[0].Hand.At(0) {
+		t.Fatal("Expected the clone to hold the same cards")
+	}
+
+	if !clone.Players[0].hasPlayedCard {
+		t.Error("Expected the clone to keep internal player flags")
+	}
+
+	// Playing on the clone leaves the original untouched
+	rules := NewGameRules()
+	if err := rules.HandlePlayCard(clone.Players[0], 0, clone, nil); err != nil {
+		t.Fatalf("Expected no error playing on the clone, got %v", err)
+	}
+	clone.Players[1].Hand.Remove(0)
+	clone.Players[1].AddCard(Card{Color: Green, Type: Number, Value: 9})
+	clone.DrawPile.AddToBottom(Card{Color: Green, Type: Number, Value: 9})
+	clone.Teams[0] = 1
+
+	if state.Players[0].HandSize() != 2 || state.CurrentPlayer != 0 || state.DiscardPile.Size() != 1 {
+		t.Error("Expected the original state to be unchanged")
+	}
+
+	if state.Players[1].Hand.At(0).Value != 2 {
+		t.Error("Expected hands not to share cards")
+	}
+
+	if state.DrawPile.Cards()[0].Value == 9 || state.DrawPile.Size() == clone.DrawPile.Size() || state.Teams[0] != 0 {
+		t.Error("Expected piles and teams not to share backing arrays")
+	}
+}
+
+// Test that adding cards to a cloned hand never spills into another hand
+func TestCloneHandsStayApart(t *testing.T) {
+	state := createTestGameState()
+	state.Players[0].AddCard(Card{Color: Red, Type: Number, Value: 1})
+	state.Players[1].AddCard(Card{Color: Blue, Type: Number, Value: 2})
+
+	clone := state.Clone()
+	clone.Players[0].AddCard(Card{Color: Green, Type: Number, Value: 3})
+
+	if clone.Players[1].HandSize() != 1 || clone.Players[1].Hand.At(0) != (Card{Color: Blue, Type: Number, Value: 2}) {
+		t.Errorf("Expected player 2's hand to be untouched, got %v", clone.Players[1].Hand)
+	}
+}
+
+func BenchmarkClone(b *testing.B) {
+	state, err := NewGameStateWithOptions([]*Player{NewPlayer("Player 1"), NewPlayer("Player 2")}, ModernOptions())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		state.Clone()
+	}
+}
@@ -0,0 +1,24 @@
+#!/bin/sh
+# Times the games of BenchmarkLegalGame on the engine before the allocation-free
+# redesign against BenchmarkPlayout on this tree, and prints how many times more
+# games per second this tree simulates. The runs are interleaved so that a busy
+# machine slows both sides alike. Usage: compare_baseline.sh [runs] [revision]
+set -e
+
+runs=${1:-5}
+base=${2:-42a970bbc5a0}
+game=$(cd "$(dirname "$0")" && pwd)
+dir=$(mktemp -d)
+trap 'git -C "$game" worktree remove --force "$dir/tree"; rm -rf "$dir"' EXIT
+
+git -C "$game" worktree add --detach "$dir/tree" "$base" >/dev/null 2>&1
+cp "$game/baseline_bench_test.go" "$dir/tree/game/"
+(cd "$dir/tree/game" && go test -c -o "$dir/base.test" .)
+(cd "$game" && go test -c -o "$dir/new.test" .)
+
+for i in $(seq "$runs"); do
+	"$dir/base.test" -test.run '^$' -test.bench 'LegalGame$' | awk '/^Benchmark/ { print "base", $3 }'
+	"$dir/new.test" -test.run '^$' -test.bench 'Playout$' | awk '/^Benchmark/ { print "new", $3 }'
+done | awk '
+	{ if (!($1 in best) || $2 < best[$1]) best[$1] = $2 }
+	END { printf "baseline %d ns/game, playout %d ns/game, %.1fx the games per second\n", best["base"], best["new"], best["base"] / best["new"] }'
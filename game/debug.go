@@ -0,0 +1,6 @@
+//go:build debug
+
+package game
+
+// debugChecks validates the state after every rule operation, build with -tags debug
+const debugChecks = true
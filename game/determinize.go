@@ -0,0 +1,107 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+)
+
+// UnseenCards returns the cards the viewing player cannot account for: the full
+// deck minus every visible hand and the discard pile. They are split between the
+// hidden hands and the draw pile in an order the player cannot know
+func UnseenCards(view PlayerView) ([]Card, error) {
+	counts := make(map[Card]int)
+	for _, card := range NewDeckWithOptions(view.Options).Cards() {
+		counts[card]++
+	}
+
+	seen := make([]Card, 0, len(view.DiscardPile))
+	seen = append(seen, view.DiscardPile...)
+	for _, hand := range view.Hands {
+		seen = append(seen, hand...)
+	}
+
+	for _, card := range seen {
+		if counts[card] == 0 {
+			return nil, fmt.Errorf("card %v appears more often than the deck holds it", card)
+		}
+		counts[card]--
+	}
+
+	// Walk the deck again so the result has a stable order
+	unseen := make([]Card, 0)
+	for _, card := range NewDeckWithOptions(view.Options).Cards() {
+		if counts[card] > 0 {
+			unseen = append(unseen, card)
+			counts[card]--
+		}
+	}
+
+	return unseen, nil
+}
+
+// Determinize builds a complete game state consistent with what the viewing player
+// has observed. The unseen cards are shuffled, dealt to the hidden hands and the
+// rest become the draw pile. Bots sample many of these to search over hidden information
+func Determinize(view PlayerView, r *rand.Rand) (*GameState, error) {
+	unseen, err := UnseenCards(view)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Shuffle(len(unseen), func(i, j int) {
+		unseen[i], unseen[j] = unseen[j], unseen[i]
+	})
+
+	hands := make([][]Card, len(view.HandSizes))
+	for i, size := range view.HandSizes {
+		if view.Hands[i] != nil {
+			hands[i] = view.Hands[i]
+			continue
+		}
+
+		if size > len(unseen) {
+			return nil, errors.New("not enough unseen cards to fill the hidden hands")
+		}
+		hands[i] = unseen[:size]
+		unseen = unseen[size:]
+	}
+
+	if len(unseen) != view.DrawPileSize {
+		return nil, fmt.Errorf("expected %d cards left for the draw pile, got %d", view.DrawPileSize, len(unseen))
+	}
+
+	return StateFromHands(view, hands, unseen), nil
+}
+
+// StateFromHands builds a game state from the public part of a view, the
+// given hands and the given draw pile, top card last
+func StateFromHands(view PlayerView, hands [][]Card, drawPile []Card) *GameState {
+	state := &GameState{
+		Players:       make([]*Player, len(hands)),
+		CurrentPlayer: view.CurrentPlayer,
+		DrawPile:      NewDeckOf(drawPile),
+		DiscardPile:   NewDeckOf(view.DiscardPile),
+		ActiveColor:   view.ActiveColor,
+		Phase:         view.Phase,
+		LastPlayedBy:  view.LastPlayedBy,
+		Options:       view.Options,
+		Teams:         append([]int(nil), view.Teams...),
+		Reversed:      view.Reversed,
+		HasDrawn:      view.HasDrawn,
+	}
+
+	for i, hand := range hands {
+		player := NewPlayer(fmt.Sprintf("Player %d", i+1))
+
+		player.Hand = NewHand(hand...)
+
+		player.HasCalledUno = view.CalledUno[i]
+		player.IsMyTurn = i == view.CurrentPlayer
+		player.hasPlayedCard = view.HasPlayed[i]
+		state.Players[i] = player
+	}
+
+	debugValidate(state, "determinizing")
+	return state
+}
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// Test that the unseen cards are the deck minus everything the viewer knows about
+func TestUnseenCards(t *testing.T) {
+	players := []*Player{NewPlayer("A"), NewPlayer("B")}
+	state, err := NewGameState(players)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	view := state.View(0)
+	unseen, err := UnseenCards(view)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := players[1].HandSize() + state.DrawPile.Size()
+	if len(unseen) != expected {
+		t.Errorf("Expected %d unseen cards, got %d", expected, len(unseen))
+	}
+
+	// A view claiming more copies of a card than the deck holds is rejected
+	view.Hands[0] = append(view.Hands[0], Card{Color: Wild, Type: WildShuffleHands})
+	if _, err := UnseenCards(view); err == nil {
+		t.Error("Expected error for a card the deck does not contain")
+	}
+}
+
+// Test that a determinization is a complete state matching the view
+func TestDeterminize(t *testing.T) {
+	players := []*Player{NewPlayer("A"), NewPlayer("B")}
+	state, err := NewGameStateWithOptions(players, ModernOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	view := state.View(0)
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for range 10 {
+		sample, err := Determinize(view, r)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Our own hand, the public piles and the hand sizes are kept
+		for i, card := range sample.Players[0].Hand.All() {
+			if card != state.Players[0].Hand.At(i) {
+				t.Fatalf("Expected own hand to be kept, got %v", card)
+			}
+		}
+
+		if sample.Players[1].HandSize() != players[1].HandSize() {
+			t.Errorf("Expected opponent to hold %d cards, got %d", players[1].HandSize(), sample.Players[1].HandSize())
+		}
+
+		if sample.DrawPile.Size() != state.DrawPile.Size() || sample.DiscardPile.Size() != state.DiscardPile.Size() {
+			t.Error("Expected pile sizes to match the view")
+		}
+
+		total := sample.DrawPile.Size() + sample.DiscardPile.Size()
+		for _, player := range sample.Players {
+			total += player.HandSize()
+		}
+		if total != 112 {
+			t.Errorf("Expected 112 cards in the determinization, got %d", total)
+		}
+
+		if !sample.Players[sample.CurrentPlayer].IsMyTurn {
+			t.Error("Expected the current player's turn flag to be set")
+		}
+
+		// The sample is playable and agrees with the original on the legal moves
+		if len(LegalMoves(sample, 0)) != len(view.LegalMoves) {
+			t.Errorf("Expected %d legal moves, got %d", len(view.LegalMoves), len(LegalMoves(sample, 0)))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package game
+
+// EventKind is the type of a public event in a game
+type EventKind int
+
+const (
+	EventPlay         EventKind = iota // Player put Card on the discard pile, Color is the chosen color of a wild
+	EventDraw                          // Player drew a card on their turn
+	EventPenalty                       // Player had to take Count cards from an attack card, house rule or challenge
+	EventPass                          // Player ended their turn after drawing
+	EventChooseColor                   // Player picked Color for the wild card on top of the discard pile
+	EventCallUno                       // Player called UNO
+	EventShuffleHands                  // Player's Wild Shuffle Hands collected and redealt every hand
+	EventSwapHands                     // Player and Target exchanged hands
+	EventReshuffle                     // The discard pile was shuffled back into the draw pile
+	EventGameOver                      // Player went out and the round is over
+)
+
+// Event is something that happened in a game that every player can see
+// Events never reveal hidden cards, drawn cards only show up as a count
+type Event struct {
+	Kind   EventKind
+	Player int
+	Target int
+	Card   Card
+	Color  CardColor
+	Count  int
+}
+
+func (k EventKind) String() string {
+	switch k {
+	case EventPlay:
+		return "Play"
+	case EventDraw:
+		return "Draw"
+	case EventPenalty:
+		return "Penalty"
+	case EventPass:
+		return "Pass"
+	case EventChooseColor:
+		return "Choose Color"
+	case EventCallUno:
+		return "Call UNO"
+	case EventShuffleHands:
+		return "Shuffle Hands"
+	case EventSwapHands:
+		return "Swap Hands"
+	case EventReshuffle:
+		return "Reshuffle"
+	case EventGameOver:
+		return "Game Over"
+	default:
+		return "Unknown"
+	}
+}
+
+// Subscribe registers a listener that is called with every event the rules produce
+// Listeners run synchronously, after the state change they describe
+func (gr *GameRules) Subscribe(listener func(Event)) {
+	gr.listeners = append(gr.listeners, listener)
+}
+
+// emit is small enough to inline, so the event is not even built when nobody listens
+func (gr *GameRules) emit(event Event) {
+	if len(gr.listeners) != 0 {
+		gr.notify(event)
+	}
+}
+
+func (gr *GameRules) notify(event Event) {
+	for _, listener := range gr.listeners {
+		listener(event)
+	}
+}
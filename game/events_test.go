@@ -0,0 +1,55 @@
+package game
+
+import (
+	"testing"
+)
+
+// Test that the rules report what happens in the order it happens
+func TestEvents(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestGameState()
+	state.Players[0].AddCardsToHand([]Card{{Color: Red, Type: Number, Value: 7}, {Color: Wild, Type: WildDrawFour}, {Color: Blue, Type: Number, Value: 1}})
+	state.Players[1].AddCardsToHand([]Card{{Color: Green, Type: Number, Value: 2}, {Color: Green, Type: Number, Value: 3}})
+
+	var events []Event
+	rules.Subscribe(func(event Event) {
+		events = append(events, event)
+	})
+
+	moves := []Move{
+		{Kind: MovePlay, Player: 0, CardIndex: 0},
+		{Kind: MoveDraw, Player: 1},
+		{Kind: MovePass, Player: 1},
+	}
+	for _, move := range moves {
+		if err := rules.Apply(state, move); err != nil {
+			t.Fatalf("Expected %v to apply, got %v", move, err)
+		}
+	}
+
+	// The hand is now Blue 1 and Wild Draw Four, the color is picked after playing
+	if err := rules.HandlePlayCard(state.Players[0], 1, state, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := rules.Apply(state, Move{Kind: MoveChooseColor, Player: 0, Color: Blue}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Event{
+		{Kind: EventPlay, Player: 0, Card: Card{Color: Red, Type: Number, Value: 7}},
+		{Kind: EventDraw, Player: 1, Count: 1},
+		{Kind: EventPass, Player: 1},
+		{Kind: EventPlay, Player: 0, Card: Card{Color: Wild, Type: WildDrawFour}},
+		{Kind: EventChooseColor, Player: 0, Color: Blue},
+		{Kind: EventPenalty, Player: 1, Count: 4},
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %v", len(expected), events)
+	}
+	for i, event := range expected {
+		if events[i] != event {
+			t.Errorf("Expected event %d to be %+v, got %+v", i, event, events[i])
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package game
+
+import (
+	"errors"
+	"iter"
+	"slices"
+)
+
+// handCapacity is room for a dealt hand and a few draws before the cards move
+const handCapacity = 16
+
+// Hand holds a player's cards in the order they were picked up, along with how many
+// of every kind of card it holds, so rules can ask what a hand holds without walking it
+// The zero value is an empty hand
+type Hand struct {
+	cards  []Card
+	order  []uint8 // KindOf of every card, in hand order
+	counts [CardKinds]uint16
+	kinds  uint64 // Bit KindOf(card) is set for every kind of card held
+	hash   uint64 // XOR of the hand keys of the cards, kept up to date by every change
+}
+
+// NewHand creates a hand holding the given cards in order
+func NewHand(cards ...Card) Hand {
+	size := max(len(cards), handCapacity)
+	hand := Hand{cards: make([]Card, 0, size), order: make([]uint8, 0, size)}
+	for _, card := range cards {
+		hand.Add(card)
+	}
+	return hand
+}
+
+// Len returns the number of cards in the hand
+func (h *Hand) Len() int {
+	return len(h.cards)
+}
+
+// At returns the card at the given index
+func (h *Hand) At(i int) Card {
+	return h.cards[i]
+}
+
+// Cards returns a copy of the cards in hand order
+func (h *Hand) Cards() []Card {
+	return append([]Card(nil), h.cards...)
+}
+
+// All iterates over the cards and their indices in hand order
+func (h *Hand) All() iter.Seq2[int, Card] {
+	return func(yield func(int, Card) bool) {
+		for i, card := range h.cards {
+			if !yield(i, card) {
+				return
+			}
+		}
+	}
+}
+
+// Count returns how many copies of the card the hand holds
+func (h *Hand) Count(card Card) int {
+	return int(h.counts[KindOf(card)])
+}
+
+// HasColor checks if the hand holds a card of the given color
+func (h *Hand) HasColor(color CardColor) bool {
+	return h.kinds&colorKinds(color) != 0
+}
+
+// Add puts a card at the end of the hand
+func (h *Hand) Add(card Card) {
+	kind := KindOf(card)
+	h.hash ^= handKey(kind, int(h.counts[kind]))
+	h.counts[kind]++
+	h.kinds |= 1 << kind
+	h.cards = append(h.cards, card)
+	h.order = append(h.order, uint8(kind))
+}
+
+// Remove takes the card at the given index out of the hand and returns it
+// The last card moves into its place, the order of the others is kept
+func (h *Hand) Remove(i int) Card {
+	card, kind := h.cards[i], int(h.order[i])
+	last := len(h.cards) - 1
+	h.cards[i], h.order[i] = h.cards[last], h.order[last]
+	h.cards, h.order = h.cards[:last], h.order[:last]
+
+	h.counts[kind]--
+	h.hash ^= handKey(kind, int(h.counts[kind]))
+	if h.counts[kind] == 0 {
+		h.kinds &^= 1 << kind
+	}
+	return card
+}
+
+// Clear empties the hand, keeping its storage
+func (h *Hand) Clear() {
+	h.cards, h.order = h.cards[:0], h.order[:0]
+	h.counts = [CardKinds]uint16{}
+	h.kinds = 0
+	h.hash = 0
+}
+
+// Clone returns a copy of the hand that shares no storage with it
+func (h *Hand) Clone() Hand {
+	clone := *h
+	size := max(len(h.cards), handCapacity)
+	clone.cards = append(make([]Card, 0, size), h.cards...)
+	clone.order = append(make([]uint8, 0, size), h.order...)
+	return clone
+}
+
+// check compares the counts and the hash with the ones worked out from the cards
+func (h *Hand) check() error {
+	fresh := NewHand(h.cards...)
+	if fresh.counts != h.counts || fresh.kinds != h.kinds || fresh.hash != h.hash || !slices.Equal(fresh.order, h.order) {
+		return errors.New("the counts or hash of the hand do not match its cards")
+	}
+	return nil
+}
+
+// playableKinds returns the bits of every kind of card the hand may put on the top card,
+// see KindOf. It agrees with IsPlayable, Wild Draw Four included
+func playableKinds(hand *Hand, topCard Card, activeColor CardColor) uint64 {
+	kinds := colorKinds(activeColor) | colorKinds(Wild)
+
+	// Cards showing the same number or action in any color, as long as no wild changed the color
+	if topCard.Color == activeColor && topCard.Color != Wild {
+		kinds |= symbolKinds << (KindOf(topCard) % 13)
+	}
+
+	if hand.HasColor(activeColor) {
+		kinds &^= 1 << KindOf(Card{Color: Wild, Type: WildDrawFour})
+	}
+	return kinds
+}
+
+// symbolKinds has the bit of the first kind of every color, shifting it gives
+// the bits of one number or action in all four colors
+const symbolKinds uint64 = 1 | 1<<13 | 1<<26 | 1<<39
+
+// colorKinds returns the bits of every kind of card of the color, see KindOf
+func colorKinds(color CardColor) uint64 {
+	switch {
+	case color >= Red && color <= Yellow:
+		return (1<<13 - 1) << (13 * uint(color))
+	case color == Wild:
+		return (1<<(CardKinds-4*13) - 1) << (4 * 13)
+	default:
+		return 0
+	}
+}
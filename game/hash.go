@@ -0,0 +1,177 @@
+package game
+
+// Zobrist style hashing: every fact about a state (a card in a hand, whose turn it is,
+// the active color...) has its own pseudo-random 64-bit key and a hash is the XOR of
+// the keys of all facts that hold. Hands and piles keep the hash of their cards up to
+// date as cards come and go, so hashing a state never walks its cards
+
+// CardKinds is the number of distinct cards: 13 per color and the five kinds of wilds
+const CardKinds = 4*13 + 5
+
+const (
+	tagCard uint64 = iota + 1
+	tagPileCard
+	tagHand
+	tagDrawPile
+	tagDiscardPile
+	tagHandSize
+	tagDrawSize
+	tagCurrentPlayer
+	tagActiveColor
+	tagPhase
+	tagLastPlayedBy
+	tagReversed
+	tagHasDrawn
+	tagCalledUno
+	tagHasPlayed
+)
+
+// KindOf numbers the distinct cards from 0 to CardKinds-1
+// Malformed cards share a number with a real one rather than falling out of range
+func KindOf(card Card) int {
+	return int(kinds[card.Color&15][card.Type&15][card.Value&15])
+}
+
+// kinds holds KindOf of every card by its fields, of which KindOf only looks at the
+// low bits. Looking a kind up is cheaper than the branches of kindOf
+var kinds = func() (kinds [16][16][16]uint8) {
+	for color := range kinds {
+		for cardType := range kinds[color] {
+			for value := range kinds[color][cardType] {
+				kinds[color][cardType][value] = uint8(kindOf(Card{Color: CardColor(color), Type: CardType(cardType), Value: value}))
+			}
+		}
+	}
+	return kinds
+}()
+
+// kindOf works out the number of a card from its fields
+func kindOf(card Card) int {
+	kind := 4*13 + int(card.Type-WildCard)
+	if card.Color != Wild {
+		kind = int(card.Color)*13 + int(card.Type) + 9 // Skip, Reverse and Draw Two follow the numbers
+		if card.Type == Number {
+			kind = int(card.Color)*13 + card.Value
+		}
+	}
+
+	if kind < 0 || kind >= CardKinds {
+		kind = (kind%CardKinds + CardKinds) % CardKinds
+	}
+	return kind
+}
+
+// zobrist returns the key of one fact, made of a tag and up to two numbers
+func zobrist(tag uint64, a, b int) uint64 {
+	return mix(tag<<56 ^ uint64(uint32(a))<<24 ^ uint64(uint32(b)))
+}
+
+// mix scrambles a number with the splitmix64 finalizer, so keys need no tables
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// handKey returns the key of a card of the given kind in a hand, where n counts
+// the copies already in that hand, so the order of a hand does not matter
+func handKey(kind, n int) uint64 {
+	if n < len(handKeys[kind]) {
+		return handKeys[kind][n]
+	}
+	return zobrist(tagCard, kind, n)
+}
+
+// handKeys holds the keys of the first few copies of every kind of card in a hand
+var handKeys = func() (keys [CardKinds][4]uint64) {
+	for kind := range keys {
+		for n := range keys[kind] {
+			keys[kind][n] = zobrist(tagCard, kind, n)
+		}
+	}
+	return keys
+}()
+
+// The order of a pile matters and cards come and go at both ends, so a pile is
+// hashed as a polynomial: the sum of the key of every card times pileBase to the
+// power of its position from the bottom. Adding to the top adds one term, adding
+// to the bottom multiplies the sum by pileBase first
+const pileBase uint64 = 0x9e3779b97f4a7c15
+
+// pileBaseInverse undoes a multiplication by pileBase, which is odd and so invertible
+var pileBaseInverse = func() uint64 {
+	inverse := pileBase // Newton's method, every step doubles the correct bits
+	for range 5 {
+		inverse *= 2 - pileBase*inverse
+	}
+	return inverse
+}()
+
+// pileKeys holds the key of every kind of card in a pile
+var pileKeys = func() (keys [CardKinds]uint64) {
+	for kind := range keys {
+		keys[kind] = zobrist(tagPileCard, kind, 0)
+	}
+	return keys
+}()
+
+// pileHash hashes the cards of a pile, bottom first, and returns pileBase to the power of their number
+func pileHash(cards []Card) (hash, power uint64) {
+	power = 1
+	for _, card := range cards {
+		hash += pileKeys[KindOf(card)] * power
+		power *= pileBase
+	}
+	return hash, power
+}
+
+// Hash returns a hash of the complete state, hidden hands and draw order included
+// Equal states have equal hashes, which makes it usable for transposition tables
+// and for spotting a game that keeps coming back to the same position
+func (s *GameState) Hash() uint64 {
+	hash := s.publicFlags()
+
+	for i, player := range s.Players {
+		hash ^= mix(player.Hand.hash ^ zobrist(tagHand, i, 0))
+	}
+	return hash ^ mix(s.DrawPile.hash^zobrist(tagDrawPile, 0, 0))
+}
+
+// PublicHash returns a hash of what every player can see: hand sizes instead of
+// hands and the draw pile's size instead of its order
+func (s *GameState) PublicHash() uint64 {
+	hash := s.publicFlags()
+
+	for i, player := range s.Players {
+		hash ^= zobrist(tagHandSize, i, player.Hand.Len())
+	}
+	return hash ^ zobrist(tagDrawSize, 0, s.DrawPile.Size())
+}
+
+// publicFlags hashes the discard pile and the public fields of the state and players
+func (s *GameState) publicFlags() uint64 {
+	hash := zobrist(tagCurrentPlayer, 0, s.CurrentPlayer) ^
+		zobrist(tagActiveColor, 0, int(s.ActiveColor)) ^
+		zobrist(tagPhase, 0, int(s.Phase)) ^
+		zobrist(tagLastPlayedBy, 0, s.LastPlayedBy) ^
+		mix(s.DiscardPile.hash^zobrist(tagDiscardPile, 0, 0))
+
+	if s.Reversed {
+		hash ^= zobrist(tagReversed, 0, 0)
+	}
+	if s.HasDrawn {
+		hash ^= zobrist(tagHasDrawn, 0, 0)
+	}
+
+	for i, player := range s.Players {
+		if player.HasCalledUno {
+			hash ^= zobrist(tagCalledUno, i, 0)
+		}
+		if player.hasPlayedCard {
+			hash ^= zobrist(tagHasPlayed, i, 0)
+		}
+	}
+
+	return hash
+}
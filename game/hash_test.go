@@ -0,0 +1,136 @@
+package game
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// rehashed returns a copy of the state whose hands and piles were rebuilt from their cards,
+// so its hash is worked out from scratch instead of kept up to date
+func rehashed(state *GameState) *GameState {
+	clone := state.Clone()
+	for _, player := range clone.Players {
+		player.Hand = NewHand(player.Hand.Cards()...)
+	}
+	clone.DrawPile = NewDeckOf(clone.DrawPile.Cards())
+	clone.DiscardPile = NewDeckOf(clone.DiscardPile.Cards())
+	return clone
+}
+
+// Test that equal states hash equally and any change shows up in the hash
+func TestHash(t *testing.T) {
+	state := createTestGameState()
+	state.Players[0].AddCardsToHand([]Card{{Color: Red, Type: Number, Value: 7}, {Color: Red, Type: Number, Value: 7}, {Color: Wild, Type: WildCard}})
+	state.Players[1].AddCard(Card{Color: Blue, Type: Skip})
+
+	clone := state.Clone()
+	if clone.Hash() != state.Hash() || clone.PublicHash() != state.PublicHash() {
+		t.Fatal("Expected a clone to hash like the original")
+	}
+
+	// Hand order does not matter
+	clone.Players[0].Hand = NewHand(Card{Color: Wild, Type: WildCard}, Card{Color: Red, Type: Number, Value: 7}, Card{Color: Red, Type: Number, Value: 7})
+	if clone.Hash() != state.Hash() {
+		t.Error("Expected the hash to ignore the order of a hand")
+	}
+
+	// Duplicate cards do not cancel each other out
+	clone.Players[0].Hand = NewHand(Card{Color: Wild, Type: WildCard}, Card{Color: Wild, Type: WildCard})
+	clone.Players[0].AddCard(Card{Color: Red, Type: Number, Value: 7})
+	if clone.Hash() == state.Hash() {
+		t.Error("Expected a hand without the pair of Red 7s to hash differently")
+	}
+
+	// A hidden card change shows in the full hash only
+	clone = state.Clone()
+	clone.Players[1].Hand.Remove(0)
+	clone.Players[1].AddCard(Card{Color: Green, Type: Skip})
+	if clone.Hash() == state.Hash() {
+		t.Error("Expected a different hand to change the hash")
+	}
+	if clone.PublicHash() != state.PublicHash() {
+		t.Error("Expected a different hidden hand to keep the public hash")
+	}
+
+	// Swapping two hands changes the hash
+	clone = state.Clone()
+	clone.Players[0].Hand, clone.Players[1].Hand = clone.Players[1].Hand, clone.Players[0].Hand
+	if clone.Hash() == state.Hash() {
+		t.Error("Expected swapped hands to change the hash")
+	}
+
+	// So does the order of the draw pile
+	clone = state.Clone()
+	cards := clone.DrawPile.Cards()
+	cards = append([]Card{cards[len(cards)-1]}, cards[:len(cards)-1]...)
+	clone.DrawPile.Replace(cards)
+	if clone.Hash() == state.Hash() || clone.PublicHash() != state.PublicHash() {
+		t.Error("Expected the draw order to change the full hash only")
+	}
+
+	clone = state.Clone()
+	clone.ActiveColor = Yellow
+	if clone.PublicHash() == state.PublicHash() {
+		t.Error("Expected the active color to change the public hash")
+	}
+}
+
+// Test that a pile hashes the same whichever end its cards were added at
+func TestPileHash(t *testing.T) {
+	red, blue, wild := Card{Color: Red, Type: Skip}, Card{Color: Blue, Type: Number, Value: 4}, Card{Color: Wild, Type: WildCard}
+
+	fromTop := &Deck{}
+	fromTop.AddToTop(red)
+	fromTop.AddToTop(blue)
+	fromTop.AddToTop(wild)
+
+	fromBottom := CreateDiscardPile(blue)
+	fromBottom.AddToTop(wild)
+	fromBottom.AddToBottom(red)
+
+	if fromTop.hash != fromBottom.hash || fromTop.check() != nil || fromBottom.check() != nil {
+		t.Errorf("Expected piles with the same cards in the same order to hash equally, got %x and %x", fromTop.hash, fromBottom.hash)
+	}
+
+	if card, _ := fromBottom.Draw(); card != wild || fromBottom.check() != nil {
+		t.Errorf("Expected drawing %v to keep the hash up to date", card)
+	}
+	if reversed := NewDeckOf([]Card{blue, red}); reversed.hash == fromBottom.hash {
+		t.Error("Expected the order of a pile to change its hash")
+	}
+}
+
+// Test that the hash kept up to date by every move matches the one worked out from scratch
+func TestHashIncremental(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	rules := NewGameRules()
+
+	for game := range 20 {
+		opts := ModernOptions()
+		names := []*Player{NewPlayer("Player 1"), NewPlayer("Player 2")}
+		if game%2 == 1 {
+			opts.Teams = true
+			names = append(names, NewPlayer("Player 3"), NewPlayer("Player 4"))
+		}
+		state, err := NewGameStateWithRandom(names, opts, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for moves := 0; state.Phase != PhaseGameOver && moves < 2000; moves++ {
+			legal := LegalMoves(state, state.CurrentPlayer)
+			if err := rules.Apply(state, legal[r.IntN(len(legal))]); err != nil {
+				t.Fatal(err)
+			}
+
+			fresh := rehashed(state)
+			if state.Hash() != fresh.Hash() || state.PublicHash() != fresh.PublicHash() {
+				t.Fatalf("Expected the hash after move %d of game %d to match the full hash", moves+1, game)
+			}
+		}
+
+		if err := state.Validate(); err != nil {
+			t.Errorf("Expected the counts and hashes of game %d to match its cards: %v", game, err)
+		}
+	}
+}
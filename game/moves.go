@@ -0,0 +1,325 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type MoveKind int
+
+const (
+	MovePlay        MoveKind = iota // Play a card from the hand
+	MoveDraw                        // Draw a card from the draw pile
+	MovePass                        // Keep the drawn card and end the turn
+	MoveChooseColor                 // Pick the color for the wild card on top of the discard pile
+	MoveCallUno                     // Call UNO with one card left
+	MoveChallenge                   // Catch a player who forgot to call UNO
+)
+
+// Move is a complete action a player can take, including every choice it needs
+type Move struct {
+	Kind      MoveKind
+	Player    int       // Index of the player making the move
+	CardIndex int       // Hand index of the card to play, only used by MovePlay
+	Color     CardColor // Chosen color when playing or resolving a wild card
+	Target    int       // Swap target for Wild Swap Hands, or the player being challenged
+}
+
+func (k MoveKind) String() string {
+	switch k {
+	case MovePlay:
+		return "Play"
+	case MoveDraw:
+		return "Draw"
+	case MovePass:
+		return "Pass"
+	case MoveChooseColor:
+		return "Choose Color"
+	case MoveCallUno:
+		return "Call UNO"
+	case MoveChallenge:
+		return "Challenge"
+	default:
+		return "Unknown"
+	}
+}
+
+// IllegalMoveError is returned by Apply for a move the rules do not allow right now
+// Reason is the rules' explanation, fit to show to players
+type IllegalMoveError struct {
+	Move   Move
+	Reason error
+}
+
+func (e *IllegalMoveError) Error() string {
+	return fmt.Sprintf("illegal move: %v: %v", e.Move, e.Reason)
+}
+
+func (e *IllegalMoveError) Unwrap() error {
+	return e.Reason
+}
+
+func (m Move) String() string {
+	switch m.Kind {
+	case MovePlay:
+		return fmt.Sprintf("Player %d plays card %d (%v, target %d)", m.Player, m.CardIndex, m.Color, m.Target)
+	case MoveChooseColor:
+		return fmt.Sprintf("Player %d chooses %v (target %d)", m.Player, m.Color, m.Target)
+	case MoveChallenge:
+		return fmt.Sprintf("Player %d challenges player %d", m.Player, m.Target)
+	default:
+		return fmt.Sprintf("Player %d: %v", m.Player, m.Kind)
+	}
+}
+
+// LegalMoves lists every complete move the player at the given seat can make right now
+// Play moves follow ValidateMove so the list always agrees with HandlePlayCard,
+// wild cards get one move per color and, for Wild Swap Hands, per target
+func LegalMoves(state *GameState, seat int) []Move {
+	return AppendLegalMoves(nil, state, seat)
+}
+
+// AppendLegalMoves appends the moves LegalMoves lists to moves and returns the result
+// Search code passes the same buffer every time to avoid allocating
+func AppendLegalMoves(moves []Move, state *GameState, seat int) []Move {
+	if seat < 0 || seat >= len(state.Players) {
+		return moves
+	}
+
+	if state.Phase != PhasePlay && state.Phase != PhaseColorSelection {
+		return moves
+	}
+
+	player := state.Players[seat]
+	if moves == nil {
+		moves = make([]Move, 0, player.Hand.Len()+4)
+	}
+
+	// UNO calls and challenges can be made out of turn
+	if player.ShouldCallUno() && !player.HasCalledUno {
+		moves = append(moves, Move{Kind: MoveCallUno, Player: seat})
+	}
+
+	for target, other := range state.Players {
+		if target != seat && other.ShouldCallUno() && !other.HasCalledUno {
+			moves = append(moves, Move{Kind: MoveChallenge, Player: seat, Target: target})
+		}
+	}
+
+	if !player.IsMyTurn {
+		return moves
+	}
+
+	if state.Phase == PhaseColorSelection {
+		topCard, err := state.DiscardPile.Top()
+		if err != nil {
+			return moves
+		}
+
+		// The starting card never swaps hands
+		needsTarget := topCard.Type == WildSwapHands && state.LastPlayedBy != -1
+		return appendWildMoves(moves, state, Move{Kind: MoveChooseColor, Player: seat}, needsTarget)
+	}
+
+	// The same checks as ValidateMove, with every kind of card that may be played worked out once
+	hand := &player.Hand
+	topCard, err := state.DiscardPile.Top()
+	first := 0
+	if err != nil {
+		first = hand.Len()
+	} else if state.HasDrawn {
+		first = hand.Len() - 1
+	}
+
+	playable := playableKinds(hand, topCard, state.ActiveColor)
+	if hand.kinds&playable == 0 {
+		first = hand.Len()
+	}
+
+	for i := first; i < hand.Len(); i++ {
+		card := hand.cards[i]
+		if playable&(1<<KindOf(card)) == 0 {
+			continue
+		}
+
+		move := Move{Kind: MovePlay, Player: seat, CardIndex: i}
+		if card.Color == Wild {
+			moves = appendWildMoves(moves, state, move, card.Type == WildSwapHands)
+		} else {
+			moves = append(moves, move)
+		}
+	}
+
+	if canDraw(state) {
+		moves = append(moves, Move{Kind: MoveDraw, Player: seat})
+	} else {
+		moves = append(moves, Move{Kind: MovePass, Player: seat})
+	}
+
+	return moves
+}
+
+// moveRules validates plays for the move checker, GameRules holds no state
+var moveRules = NewGameRules()
+
+// appendWildMoves adds one copy of the move for every color, and every swap target if needed
+func appendWildMoves(moves []Move, state *GameState, move Move, needsTarget bool) []Move {
+	if !needsTarget {
+		n := len(moves)
+		moves = slices.Grow(moves, 4)[:n+4]
+		for i := range 4 {
+			moves[n+i] = move
+			moves[n+i].Color = Red + CardColor(i)
+		}
+		return moves
+	}
+
+	for color := Red; color <= Yellow; color++ {
+		move.Color = color
+		for target := range state.Players {
+			if target != move.Player {
+				move.Target = target
+				moves = append(moves, move)
+			}
+		}
+	}
+
+	return moves
+}
+
+// canDraw checks if the current player may still draw a card this turn
+func canDraw(state *GameState) bool {
+	if state.HasDrawn {
+		return false
+	}
+	return !state.DrawPile.IsEmpty() || state.DiscardPile.Size() > 1
+}
+
+// IsLegalMove checks if a move is one of the legal moves for its player
+// Fields the move kind does not use are ignored
+func IsLegalMove(state *GameState, move Move) bool {
+	return checkMove(state, move) == nil
+}
+
+// checkMove accepts exactly the moves LegalMoves lists, without listing them all
+func checkMove(state *GameState, move Move) error {
+	if move.Player < 0 || move.Player >= len(state.Players) {
+		return errors.New("invalid player index")
+	}
+
+	if state.Phase != PhasePlay && state.Phase != PhaseColorSelection {
+		return errors.New("the game is not being played")
+	}
+
+	player := state.Players[move.Player]
+
+	switch move.Kind {
+	case MoveCallUno:
+		if !player.ShouldCallUno() || player.HasCalledUno {
+			return errors.New("no UNO to call")
+		}
+		return nil
+	case MoveChallenge:
+		if move.Target == move.Player || move.Target < 0 || move.Target >= len(state.Players) {
+			return errors.New("invalid challenge target")
+		}
+		target := state.Players[move.Target]
+		if !target.ShouldCallUno() || target.HasCalledUno {
+			return errors.New("the player cannot be challenged")
+		}
+		return nil
+	}
+
+	if !player.IsMyTurn {
+		return errors.New("it is not your turn")
+	}
+
+	switch move.Kind {
+	case MovePlay:
+		if state.Phase != PhasePlay {
+			return errors.New("game is not in the play phase")
+		}
+		if valid, message := moveRules.ValidateMove(player, move.CardIndex, state); !valid {
+			return errors.New(message)
+		}
+
+		card := player.Hand.At(move.CardIndex)
+		if card.Color == Wild {
+			return checkWildChoice(state, move, card.Type == WildSwapHands)
+		}
+		return nil
+	case MoveDraw, MovePass:
+		if state.Phase != PhasePlay {
+			return errors.New("game is not in the play phase")
+		}
+		if (move.Kind == MoveDraw) != canDraw(state) {
+			return fmt.Errorf("cannot %v now", move.Kind)
+		}
+		return nil
+	case MoveChooseColor:
+		topCard, err := state.DiscardPile.Top()
+		if state.Phase != PhaseColorSelection || err != nil {
+			return errors.New("no color to choose")
+		}
+		return checkWildChoice(state, move, topCard.Type == WildSwapHands && state.LastPlayedBy != -1)
+	default:
+		return fmt.Errorf("unknown move kind: %v", move.Kind)
+	}
+}
+
+// checkWildChoice checks the color, and the target if needed, of a move resolving a wild card
+func checkWildChoice(state *GameState, move Move, needsTarget bool) error {
+	if move.Color < Red || move.Color > Yellow {
+		return errors.New("invalid color choice")
+	}
+	if needsTarget && (move.Target == move.Player || move.Target < 0 || move.Target >= len(state.Players)) {
+		return errors.New("invalid swap target")
+	}
+	return nil
+}
+
+// Apply checks that a move is legal and carries it out through the matching handler
+func (gr *GameRules) Apply(state *GameState, move Move) error {
+	if err := checkMove(state, move); err != nil {
+		return &IllegalMoveError{Move: move, Reason: err}
+	}
+	return gr.apply(state, move)
+}
+
+// apply carries out a move that is known to be legal
+func (gr *GameRules) apply(state *GameState, move Move) error {
+	if debugChecks {
+		defer gr.operation(state, move)()
+	}
+
+	player := state.Players[move.Player]
+
+	switch move.Kind {
+	case MovePlay:
+		var choice *Choice
+		if player.Hand.At(move.CardIndex).Color == Wild {
+			choice = &Choice{Color: move.Color, Target: move.Target}
+		}
+		return gr.playCard(player, move.CardIndex, state, choice)
+	case MoveDraw:
+		return gr.drawCard(player, state)
+	case MovePass:
+		gr.endTurn(state)
+		return nil
+	case MoveChooseColor:
+		return gr.HandleColorSelection(player, state, Choice{Color: move.Color, Target: move.Target})
+	case MoveCallUno:
+		if ok, message := gr.HandleUnoCall(move.Player, state); !ok {
+			return errors.New(message)
+		}
+		return nil
+	case MoveChallenge:
+		if ok, message := gr.HandleUnoChallenge(move.Target, state); !ok {
+			return errors.New(message)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown move kind: %v", move.Kind)
+	}
+}
@@ -0,0 +1,313 @@
+package game
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+// Helper function to count moves of a given kind
+func countMoves(moves []Move, kind MoveKind) int {
+	count := 0
+	for _, move := range moves {
+		if move.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Test LegalMoves for a regular turn
+func TestLegalMoves(t *testing.T) {
+	state := createTestGameState()
+
+	redSeven := Card{Color: Red, Type: Number, Value: 7}
+	blueSkip := Card{Color: Blue, Type: Skip}
+	wildCard := Card{Color: Wild, Type: WildCard}
+	state.Players[0].AddCardsToHand([]Card{redSeven, blueSkip, wildCard})
+
+	moves := LegalMoves(state, 0)
+
+	// Red 7 once, Wild once per color, plus drawing
+	if countMoves(moves, MovePlay) != 5 {
+		t.Errorf("Expected 5 play moves, got %d", countMoves(moves, MovePlay))
+	}
+
+	if countMoves(moves, MoveDraw) != 1 {
+		t.Errorf("Expected 1 draw move, got %d", countMoves(moves, MoveDraw))
+	}
+
+	if countMoves(moves, MovePass) != 0 {
+		t.Error("Expected passing to require drawing first")
+	}
+
+	for _, move := range moves {
+		if move.Kind == MovePlay && move.CardIndex == 1 {
+			t.Error("Expected Blue Skip not to be playable on Red 5")
+		}
+	}
+
+	// The other player has nothing to do out of turn
+	if len(LegalMoves(state, 1)) != 0 {
+		t.Errorf("Expected no moves for the waiting player, got %v", LegalMoves(state, 1))
+	}
+
+	// Invalid seat
+	if LegalMoves(state, 5) != nil {
+		t.Error("Expected no moves for an invalid seat")
+	}
+}
+
+// Test that every wild color and swap target is listed
+func TestLegalMovesWildChoices(t *testing.T) {
+	state := createTestTeamGameState()
+	state.Players[0].AddCard(Card{Color: Wild, Type: WildSwapHands})
+
+	moves := LegalMoves(state, 0)
+
+	// Four colors times three possible targets
+	if countMoves(moves, MovePlay) != 12 {
+		t.Errorf("Expected 12 play moves for Wild Swap Hands, got %d", countMoves(moves, MovePlay))
+	}
+
+	for _, move := range moves {
+		if move.Kind == MovePlay && move.Target == 0 {
+			t.Error("Expected no move swapping hands with yourself")
+		}
+	}
+
+	// Pending color selection lists the color choices only
+	state.Phase = PhaseColorSelection
+	state.DiscardPile.AddToTop(Card{Color: Wild, Type: WildCard})
+	state.LastPlayedBy = 0
+
+	moves = LegalMoves(state, 0)
+	if len(moves) != 4 || countMoves(moves, MoveChooseColor) != 4 {
+		t.Errorf("Expected 4 color choices, got %v", moves)
+	}
+}
+
+// Test the moves available after drawing a card
+func TestLegalMovesAfterDrawing(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestGameState()
+
+	state.Players[0].AddCard(Card{Color: Red, Type: Number, Value: 7})
+	state.DrawPile = NewDeckOf([]Card{{Color: Red, Type: Number, Value: 1}})
+
+	if err := rules.Apply(state, Move{Kind: MoveDraw, Player: 0}); err != nil {
+		t.Fatalf("Expected no error when drawing, got %v", err)
+	}
+
+	moves := LegalMoves(state, 0)
+
+	// Only the drawn card can be played, or the turn can be ended
+	if countMoves(moves, MovePlay) != 1 || moves[0].CardIndex != 1 {
+		t.Errorf("Expected only the drawn card to be playable, got %v", moves)
+	}
+
+	if countMoves(moves, MoveDraw) != 0 || countMoves(moves, MovePass) != 1 {
+		t.Errorf("Expected a pass instead of a second draw, got %v", moves)
+	}
+
+	valid, _ := rules.ValidateMove(state.Players[0], 0, state)
+	if valid {
+		t.Error("Expected ValidateMove to reject cards other than the drawn one")
+	}
+
+	if err := rules.HandleDrawCard(state.Players[0], state); err == nil {
+		t.Error("Expected error when drawing twice in a turn")
+	}
+
+	if err := rules.Apply(state, Move{Kind: MovePass, Player: 0}); err != nil {
+		t.Errorf("Expected no error when passing, got %v", err)
+	}
+
+	if state.CurrentPlayer != 1 || state.HasDrawn {
+		t.Error("Expected the turn to pass with a fresh draw allowance")
+	}
+}
+
+// Test UNO calls and challenges made out of turn
+func TestLegalMovesUno(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestGameState()
+
+	state.Players[0].Hand = NewHand(Card{Color: Blue, Type: Number, Value: 1})
+	state.Players[0].hasPlayedCard = true
+
+	moves := LegalMoves(state, 1)
+	if len(moves) != 1 || moves[0].Kind != MoveChallenge || moves[0].Target != 0 {
+		t.Errorf("Expected the opponent to be able to challenge, got %v", moves)
+	}
+
+	if countMoves(LegalMoves(state, 0), MoveCallUno) != 1 {
+		t.Error("Expected the player to be able to call UNO")
+	}
+
+	if err := rules.Apply(state, Move{Kind: MoveCallUno, Player: 0}); err != nil {
+		t.Errorf("Expected no error when calling UNO, got %v", err)
+	}
+
+	if len(LegalMoves(state, 1)) != 0 {
+		t.Error("Expected no challenge after UNO was called")
+	}
+}
+
+// Test that Apply rejects moves LegalMoves does not list
+func TestApplyIllegalMove(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestGameState()
+	state.Players[0].AddCard(Card{Color: Blue, Type: Skip})
+
+	illegal := []Move{
+		{Kind: MovePlay, Player: 0, CardIndex: 0},
+		{Kind: MovePlay, Player: 0, CardIndex: 3},
+		{Kind: MoveDraw, Player: 1},
+		{Kind: MovePass, Player: 0},
+		{Kind: MoveChooseColor, Player: 0, Color: Blue},
+		{Kind: MoveChallenge, Player: 0, Target: 1},
+		{Kind: MoveDraw, Player: 4},
+	}
+
+	for _, move := range illegal {
+		if err := rules.Apply(state, move); err == nil {
+			t.Errorf("Expected error applying %v", move)
+		}
+	}
+}
+
+// Test that every generated move is accepted by Apply over full random games
+func TestLegalMovesAgreeWithApply(t *testing.T) {
+	rules := NewGameRules()
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for game := range 20 {
+		players := []*Player{NewPlayer("A"), NewPlayer("B")}
+		state, err := NewGameStateWithOptions(players, GameOptions{SwapHandsCards: 2, ShuffleHandsCards: 1, CustomizableCards: 3, CustomRule: CustomRuleDrawTwo})
+		if err != nil {
+			t.Fatalf("Expected no error creating game, got %v", err)
+		}
+
+		for step := 0; step < 2000 && state.Phase != PhaseGameOver; step++ {
+			seat := state.CurrentPlayer
+			moves := LegalMoves(state, seat)
+			if len(moves) == 0 {
+				t.Fatalf("Game %d step %d: expected the current player to have a move", game, step)
+			}
+
+			// Every listed play must also pass ValidateMove
+			for _, move := range moves {
+				if move.Kind == MovePlay {
+					if valid, message := rules.ValidateMove(state.Players[seat], move.CardIndex, state); !valid {
+						t.Fatalf("Game %d step %d: listed move %v rejected: %s", game, step, move, message)
+					}
+				}
+			}
+
+			// Every card ValidateMove accepts must be listed
+			for i := range state.Players[seat].Hand.Len() {
+				valid, _ := rules.ValidateMove(state.Players[seat], i, state)
+				listed := false
+				for _, move := range moves {
+					listed = listed || (move.Kind == MovePlay && move.CardIndex == i)
+				}
+				if valid != listed {
+					t.Fatalf("Game %d step %d: card %d valid=%t but listed=%t", game, step, i, valid, listed)
+				}
+			}
+
+			// IsLegalMove accepts exactly the listed moves, for every seat
+			for other := range state.Players {
+				if step%5 != 0 {
+					break
+				}
+				listed := LegalMoves(state, other)
+				for _, candidate := range candidateMoves(state, other) {
+					if legal, found := IsLegalMove(state, candidate), containsMove(state, listed, candidate); legal != found {
+						t.Fatalf("Game %d step %d: %v legal=%t but listed=%t", game, step, candidate, legal, found)
+					}
+				}
+			}
+
+			move := moves[rng.IntN(len(moves))]
+			if err := rules.Apply(state, move); err != nil {
+				t.Fatalf("Game %d step %d: expected %v to apply, got %v", game, step, move, err)
+			}
+		}
+	}
+}
+
+// candidateMoves builds every move of every kind the seat could try, legal or not
+func candidateMoves(state *GameState, seat int) []Move {
+	candidates := make([]Move, 0)
+	for kind := MovePlay; kind <= MoveChallenge; kind++ {
+		for target := -1; target <= len(state.Players); target++ {
+			for color := Red; color <= Wild; color++ {
+				move := Move{Kind: kind, Player: seat, Color: color, Target: target}
+				if kind != MovePlay {
+					candidates = append(candidates, move)
+					continue
+				}
+
+				for index := -1; index <= state.Players[seat].Hand.Len(); index++ {
+					move.CardIndex = index
+					candidates = append(candidates, move)
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+// containsMove checks if a listed move matches the candidate on the fields its kind uses
+func containsMove(state *GameState, listed []Move, candidate Move) bool {
+	for _, move := range listed {
+		if normalizeTestMove(state, move) == normalizeTestMove(state, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTestMove clears the fields a move does not use
+func normalizeTestMove(state *GameState, move Move) Move {
+	normalized := Move{Kind: move.Kind, Player: move.Player}
+
+	switch move.Kind {
+	case MovePlay:
+		normalized.CardIndex = move.CardIndex
+		hand := state.Players[move.Player].Hand
+		if move.CardIndex >= 0 && move.CardIndex < hand.Len() && hand.At(move.CardIndex).Color == Wild {
+			normalized.Color = move.Color
+			if hand.At(move.CardIndex).Type == WildSwapHands {
+				normalized.Target = move.Target
+			}
+		}
+	case MoveChooseColor:
+		normalized.Color = move.Color
+		if top, err := state.DiscardPile.Top(); err == nil && top.Type == WildSwapHands && state.LastPlayedBy != -1 {
+			normalized.Target = move.Target
+		}
+	case MoveChallenge:
+		normalized.Target = move.Target
+	}
+
+	return normalized
+}
+
+func TestIllegalMoveError(t *testing.T) {
+	state, err := NewBuilder("Alice", "Bob").Hand(0, "R7").Hand(1, "B3").Discard("G5").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewGameRules().Apply(state, Move{Kind: MovePlay, Player: 0, CardIndex: 0})
+	var illegal *IllegalMoveError
+	if !errors.As(err, &illegal) {
+		t.Fatalf("Expected an IllegalMoveError, got %v", err)
+	}
+	if illegal.Move.CardIndex != 0 || illegal.Reason == nil {
+		t.Errorf("Expected the move and the reason, got %+v", illegal)
+	}
+}
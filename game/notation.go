@@ -0,0 +1,212 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Compact card notation, used for logs, replay files, test fixtures and text clients:
+// a color letter R, B, G or Y followed by the number, S for Skip, R for Reverse or
+// +2 for Draw Two, so R7, BS, GR and Y+2. Wilds start with W: W, W+4, WSH for
+// Shuffle Hands, WSW for Swap Hands and WC for Customizable
+
+var colorLetters = map[CardColor]string{
+	Red:    "R",
+	Blue:   "B",
+	Green:  "G",
+	Yellow: "Y",
+	Wild:   "W",
+}
+
+var actionCodes = map[CardType]string{
+	Skip:    "S",
+	Reverse: "R",
+	DrawTwo: "+2",
+}
+
+var wildCodes = map[CardType]string{
+	WildCard:         "W",
+	WildDrawFour:     "W+4",
+	WildShuffleHands: "WSH",
+	WildSwapHands:    "WSW",
+	WildCustomizable: "WC",
+}
+
+var colorNames = map[CardColor]string{
+	Red:    "red",
+	Blue:   "blue",
+	Green:  "green",
+	Yellow: "yellow",
+	Wild:   "wild",
+}
+
+var typeNames = map[CardType]string{
+	Number:           "number",
+	Skip:             "skip",
+	Reverse:          "reverse",
+	DrawTwo:          "draw-two",
+	WildCard:         "wild",
+	WildDrawFour:     "wild-draw-four",
+	WildShuffleHands: "wild-shuffle-hands",
+	WildSwapHands:    "wild-swap-hands",
+	WildCustomizable: "wild-customizable",
+}
+
+// Notation returns the card in compact notation, like R7 or W+4
+func (c Card) Notation() (string, error) {
+	if c.Color == Wild {
+		code, ok := wildCodes[c.Type]
+		if !ok {
+			return "", fmt.Errorf("invalid wild card: %v", c.Type)
+		}
+		return code, nil
+	}
+
+	letter, ok := colorLetters[c.Color]
+	if !ok {
+		return "", fmt.Errorf("invalid card color: %d", int(c.Color))
+	}
+
+	if c.Type == Number {
+		if c.Value < 0 {
+			return "", fmt.Errorf("invalid card value: %d", c.Value)
+		}
+		return letter + strconv.Itoa(c.Value), nil
+	}
+
+	code, ok := actionCodes[c.Type]
+	if !ok {
+		return "", fmt.Errorf("invalid colored card: %v", c.Type)
+	}
+	return letter + code, nil
+}
+
+// ParseCard reads a card written in compact notation, ignoring case
+func ParseCard(s string) (Card, error) {
+	text := strings.ToUpper(strings.TrimSpace(s))
+
+	for cardType, code := range wildCodes {
+		if text == code {
+			return Card{Color: Wild, Type: cardType}, nil
+		}
+	}
+
+	if text == "" {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+
+	var card Card
+	found := false
+	for color, letter := range colorLetters {
+		if color != Wild && text[:1] == letter {
+			card.Color = color
+			found = true
+		}
+	}
+	if !found {
+		return Card{}, fmt.Errorf("invalid card %q: unknown color", s)
+	}
+
+	rest := text[1:]
+	for cardType, code := range actionCodes {
+		if rest == code {
+			card.Type = cardType
+			return card, nil
+		}
+	}
+
+	value, err := strconv.Atoi(rest)
+	if err != nil || value < 0 || rest[0] == '+' {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+	card.Type = Number
+	card.Value = value
+	return card, nil
+}
+
+// ParseHand reads cards in compact notation separated by spaces or commas
+func ParseHand(s string) ([]Card, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+
+	cards := make([]Card, 0, len(fields))
+	for _, field := range fields {
+		card, err := ParseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// FormatHand writes cards in compact notation separated by spaces
+func FormatHand(cards []Card) (string, error) {
+	codes := make([]string, len(cards))
+	for i, card := range cards {
+		code, err := card.Notation()
+		if err != nil {
+			return "", err
+		}
+		codes[i] = code
+	}
+	return strings.Join(codes, " "), nil
+}
+
+// MarshalText writes the card in compact notation
+func (c Card) MarshalText() ([]byte, error) {
+	code, err := c.Notation()
+	return []byte(code), err
+}
+
+// UnmarshalText reads a card in compact notation
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// MarshalText writes the color as a lowercase name like "red"
+func (c CardColor) MarshalText() ([]byte, error) {
+	name, ok := colorNames[c]
+	if !ok {
+		return nil, fmt.Errorf("unknown card color: %d", int(c))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText reads a color written by MarshalText or as its letter, like "R"
+func (c *CardColor) UnmarshalText(text []byte) error {
+	for color, name := range colorNames {
+		if strings.EqualFold(string(text), name) || strings.EqualFold(string(text), colorLetters[color]) {
+			*c = color
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown card color: %q", text)
+}
+
+// MarshalText writes the type as a short name like "draw-two"
+func (t CardType) MarshalText() ([]byte, error) {
+	name, ok := typeNames[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown card type: %d", int(t))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText reads a type written by MarshalText
+func (t *CardType) UnmarshalText(text []byte) error {
+	for cardType, name := range typeNames {
+		if strings.EqualFold(string(text), name) {
+			*t = cardType
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown card type: %q", text)
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCardNotation(t *testing.T) {
+	tests := []struct {
+		card     Card
+		notation string
+	}{
+		{Card{Color: Red, Type: Number, Value: 7}, "R7"},
+		{Card{Color: Yellow, Type: Number, Value: 0}, "Y0"},
+		{Card{Color: Blue, Type: Number, Value: 12}, "B12"},
+		{Card{Color: Blue, Type: Skip}, "BS"},
+		{Card{Color: Green, Type: Reverse}, "GR"},
+		{Card{Color: Yellow, Type: DrawTwo}, "Y+2"},
+		{Card{Color: Wild, Type: WildCard}, "W"},
+		{Card{Color: Wild, Type: WildDrawFour}, "W+4"},
+		{Card{Color: Wild, Type: WildShuffleHands}, "WSH"},
+		{Card{Color: Wild, Type: WildSwapHands}, "WSW"},
+		{Card{Color: Wild, Type: WildCustomizable}, "WC"},
+	}
+
+	for _, tt := range tests {
+		notation, err := tt.card.Notation()
+		if err != nil || notation != tt.notation {
+			t.Errorf("Expected %v to be written %s, got %q and %v", tt.card, tt.notation, notation, err)
+		}
+
+		card, err := ParseCard(tt.notation)
+		if err != nil || card != tt.card {
+			t.Errorf("Expected %s to read as %v, got %v and %v", tt.notation, tt.card, card, err)
+		}
+	}
+
+	// Every card of the deck survives the round trip
+	for _, card := range NewDeckWithOptions(ModernOptions()).Cards() {
+		notation, err := card.Notation()
+		if err != nil {
+			t.Fatalf("Expected no error writing %v, got %v", card, err)
+		}
+		if parsed, err := ParseCard(notation); err != nil || parsed != card {
+			t.Errorf("Expected %s to read back as %v, got %v", notation, card, parsed)
+		}
+	}
+
+	if card, err := ParseCard(" g+2 "); err != nil || card != (Card{Color: Green, Type: DrawTwo}) {
+		t.Errorf("Expected lowercase notation to be read, got %v and %v", card, err)
+	}
+
+	for _, invalid := range []string{"", "R", "X7", "R+4", "R-1", "RX", "W+2", "7"} {
+		if _, err := ParseCard(invalid); err == nil {
+			t.Errorf("Expected error reading %q", invalid)
+		}
+	}
+
+	for _, invalid := range []Card{{Color: Wild, Type: Skip}, {Color: Red, Type: WildCard}, {Color: 9, Type: Number}} {
+		if _, err := invalid.Notation(); err == nil {
+			t.Errorf("Expected error writing %+v", invalid)
+		}
+	}
+}
+
+func TestParseHand(t *testing.T) {
+	hand, err := ParseHand("R7, BS GR\tY+2,W+4")
+	if err != nil {
+		t.Fatalf("Expected no error reading the hand, got %v", err)
+	}
+
+	formatted, err := FormatHand(hand)
+	if err != nil || formatted != "R7 BS GR Y+2 W+4" {
+		t.Errorf("Expected the hand to be written back, got %q and %v", formatted, err)
+	}
+
+	if _, err := ParseHand("R7 Q3"); err == nil {
+		t.Error("Expected error for a hand with an invalid card")
+	}
+
+	if hand, err := ParseHand(""); err != nil || len(hand) != 0 {
+		t.Errorf("Expected an empty hand, got %v and %v", hand, err)
+	}
+}
+
+func TestCardText(t *testing.T) {
+	type record struct {
+		Card  Card      `json:"card"`
+		Color CardColor `json:"color"`
+		Type  CardType  `json:"type"`
+	}
+
+	original := record{Card{Color: Blue, Type: Number, Value: 4}, Yellow, WildDrawFour}
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"card":"B4","color":"yellow","type":"wild-draw-four"}` {
+		t.Errorf("Expected compact JSON, got %s", data)
+	}
+
+	var decoded record
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != original {
+		t.Errorf("Expected %+v back, got %+v and %v", original, decoded, err)
+	}
+
+	var color CardColor
+	if err := color.UnmarshalText([]byte("G")); err != nil || color != Green {
+		t.Errorf("Expected the letter G to read as Green, got %v and %v", color, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"color":"purple"}`), &decoded); err == nil {
+		t.Error("Expected error for an unknown color")
+	}
+
+	if _, err := CardType(42).MarshalText(); err == nil {
+		t.Error("Expected error for an unknown type")
+	}
+}
+
+func TestCardStringAboveNine(t *testing.T) {
+	card := Card{Color: Red, Type: Number, Value: 12}
+	if card.String() != "Red Number 12" {
+		t.Errorf("Expected Red Number 12, got %s", card.String())
+	}
+}
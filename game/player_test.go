@@ -12,8 +12,8 @@ func TestNewPlayer(t *testing.T) {
 		t.Errorf("Expected player name to be %s, got %s", name, player.Name)
 	}
 
-	if len(player.Hand) != 0 {
-		t.Errorf("Expected new player to have empty hand, got %d cards", len(player.Hand))
+	if player.Hand.Len() != 0 {
+		t.Errorf("Expected new player to have empty hand, got %d cards", player.Hand.Len())
 	}
 
 	if player.HasCalledUno {
@@ -31,35 +31,28 @@ func TestNewPlayer(t *testing.T) {
 
 func TestAddCard(t *testing.T) {
 	player := NewPlayer("TestPlayer")
-	card := &Card{Color: Red, Type: Number, Value: 5}
+	card := Card{Color: Red, Type: Number, Value: 5}
 
 	player.AddCard(card)
 
-	if len(player.Hand) != 1 {
-		t.Errorf("Expected hand size to be 1 after adding a card, got %d", len(player.Hand))
+	if player.Hand.Len() != 1 {
+		t.Errorf("Expected hand size to be 1 after adding a card, got %d", player.Hand.Len())
 	}
 
-	if player.Hand[0] != card {
+	if player.Hand.At(0) != card {
 		t.Errorf("Expected the added card to be in player's hand")
 	}
 
-	handSizeBefore := len(player.Hand)
-	player.AddCard(nil)
-
-	if len(player.Hand) != handSizeBefore {
-		t.Errorf("Expected player hand size to remain %d after adding nil card, got %d", handSizeBefore, len(player.Hand))
-	}
-
 	player.CallUno()
 
 	if !player.HasCalledUno {
 		t.Errorf("Expected player to have HasCalledUno = true after calling uno")
 	}
 
-	player.AddCard(&Card{Color: Yellow, Type: Number, Value: 6})
+	player.AddCard(Card{Color: Yellow, Type: Number, Value: 6})
 
-	if len(player.Hand) != 2 {
-		t.Errorf("Expected hand size to be 2 after adding a second card, got %d", len(player.Hand))
+	if player.Hand.Len() != 2 {
+		t.Errorf("Expected hand size to be 2 after adding a second card, got %d", player.Hand.Len())
 	}
 
 	if player.HasCalledUno {
@@ -69,7 +62,7 @@ func TestAddCard(t *testing.T) {
 
 func TestAddCardsToHand(t *testing.T) {
 	player := NewPlayer("TestPlayer")
-	cards := []*Card{
+	cards := []Card{
 		{Color: Red, Type: Number, Value: 5},
 		{Color: Blue, Type: Number, Value: 7},
 		{Color: Green, Type: Skip},
@@ -77,21 +70,21 @@ func TestAddCardsToHand(t *testing.T) {
 
 	player.AddCardsToHand(cards)
 
-	if len(player.Hand) != len(cards) {
-		t.Errorf("Expected hand size to be %d after adding multiple cards, got %d", len(cards), len(player.Hand))
+	if player.Hand.Len() != len(cards) {
+		t.Errorf("Expected hand size to be %d after adding multiple cards, got %d", len(cards), player.Hand.Len())
 	}
 
 	for i, card := range cards {
-		if player.Hand[i] != card {
-			t.Errorf("Expected card at index %d to be %v, got %v", i, card, player.Hand[i])
+		if player.Hand.At(i) != card {
+			t.Errorf("Expected card at index %d to be %v, got %v", i, card, player.Hand.At(i))
 		}
 	}
 }
 
 func TestPlayCard(t *testing.T) {
 	player := NewPlayer("TestPlayer")
-	card1 := &Card{Color: Red, Type: Number, Value: 5}
-	card2 := &Card{Color: Yellow, Type: Number, Value: 6}
+	card1 := Card{Color: Red, Type: Number, Value: 5}
+	card2 := Card{Color: Yellow, Type: Number, Value: 6}
 
 	player.AddCard(card1)
 	player.AddCard(card2)
@@ -106,8 +99,8 @@ func TestPlayCard(t *testing.T) {
 		t.Errorf("Expected to play card1, but got a different card")
 	}
 
-	if len(player.Hand) != 1 {
-		t.Errorf("Expected hand size to be 1 after playing a card, got %d", len(player.Hand))
+	if player.Hand.Len() != 1 {
+		t.Errorf("Expected hand size to be 1 after playing a card, got %d", player.Hand.Len())
 	}
 	
 	_, err = player.PlayCard(5)
@@ -133,12 +126,12 @@ func TestGetValidPlays(t *testing.T) {
 	}
 
 	// Add cards to hand
-	redSeven := &Card{Color: Red, Type: Number, Value: 7}
-	blueFive := &Card{Color: Blue, Type: Number, Value: 5}
-	greenSkip := &Card{Color: Green, Type: Skip}
-	wildCard := &Card{Color: Wild, Type: WildCard}
+	redSeven := Card{Color: Red, Type: Number, Value: 7}
+	blueFive := Card{Color: Blue, Type: Number, Value: 5}
+	greenSkip := Card{Color: Green, Type: Skip}
+	wildCard := Card{Color: Wild, Type: WildCard}
 
-	player.AddCardsToHand([]*Card{redSeven, blueFive, greenSkip, wildCard})
+	player.AddCardsToHand([]Card{redSeven, blueFive, greenSkip, wildCard})
 
 	validPlays = player.GetValidPlays(topCard, currentColor)
 	if len(validPlays) != 3 {
@@ -153,6 +146,44 @@ func TestGetValidPlays(t *testing.T) {
 	}
 }
 
+// Test that the plays found through the kinds of card held agree with IsPlayable
+func TestAppendValidPlays(t *testing.T) {
+	var all []Card
+	for color := Red; color <= Yellow; color++ {
+		for value := 0; value <= 9; value++ {
+			all = append(all, Card{Color: color, Type: Number, Value: value})
+		}
+		for _, cardType := range []CardType{Skip, Reverse, DrawTwo} {
+			all = append(all, Card{Color: color, Type: cardType})
+		}
+	}
+	all = append(all, Card{Color: Wild, Type: WildCard}, Card{Color: Wild, Type: WildDrawFour})
+
+	for _, hand := range [][]Card{all, all[13:26], {all[len(all)-1], all[40]}} {
+		player := NewPlayer("TestPlayer")
+		player.AddCardsToHand(hand)
+		for _, top := range all {
+			for color := Red; color <= Yellow; color++ {
+				var want []int
+				for i, card := range player.Hand.All() {
+					if IsPlayable(card, &player.Hand, top, color) {
+						want = append(want, i)
+					}
+				}
+				got := player.AppendValidPlays(nil, &top, color)
+				if len(got) != len(want) || player.HasValidPlay(&top, color) != (len(want) > 0) {
+					t.Fatalf("Expected %d valid plays on %v with %v active, got %d", len(want), top, color, len(got))
+				}
+				for i := range got {
+					if got[i] != want[i] {
+						t.Fatalf("Expected valid plays %v on %v with %v active, got %v", want, top, color, got)
+					}
+				}
+			}
+		}
+	}
+}
+
 func TestHasValidPlay(t *testing.T) {
 	player := NewPlayer("TestPlayer")
 	topCard := &Card{Color: Red, Type: Number, Value: 5}
@@ -164,16 +195,16 @@ func TestHasValidPlay(t *testing.T) {
 	}
 
 	// Add cards with no valid plays
-	blueSkip := &Card{Color: Blue, Type: Skip}
-	greenSkip := &Card{Color: Green, Type: Skip}
-	player.AddCardsToHand([]*Card{blueSkip, greenSkip})
+	blueSkip := Card{Color: Blue, Type: Skip}
+	greenSkip := Card{Color: Green, Type: Skip}
+	player.AddCardsToHand([]Card{blueSkip, greenSkip})
 
 	if player.HasValidPlay(topCard, currentColor) {
 		t.Errorf("Expected HasValidPlay to be false with no matching cards")
 	}
 
 	// Add a wild card which is always valid
-	wildCard := &Card{Color: Wild, Type: WildCard}
+	wildCard := Card{Color: Wild, Type: WildCard}
 	player.AddCard(wildCard)
 
 	if !player.HasValidPlay(topCard, currentColor) {
@@ -188,19 +219,19 @@ func TestHasWon(t *testing.T) {
 		t.Errorf("Expected new player to have HasWon = false")
 	}
 
-	player.AddCard(&Card{Color: Red, Type: Number, Value: 5})
+	player.AddCard(Card{Color: Red, Type: Number, Value: 5})
 
 	if player.HasWon() {
 		t.Errorf("Expected player with cards to have HasWon = false")
 	}
 
-	player.Hand = []*Card{}
+	player.Hand = NewHand()
 
 	if player.HasWon() {
 		t.Errorf("Expected player with empty hand but who never played any cards to have HasWon = false")
 	}
 
-	player.AddCard(&Card{Color: Yellow, Type: Number, Value: 6})
+	player.AddCard(Card{Color: Yellow, Type: Number, Value: 6})
 	player.PlayCard(0)
 
 	if !player.HasWon() {
@@ -215,8 +246,8 @@ func TestShouldCallUno(t *testing.T) {
 		t.Errorf("Expected new player with empty hands to have ShouldCallUno = false")
 	}
 
-	player.AddCard(&Card{Color: Red, Type: Number, Value: 5})
-	player.AddCard(&Card{Color: Yellow, Type: Number, Value: 6})
+	player.AddCard(Card{Color: Red, Type: Number, Value: 5})
+	player.AddCard(Card{Color: Yellow, Type: Number, Value: 6})
 
 	if player.ShouldCallUno() {
 		t.Errorf("Expected a player who never played any cards to have ShouldCallUno = false")
@@ -228,7 +259,7 @@ func TestShouldCallUno(t *testing.T) {
 		t.Errorf("Expected player who have played cards and has only 1 card in hand to have ShouldCallUno = true")
 	}
 
-	player.AddCard(&Card{Color: Green, Type: Number, Value: 7})
+	player.AddCard(Card{Color: Green, Type: Number, Value: 7})
 
 	if player.ShouldCallUno() {
 		t.Errorf("Expected player with two cards in hand to have ShouldCallUno = false")
@@ -263,7 +294,7 @@ func TestString(t *testing.T) {
 		t.Errorf("Expected string representation to be '%s', got '%s'", expected, player.String())
 	}
 
-	player.AddCard(&Card{Color: Red, Type: Number, Value: 5})
+	player.AddCard(Card{Color: Red, Type: Number, Value: 5})
 	player.CallUno()
 	
 	expected = "Player TestPlayer (1 cards) | Uno(true)"
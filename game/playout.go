@@ -0,0 +1,133 @@
+package game
+
+import (
+	"math/bits"
+	"math/rand/v2"
+)
+
+// Playout plays the game on with random moves until it ends or maxMoves moves were made,
+// and returns the number of moves made. The player whose turn it is calls UNO when they
+// can, otherwise makes one of their plays, each as likely as the others, and draws or
+// passes only when they have none. Nobody challenges
+// It makes the same moves as picking from LegalMoves would, with the same random draws,
+// but works them out from the kinds of card in the hand instead of listing them, which
+// is what makes simulating many games cheap
+func (gr *GameRules) Playout(state *GameState, r *rand.Rand, maxMoves int) (int, error) {
+	moves := 0
+	for ; moves < maxMoves; moves++ {
+		if state.Phase != PhasePlay && state.Phase != PhaseColorSelection {
+			break
+		}
+
+		if err := gr.apply(state, randomMove(state, r)); err != nil {
+			return moves, err
+		}
+	}
+	return moves, nil
+}
+
+// randomMove picks the move Playout makes for the player whose turn it is
+func randomMove(state *GameState, r *rand.Rand) Move {
+	seat := state.CurrentPlayer
+	player := state.Players[seat]
+	if player.ShouldCallUno() && !player.HasCalledUno {
+		return Move{Kind: MoveCallUno, Player: seat}
+	}
+
+	if state.Phase == PhaseColorSelection {
+		move := Move{Kind: MoveChooseColor, Player: seat}
+		topCard, _ := state.DiscardPile.Top()
+		return wildChoice(state, move, topCard.Type == WildSwapHands && state.LastPlayedBy != -1, r.IntN(wildChoices(state, topCard.Type == WildSwapHands && state.LastPlayedBy != -1)))
+	}
+
+	hand := &player.Hand
+	topCard, err := state.DiscardPile.Top()
+	first := 0
+	if err != nil {
+		first = hand.Len()
+	} else if state.HasDrawn {
+		first = hand.Len() - 1
+	}
+	playable := playableKinds(hand, topCard, state.ActiveColor) & hand.kinds
+
+	// Every play of a colored card is one move, a wild card is one move per choice
+	plays := 0
+	if first == 0 {
+		for kinds := playable; kinds != 0; kinds &= kinds - 1 {
+			kind := bits.TrailingZeros64(kinds)
+			plays += int(hand.counts[kind]) * kindChoices(state, kind)
+		}
+	} else if first < hand.Len() && playable&(1<<hand.order[first]) != 0 {
+		plays = kindChoices(state, int(hand.order[first]))
+	}
+
+	if plays == 0 {
+		if canDraw(state) {
+			return Move{Kind: MoveDraw, Player: seat}
+		}
+		return Move{Kind: MovePass, Player: seat}
+	}
+
+	// The plays come in hand order, as LegalMoves lists them. Without a wild card
+	// every play is one move, counted down without a branch per card
+	pick := r.IntN(plays)
+	if playable&colorKinds(Wild) == 0 {
+		for i := first; ; i++ {
+			pick -= int(playable >> hand.order[i] & 1)
+			if pick < 0 {
+				return Move{Kind: MovePlay, Player: seat, CardIndex: i}
+			}
+		}
+	}
+
+	for i := first; ; i++ {
+		kind := int(hand.order[i])
+		if playable&(1<<kind) == 0 {
+			continue
+		}
+
+		choices := kindChoices(state, kind)
+		if pick >= choices {
+			pick -= choices
+			continue
+		}
+
+		move := Move{Kind: MovePlay, Player: seat, CardIndex: i}
+		if card := hand.cards[i]; card.Color == Wild {
+			return wildChoice(state, move, card.Type == WildSwapHands, pick)
+		}
+		return move
+	}
+}
+
+// kindChoices returns the number of moves that play a card of the kind, see appendWildMoves
+func kindChoices(state *GameState, kind int) int {
+	if kind < 4*13 {
+		return 1
+	}
+	return wildChoices(state, kind == KindOf(Card{Color: Wild, Type: WildSwapHands}))
+}
+
+// wildChoices returns the number of ways to resolve a wild card
+func wildChoices(state *GameState, needsTarget bool) int {
+	if needsTarget {
+		return 4 * (len(state.Players) - 1)
+	}
+	return 4
+}
+
+// wildChoice fills in the choice of the move at the index, in the order appendWildMoves lists them
+func wildChoice(state *GameState, move Move, needsTarget bool, index int) Move {
+	if !needsTarget {
+		move.Color = Red + CardColor(index)
+		return move
+	}
+
+	others := len(state.Players) - 1
+	move.Color = Red + CardColor(index/others)
+	move.Target = index % others
+	if move.Target >= move.Player {
+		move.Target++
+	}
+	return move
+}
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// Test that a playout makes the same moves as picking from LegalMoves with the same random numbers
+func TestPlayoutMatchesLegalMoves(t *testing.T) {
+	rules := NewGameRules()
+	for seed := range uint64(200) {
+		for _, opts := range []GameOptions{{}, ModernOptions()} {
+			// Both games shuffle with their own copy of the same source
+			r := rand.New(rand.NewPCG(seed, 1))
+			listed, err := NewGameStateWithRandom([]*Player{NewPlayer("A"), NewPlayer("B")}, opts, r)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			source := rand.New(rand.NewPCG(seed, 1))
+			played, err := NewGameStateWithRandom([]*Player{NewPlayer("A"), NewPlayer("B")}, opts, source)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			expected := 0
+			for ; listed.Phase != PhaseGameOver && expected < 3000; expected++ {
+				if err := rules.Apply(listed, randomLegalMove(LegalMoves(listed, listed.CurrentPlayer), r)); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+			}
+
+			moves, err := rules.Playout(played, source, 3000)
+			if err != nil {
+				t.Fatalf("Seed %d: expected no error, got %v", seed, err)
+			}
+			if moves != expected || played.Hash() != listed.Hash() {
+				t.Errorf("Seed %d: expected the playout to reach the same state after %d moves, it made %d", seed, expected, moves)
+			}
+		}
+	}
+}
+
+// Test that a playout stops after the given number of moves and after the game ends
+func TestPlayoutStops(t *testing.T) {
+	rules := NewGameRules()
+	r := rand.New(rand.NewPCG(1, 2))
+	state, err := NewGameStateWithRandom([]*Player{NewPlayer("A"), NewPlayer("B")}, ModernOptions(), r)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if moves, err := rules.Playout(state, r, 5); err != nil || moves != 5 {
+		t.Errorf("Expected 5 moves, got %d and %v", moves, err)
+	}
+
+	if _, err := rules.Playout(state, r, 10000); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if state.Phase != PhaseGameOver {
+		t.Fatalf("Expected the game to be over, got phase %v", state.Phase)
+	}
+	if moves, err := rules.Playout(state, r, 10000); err != nil || moves != 0 {
+		t.Errorf("Expected no moves after the game ended, got %d and %v", moves, err)
+	}
+}
+
+// BenchmarkPlayout plays the games of BenchmarkLegalGame, see compare_baseline.sh
+func BenchmarkPlayout(b *testing.B) {
+	rules := NewGameRules()
+	r := rand.New(rand.NewPCG(1, 2))
+
+	b.ReportAllocs()
+	moves := 0
+	for b.Loop() {
+		players := []*Player{NewPlayer("Player 1"), NewPlayer("Player 2")}
+		state, err := NewGameStateWithRandom(players, ModernOptions(), r)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		n, err := rules.Playout(state, r, 10000)
+		if err != nil {
+			b.Fatal(err)
+		}
+		moves += n
+	}
+	b.ReportMetric(float64(moves)/float64(b.N), "moves/game")
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Replay records a game so it can be stepped through again: the state it started
+// from, every move made on it and how each shuffle during play came out, which would
+// otherwise depend on the random source
+type Replay struct {
+	Start *GameState
+	Moves []Move
+
+	// Shuffles holds the cards in the order each shuffle left them: the draw pile after
+	// a reshuffle, or every hand in seat order after Wild Shuffle Hands
+	Shuffles [][]Card
+}
+
+// Record starts a replay of the game from its current state
+// The rules must be the ones the game is played with, so shuffles are recorded too
+// Moves are added with Add as they are made
+func Record(state *GameState, rules *GameRules) *Replay {
+	replay := &Replay{Start: state.Clone()}
+	rules.Subscribe(func(event Event) {
+		switch event.Kind {
+		case EventReshuffle:
+			replay.Shuffles = append(replay.Shuffles, append([]Card(nil), state.DrawPile.Cards()...))
+		case EventShuffleHands:
+			var cards []Card
+			for _, player := range state.Players {
+				cards = append(cards, player.Hand.cards...)
+			}
+			replay.Shuffles = append(replay.Shuffles, cards)
+		}
+	})
+	return replay
+}
+
+// Add appends a move that was made on the recorded game
+func (r *Replay) Add(move Move) {
+	r.Moves = append(r.Moves, move)
+}
+
+// Walk plays the recorded moves on a copy of the start, calling visit with the state
+// before each move. The state must not be changed by visit, clone it to play on it
+func (r *Replay) Walk(visit func(i int, state *GameState, move Move) error) error {
+	_, err := r.play(visit)
+	return err
+}
+
+// Final returns the state after every recorded move
+func (r *Replay) Final() (*GameState, error) {
+	return r.play(nil)
+}
+
+// Playback returns a copy of the start to play the recorded moves on, and rules that
+// restore every recorded shuffle as the moves are applied with them
+func (r *Replay) Playback() (*GameState, *GameRules) {
+	// Shuffles are overwritten with the recorded order, the copy gets its own
+	// source so replaying does not draw from the recorded game's
+	state := r.Start.Clone()
+	state.SetRandom(rand.New(rand.NewPCG(0, 0)))
+	rules := NewGameRules()
+
+	shuffles := 0
+	rules.Subscribe(func(event Event) {
+		if (event.Kind != EventReshuffle && event.Kind != EventShuffleHands) || shuffles >= len(r.Shuffles) {
+			return
+		}
+		cards := r.Shuffles[shuffles]
+		shuffles++
+
+		if event.Kind == EventReshuffle {
+			state.DrawPile.Replace(cards)
+			return
+		}
+
+		// The hands were dealt the same sizes, only the cards differ
+		for _, player := range state.Players {
+			size := min(player.Hand.Len(), len(cards))
+			player.Hand.Clear()
+			for _, card := range cards[:size] {
+				player.Hand.Add(card)
+			}
+			cards = cards[size:]
+		}
+	})
+
+	return state, rules
+}
+
+// play replays the moves on a copy of the start, restoring every recorded shuffle
+func (r *Replay) play(visit func(i int, state *GameState, move Move) error) (*GameState, error) {
+	state, rules := r.Playback()
+
+	for i, move := range r.Moves {
+		if visit != nil {
+			if err := visit(i, state, move); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := rules.Apply(state, move); err != nil {
+			return nil, fmt.Errorf("move %d: %v", i+1, err)
+		}
+	}
+	return state, nil
+}
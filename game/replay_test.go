@@ -0,0 +1,79 @@
+package game
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// recordRandomGame plays a game with random legal moves, recording it
+func recordRandomGame(t *testing.T, opts GameOptions, seed uint64) (*GameState, *Replay) {
+	players := []*Player{NewPlayer("Player 1"), NewPlayer("Player 2")}
+	r := rand.New(rand.NewPCG(seed, seed))
+	state, err := NewGameStateWithRandom(players, opts, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := NewGameRules()
+	replay := Record(state, rules)
+	for state.Phase != PhaseGameOver && len(replay.Moves) < 2000 {
+		legal := LegalMoves(state, state.CurrentPlayer)
+		move := legal[r.IntN(len(legal))]
+		if err := rules.Apply(state, move); err != nil {
+			t.Fatal(err)
+		}
+		replay.Add(move)
+	}
+	return state, replay
+}
+
+// findShuffledGame records random games until one shuffles during play
+func findShuffledGame(t *testing.T, opts GameOptions) (*GameState, *Replay) {
+	for seed := uint64(1); seed < 100; seed++ {
+		state, replay := recordRandomGame(t, opts, seed)
+		if len(replay.Shuffles) > 0 {
+			return state, replay
+		}
+	}
+	t.Fatal("Expected some game to shuffle during play")
+	return nil, nil
+}
+
+func TestReplay(t *testing.T) {
+	// Classic games only reshuffle the discard pile, modern ones also shuffle hands
+	for _, opts := range []GameOptions{{}, ModernOptions()} {
+		state, replay := findShuffledGame(t, opts)
+
+		final, err := replay.Final()
+		if err != nil {
+			t.Fatalf("Expected the replay to play back, got %v", err)
+		}
+		if final.Hash() != state.Hash() {
+			t.Errorf("Expected the replay to end in the recorded game's state with %+v", opts)
+		}
+	}
+
+	_, replay := findShuffledGame(t, ModernOptions())
+
+	// Walk shows every move on the state it was made in
+	visited := 0
+	err := replay.Walk(func(i int, state *GameState, move Move) error {
+		if i != visited {
+			t.Errorf("Expected move %d, got %d", visited, i)
+		}
+		if !IsLegalMove(state, move) {
+			t.Errorf("Expected move %d to be legal in its state: %v", i, move)
+		}
+		visited++
+		return nil
+	})
+	if err != nil || visited != len(replay.Moves) {
+		t.Errorf("Expected to visit %d moves, got %d and %v", len(replay.Moves), visited, err)
+	}
+
+	// A move the game never allowed breaks the replay
+	replay.Moves = append([]Move{{Kind: MovePass, Player: 1}}, replay.Moves...)
+	if _, err := replay.Final(); err == nil {
+		t.Error("Expected error for an illegal move")
+	}
+}
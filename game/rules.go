@@ -3,6 +3,7 @@ package game
 import (
 	"errors"
 	"fmt"
+	"math/rand/v2"
 )
 
 type GamePhase int
@@ -14,6 +15,86 @@ const (
 	PhaseGameOver
 )
 
+// CustomRule is the house rule carried by every Wild Customizable card in a game
+type CustomRule int
+
+const (
+	CustomRulePlainWild     CustomRule = iota // Behaves like a regular Wild card
+	CustomRuleDrawTwo                         // Next player draws two cards and loses their turn
+	CustomRuleSkip                            // Next player loses their turn
+	CustomRuleOthersDrawOne                   // Every other player draws one card
+)
+
+func (r CustomRule) String() string {
+	switch r {
+	case CustomRulePlainWild:
+		return "Plain Wild"
+	case CustomRuleDrawTwo:
+		return "Wild Draw Two"
+	case CustomRuleSkip:
+		return "Wild Skip"
+	case CustomRuleOthersDrawOne:
+		return "Everyone Else Draws One"
+	default:
+		return "Unknown"
+	}
+}
+
+var customRuleNames = map[CustomRule]string{
+	CustomRulePlainWild:     "plain-wild",
+	CustomRuleDrawTwo:       "draw-two",
+	CustomRuleSkip:          "skip",
+	CustomRuleOthersDrawOne: "others-draw-one",
+}
+
+// MarshalText writes the rule as a short name like "draw-two" for rule files
+func (r CustomRule) MarshalText() ([]byte, error) {
+	name, ok := customRuleNames[r]
+	if !ok {
+		return nil, fmt.Errorf("unknown custom rule: %d", int(r))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText reads a rule written by MarshalText
+func (r *CustomRule) UnmarshalText(text []byte) error {
+	for rule, name := range customRuleNames {
+		if name == string(text) {
+			*r = rule
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown custom rule: %q", text)
+}
+
+// GameOptions holds the house rules chosen when a game is created
+type GameOptions struct {
+	ShuffleHandsCards int        `json:"shuffleHandsCards"` // Number of Wild Shuffle Hands cards added to the deck
+	SwapHandsCards    int        `json:"swapHandsCards"`    // Number of Wild Swap Hands cards added to the deck
+	CustomizableCards int        `json:"customizableCards"` // Number of Wild Customizable cards added to the deck
+	CustomRule        CustomRule `json:"customRule"`        // House rule written on the Wild Customizable cards
+	Teams             bool       `json:"teams"`             // Four players in two partnerships, partners sit opposite each other
+	PartnersSeeHands  bool       `json:"partnersSeeHands"`  // In team games, partners may look at each other's hands
+}
+
+// ModernOptions returns the options matching the current official 112-card deck
+func ModernOptions() GameOptions {
+	return GameOptions{
+		ShuffleHandsCards: 1,
+		CustomizableCards: 3,
+	}
+}
+
+func (o GameOptions) specialWildCount() int {
+	return o.ShuffleHandsCards + o.SwapHandsCards + o.CustomizableCards
+}
+
+// Choice holds the decisions a wild card asks of the player who played it
+type Choice struct {
+	Color  CardColor // The new active color
+	Target int       // Player index whose hand is taken, only used by Wild Swap Hands
+}
+
 type GameState struct {
 	Players       []*Player
 	CurrentPlayer int
@@ -22,29 +103,84 @@ type GameState struct {
 	ActiveColor   CardColor
 	Phase         GamePhase
 	LastPlayedBy  int
+	Options       GameOptions
+	Teams         []int      // Team index for each player, nil when everyone plays alone
+	Reversed      bool       // Whether play runs counter-clockwise, only matters with more than two players
+	HasDrawn      bool       // Whether the current player has drawn a card this turn
+	random        *rand.Rand // Source for shuffles during play, crypto/rand when nil
 }
 
-type GameRules struct {}
+// SetRandom makes every shuffle during play use the given source instead of crypto/rand
+func (s *GameState) SetRandom(r *rand.Rand) {
+	s.random = r
+}
+
+// shuffle randomizes a deck with the state's random source
+func (s *GameState) shuffle(d *Deck) {
+	if s.random != nil {
+		d.ShuffleWith(s.random)
+	} else {
+		d.Shuffle()
+	}
+}
+
+type GameRules struct {
+	listeners []func(Event)
+	depth     int // Rule operations in progress, see operation
+}
 
 func NewGameRules() *GameRules {
 	return &GameRules{}
 }
 
 func NewGameState(players []*Player) (*GameState, error) {
-	if len(players) != 2 {
+	return NewGameStateWithOptions(players, GameOptions{})
+}
+
+// NewGameStateWithOptions deals a new game using the deck and house rules described by opts
+func NewGameStateWithOptions(players []*Player, opts GameOptions) (*GameState, error) {
+	return NewGameStateWithRandom(players, opts, nil)
+}
+
+// NewGameStateWithRandom creates a game whose deal and every later shuffle come from r,
+// so the same seed always produces the same game. A nil r uses crypto/rand
+func NewGameStateWithRandom(players []*Player, opts GameOptions, r *rand.Rand) (*GameState, error) {
+	if opts.Teams {
+		if len(players) != 4 {
+			return nil, errors.New("four players are required for a team game")
+		}
+	} else if len(players) != 2 {
 		return nil, errors.New("two players are required")
 	}
 
+	if opts.ShuffleHandsCards < 0 || opts.SwapHandsCards < 0 || opts.CustomizableCards < 0 {
+		return nil, errors.New("special wild card counts cannot be negative")
+	}
+
+	if _, ok := customRuleNames[opts.CustomRule]; !ok {
+		return nil, fmt.Errorf("unknown custom rule: %d", int(opts.CustomRule))
+	}
+
 	state := &GameState{
 		Players:       players,
 		CurrentPlayer: 0,
 		Phase:         PhaseSetup,
 		LastPlayedBy:  -1, // Nobody played yet
+		Options:       opts,
+		random:        r,
+	}
+
+	// Players sitting opposite each other are partners
+	if opts.Teams {
+		state.Teams = make([]int, len(players))
+		for i := range players {
+			state.Teams[i] = i % 2
+		}
 	}
 	
 	// Create and shuffle deck
-	deck := NewDeck()
-	deck.Shuffle()
+	deck := NewDeckWithOptions(opts)
+	state.shuffle(deck)
 	
 	// Draw initial hands
 	for _, player := range players {
@@ -52,12 +188,7 @@ func NewGameState(players []*Player) (*GameState, error) {
 		if err != nil {
 			return nil, fmt.Errorf("Failed to deal initial cards %v", err)
 		}
-
-		cardPtrs := make([]*Card, len(cards))
-		for i := range cards {
-			cardPtrs[i] = &cards[i]
-		}
-		player.AddCardsToHand(cardPtrs)
+		player.AddCardsToHand(cards)
 	}
 	
 	// Draw initial card
@@ -67,7 +198,8 @@ func NewGameState(players []*Player) (*GameState, error) {
 	}
 	
 	// Create the discard pile with the initial card as the first card and "put the deck on the draw pile"
-	state.DiscardPile = CreateDiscardPile(initialCard)
+	// The discard pile has room for every card, so plays never have to grow it
+	state.DiscardPile = newDeck(append(make([]Card, 0, deck.Size()+1), initialCard))
 	state.DrawPile = deck
 	
 	// Set the current color based on the initial card
@@ -83,6 +215,10 @@ func NewGameState(players []*Player) (*GameState, error) {
 		case Skip, Reverse:
 			// Skip or Reverse as initial card: First player gets another turn
 			// Nothing to do here as current player is already 0
+			// With more than two players a Reverse still flips the direction of play
+			if initialCard.Type == Reverse && len(state.Players) > 2 {
+				state.Reversed = true
+			}
 		case DrawTwo:
 			// Draw Two as initial card: Second player draws 2 cards and first player takes a turn
 			secondPlayer := (state.CurrentPlayer + 1) % len(state.Players)
@@ -90,13 +226,10 @@ func NewGameState(players []*Player) (*GameState, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to draw cards for initial Draw Two: %v", err)
 			}
-			cardPtrs := make([]*Card, len(cardsDrawn))
-			for i := range cardsDrawn {
-				cardPtrs[i] = &cardsDrawn[i]
-			}
-			state.Players[secondPlayer].AddCardsToHand(cardPtrs)
-		case WildCard:
+			state.Players[secondPlayer].AddCardsToHand(cardsDrawn)
+		case WildCard, WildShuffleHands, WildSwapHands, WildCustomizable:
 			// Wild card as initial card: First player chooses the color
+			// Special wilds only act as regular Wild cards when they start the discard pile
 			// Default to Red until player chooses
 			state.ActiveColor = Red
 			state.Phase = PhaseColorSelection
@@ -107,11 +240,7 @@ func NewGameState(players []*Player) (*GameState, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to draw cards for initial Wild Draw Four: %v", err)
 			}
-			cardPtrs := make([]*Card, len(cardsDrawn))
-			for i := range cardsDrawn {
-				cardPtrs[i] = &cardsDrawn[i]
-			}
-			state.Players[secondPlayer].AddCardsToHand(cardPtrs)
+			state.Players[secondPlayer].AddCardsToHand(cardsDrawn)
 			state.ActiveColor = Red
 			state.Phase = PhaseColorSelection
 		}
@@ -123,6 +252,7 @@ func NewGameState(players []*Player) (*GameState, error) {
 	}
 
 	state.Players[state.CurrentPlayer].IsMyTurn = true
+	debugValidate(state, "the deal")
 
 	return state, nil
 }
@@ -132,7 +262,7 @@ func (gr *GameRules) ValidateMove(player *Player, cardIndex int, state *GameStat
 		return false, "It's not your turn"
 	}
 
-	if cardIndex < 0 || cardIndex >= len(player.Hand) {
+	if cardIndex < 0 || cardIndex >= player.Hand.Len() {
 		return false, "Invalid card index"
 	} 
 
@@ -140,20 +270,25 @@ func (gr *GameRules) ValidateMove(player *Player, cardIndex int, state *GameStat
 		return false, "Game is not in the play phase"
 	}
 
-	card := player.Hand[cardIndex]
+	card := player.Hand.At(cardIndex)
 
-	if len(state.DiscardPile.Cards) == 0 {
+	topCard, err := state.DiscardPile.Top()
+	if err != nil {
 		return false, "Discard pile is empty"
 	}
 
-	topCard := state.DiscardPile.Cards[len(state.DiscardPile.Cards)-1]
+	// After drawing, only the drawn card, which is always the last one in the hand, may be played,
+	// as in the rules of specs.md. Anything else ends the turn with EndTurn
+	if state.HasDrawn && cardIndex != player.Hand.Len()-1 {
+		return false, "Only the card you just drew can be played"
+	}
 
-	if !card.CanPlayOn(topCard, state.ActiveColor) {
+	if !MatchesTop(card, topCard, state.ActiveColor) {
 		return false, "Card cannot be played on top of the current discard pile"
 	}
 
 	if card.Color == Wild && card.Type == WildDrawFour {
-		if !IsWildDrawFourValid(player.Hand, state.ActiveColor) {
+		if !IsWildDrawFourValid(&player.Hand, state.ActiveColor) {
 			return false, "Wild Draw Four can only be played if you don't have any cards of the active color"
 		}
 	}
@@ -162,10 +297,28 @@ func (gr *GameRules) ValidateMove(player *Player, cardIndex int, state *GameStat
 }
 
 func (gr *GameRules) HandleCardEffect(card *Card, state *GameState, chosenColor *CardColor) error {
+	if debugChecks {
+		defer gr.operation(state, "a card effect")()
+	}
+
+	var choice *Choice
+	if chosenColor != nil {
+		choice = &Choice{Color: *chosenColor, Target: gr.nextPlayerIndex(state)}
+	}
+	return gr.handleCardEffect(card, state, choice)
+}
+
+func (gr *GameRules) handleCardEffect(card *Card, state *GameState, choice *Choice) error {
 	if state.Phase != PhasePlay && state.Phase != PhaseColorSelection {
 		return errors.New("game is not in the play or color selection phase")
 	}
 
+	// Every wild card waits for the player's choice before taking effect
+	if card.Color == Wild && choice == nil {
+		state.Phase = PhaseColorSelection
+		return nil
+	}
+
 	switch card.Type {
 	case Number:
 		return gr.handleNumberCard(state)
@@ -176,17 +329,15 @@ func (gr *GameRules) HandleCardEffect(card *Card, state *GameState, chosenColor
 	case DrawTwo:
 		return gr.handleDrawTwoCard(state)
 	case WildCard:
-		if chosenColor == nil {
-			state.Phase = PhaseColorSelection
-			return nil
-		}
-		return gr.handleWildCard(state, *chosenColor)
+		return gr.handleWildCard(state, choice.Color)
 	case WildDrawFour:
-		if chosenColor == nil {
-			state.Phase = PhaseColorSelection
-			return nil
-		}
-		return gr.handleWildDrawFourCard(state, *chosenColor)
+		return gr.handleWildDrawFourCard(state, choice.Color)
+	case WildShuffleHands:
+		return gr.handleShuffleHandsCard(state, choice.Color)
+	case WildSwapHands:
+		return gr.handleSwapHandsCard(state, *choice)
+	case WildCustomizable:
+		return gr.handleCustomizableCard(state, choice.Color)
 	default:
 		return fmt.Errorf("unknown card type: %v", card.Type)
 	}
@@ -209,26 +360,11 @@ func (gr *GameRules) handleReverseCard(state *GameState) error {
 }
 
 func (gr *GameRules) handleDrawTwoCard(state *GameState) error {
-	playerToDraw := (state.CurrentPlayer + 1) % len(state.Players)
+	playerToDraw := gr.nextPlayerIndex(state)
 
-	cardsDrawn, err := state.DrawPile.DrawN(2)
-	if err != nil {
-		if err.Error() == "not enough cards in deck" {
-			// TODO: If there are cards in the discard pile, shuffle them into the draw pile
-			// Keep the top card in the discard pile
-			// Add the cards from the discard pile to the draw pile
-			// Shuffle the draw pile
-			// Try to draw again
-		} else {
-			return fmt.Errorf("failed to draw cards: %v", err)
-		}
-	}
-
-	cardPtrs := make([]*Card, len(cardsDrawn))
-	for i:= range cardPtrs {
-		cardPtrs[i] = &cardsDrawn[i]
+	if err := gr.giveCards(state, playerToDraw, 2); err != nil {
+		return err
 	}
-	state.Players[playerToDraw].AddCardsToHand(cardPtrs)
 	
 	gr.SkipTurn(state)
 	return nil
@@ -252,53 +388,215 @@ func (gr *GameRules) handleWildDrawFourCard(state *GameState, chosenColor CardCo
 
 	state.ActiveColor = chosenColor
 
-	playerToDraw := (state.CurrentPlayer + 1) % len(state.Players)
+	playerToDraw := gr.nextPlayerIndex(state)
 
-	cardsDrawn, err := state.DrawPile.DrawN(4)
-	if err != nil {
-		if err.Error() == "not enough cards in deck" {
-			// TODO: If there are cards in the discard pile, shuffle them into the draw pile
-			// Keep the top card in the discard pile
-			// Add the cards from the discard pile to the draw pile
-			// Shuffle the draw pile
-			// Try to draw again
-		} else {
-			return fmt.Errorf("failed to draw cards: %v", err)
+	if err := gr.giveCards(state, playerToDraw, 4); err != nil {
+		return err
+	}
+
+	gr.SkipTurn(state)
+	return nil
+}
+
+func (gr *GameRules) handleShuffleHandsCard(state *GameState, chosenColor CardColor) error {
+	if chosenColor < Red || chosenColor > Yellow {
+		return errors.New("invalid color choice for Wild Shuffle Hands Card")
+	}
+
+	state.ActiveColor = chosenColor
+
+	// Collect every hand into one pile
+	// A whole deck fits on the stack, bigger custom decks grow onto the heap
+	var buffer [128]Card
+	collected := buffer[:0]
+	for _, player := range state.Players {
+		collected = append(collected, player.Hand.cards...)
+		player.Hand.Clear()
+		player.ResetUnoCall()
+	}
+
+	shuffleHand(collected, state.random)
+
+	// Redeal one card at a time starting from the next player
+	firstPlayer := gr.nextPlayerIndex(state)
+	for i, card := range collected {
+		state.Players[(firstPlayer+i)%len(state.Players)].AddCard(card)
+	}
+
+	gr.emit(Event{Kind: EventShuffleHands, Player: state.CurrentPlayer})
+	gr.NextTurn(state)
+	return nil
+}
+
+func (gr *GameRules) handleSwapHandsCard(state *GameState, choice Choice) error {
+	if choice.Color < Red || choice.Color > Yellow {
+		return errors.New("invalid color choice for Wild Swap Hands Card")
+	}
+
+	if choice.Target < 0 || choice.Target >= len(state.Players) || choice.Target == state.CurrentPlayer {
+		return errors.New("invalid target for Wild Swap Hands Card")
+	}
+
+	state.ActiveColor = choice.Color
+
+	current := state.Players[state.CurrentPlayer]
+	target := state.Players[choice.Target]
+	current.Hand, target.Hand = target.Hand, current.Hand
+	current.ResetUnoCall()
+	target.ResetUnoCall()
+
+	gr.emit(Event{Kind: EventSwapHands, Player: state.CurrentPlayer, Target: choice.Target})
+	gr.NextTurn(state)
+	return nil
+}
+
+func (gr *GameRules) handleCustomizableCard(state *GameState, chosenColor CardColor) error {
+	if chosenColor < Red || chosenColor > Yellow {
+		return errors.New("invalid color choice for Wild Customizable Card")
+	}
+
+	state.ActiveColor = chosenColor
+
+	switch state.Options.CustomRule {
+	case CustomRulePlainWild:
+		gr.NextTurn(state)
+	case CustomRuleDrawTwo:
+		if err := gr.giveCards(state, gr.nextPlayerIndex(state), 2); err != nil {
+			return err
+		}
+		gr.SkipTurn(state)
+	case CustomRuleSkip:
+		gr.SkipTurn(state)
+	case CustomRuleOthersDrawOne:
+		for i := range state.Players {
+			if i == state.CurrentPlayer {
+				continue
+			}
+			if err := gr.giveCards(state, i, 1); err != nil {
+				return err
+			}
 		}
+		gr.NextTurn(state)
+	default:
+		return fmt.Errorf("unknown custom rule: %v", state.Options.CustomRule)
 	}
 
-	cardPtrs := make([]*Card, len(cardsDrawn))
-	for i:= range cardsDrawn {
-		cardPtrs[i] = &cardsDrawn[i]
+	return nil
+}
+
+// giveCards draws n cards from the draw pile into a player's hand,
+// recycling the discard pile when the draw pile runs short
+func (gr *GameRules) giveCards(state *GameState, playerIndex int, n int) error {
+	if state.DrawPile.Size() < n {
+		gr.recycleDiscardPile(state)
 	}
 
-	state.Players[playerToDraw].AddCardsToHand(cardPtrs)
-	gr.SkipTurn(state)
+	// Not enough cards even after reshuffling, hand out what is left
+	if state.DrawPile.Size() < n {
+		n = state.DrawPile.Size()
+	}
+	if n == 0 {
+		return nil
+	}
+
+	player := state.Players[playerIndex]
+	for range n {
+		card, err := state.DrawPile.Draw()
+		if err != nil {
+			return err
+		}
+		player.AddCard(card)
+	}
+
+	gr.emit(Event{Kind: EventPenalty, Player: playerIndex, Count: n})
 	return nil
 }
 
-func (gr *GameRules) NextTurn(state *GameState) {
+// recycleDiscardPile shuffles every discarded card except the top one back into the draw pile
+// Returns false if there was nothing to recycle
+func (gr *GameRules) recycleDiscardPile(state *GameState) bool {
+	if state.DiscardPile.Size() <= 1 {
+		return false
+	}
+
+	// Add the cards from the discard pile to the draw pile, keeping the top card in the discard pile
+	// The shuffle works out the hash of the draw pile afresh
+	discarded := state.DiscardPile.Cards()
+	state.DrawPile.cards = append(state.DrawPile.cards, discarded[:len(discarded)-1]...)
+	state.DiscardPile.Replace(discarded[len(discarded)-1:])
+
+	// Shuffle the draw pile
+	state.shuffle(state.DrawPile)
+	gr.emit(Event{Kind: EventReshuffle, Player: state.CurrentPlayer})
+	return true
+}
+
+// nextPlayerIndex returns the index of the player whose turn comes after the current one
+func (gr *GameRules) nextPlayerIndex(state *GameState) int {
+	return gr.playerAfter(state, state.CurrentPlayer)
+}
+
+// playerAfter returns the index of the player seated after the given one in the direction of play
+func (gr *GameRules) playerAfter(state *GameState, index int) int {
+	if state.Reversed {
+		return (index - 1 + len(state.Players)) % len(state.Players)
+	}
+	return (index + 1) % len(state.Players)
+}
+
+// setCurrentPlayer hands the turn to the player at the given index
+func (gr *GameRules) setCurrentPlayer(state *GameState, index int) {
 	state.Players[state.CurrentPlayer].IsMyTurn = false
-	state.CurrentPlayer = (state.CurrentPlayer + 1) % len(state.Players)
+	state.CurrentPlayer = index
 	state.Players[state.CurrentPlayer].IsMyTurn = true
+	state.HasDrawn = false
+}
+
+func (gr *GameRules) NextTurn(state *GameState) {
+	if debugChecks {
+		defer gr.operation(state, "passing the turn")()
+	}
+
+	gr.setCurrentPlayer(state, gr.nextPlayerIndex(state))
 }
 
 func (gr *GameRules) SkipTurn(state *GameState) {
+	if debugChecks {
+		defer gr.operation(state, "skipping a turn")()
+	}
+
+	// The next player loses their turn
 	// In a two player game, skipping means staying with the same current player
-	// Can be implemented in the future to handle more than two players
+	gr.setCurrentPlayer(state, gr.playerAfter(state, gr.nextPlayerIndex(state)))
 }
 
 func (gr *GameRules) RepeatTurn(state *GameState) {
-	// In a two player game, repeating means staying with the same current player
-	// Can be implemented in the future to handle more than two players
+	if debugChecks {
+		defer gr.operation(state, "repeating a turn")()
+	}
+
+	// Repeating means staying with the same current player, whatever the number of players
 }
 
 func (gr *GameRules) ReverseTurn(state *GameState) {
+	if debugChecks {
+		defer gr.operation(state, "reversing")()
+	}
+
 	// In a two player game, reversing means staying with the same current player
-	// Can be implemented in the future to handle more than two players
+	if len(state.Players) == 2 {
+		return
+	}
+
+	state.Reversed = !state.Reversed
+	gr.NextTurn(state)
 }
 
 func (gr *GameRules) HandleUnoCall(playerIndex int, state *GameState) (bool, string) {
+	if debugChecks {
+		defer gr.operation(state, "an UNO call")()
+	}
+
 	if playerIndex < 0 || playerIndex >= len(state.Players) {
 		return false, "Invalid player index"
 	}
@@ -310,11 +608,16 @@ func (gr *GameRules) HandleUnoCall(playerIndex int, state *GameState) (bool, str
 	}
 
 	player.CallUno()
+	gr.emit(Event{Kind: EventCallUno, Player: playerIndex})
 	return true, "UNO called successfully"
 }
 
 func (gr *GameRules) HandleUnoChallenge(targetIndex int, state *GameState) (bool, string) {
-	if targetIndex < 0 || targetIndex > len(state.Players) {
+	if debugChecks {
+		defer gr.operation(state, "an UNO challenge")()
+	}
+
+	if targetIndex < 0 || targetIndex >= len(state.Players) {
 		return false, "Invalid target index"
 	}
 
@@ -328,61 +631,84 @@ func (gr *GameRules) HandleUnoChallenge(targetIndex int, state *GameState) (bool
 		return false, "Target allready called uno"
 	}
 
-	cardsDrawn, err := state.DrawPile.DrawN(2)
-	if err != nil {
-		if err.Error() == "not enough cards in deck" {
-			// TODO: If there are cards in the discard pile, shuffle them into the draw pile
-			// Keep the top card in the discard pile
-			// Add the cards from the discard pile to the draw pile
-			// Shuffle the draw pile
-			// Try to draw again
-		} else {
-			return false, fmt.Sprintf("failed to draw cards: %v", err) 
-		}
-	}
-
-	cardPtrs := make([]*Card, len(cardsDrawn))
-	for i := range cardsDrawn {
-		cardPtrs[i] = &cardsDrawn[i]
+	if err := gr.giveCards(state, targetIndex, 2); err != nil {
+		return false, err.Error()
 	}
 
-	target.AddCardsToHand(cardPtrs)
-
 	return true, "Challenge successfull! Target has drawn 2 cards"
 }
 
 func (gr *GameRules) HandlePlayCard(player *Player, cardIndex int, state *GameState, chosenColor *CardColor) error {
+	if debugChecks {
+		defer gr.operation(state, "playing a card")()
+	}
+
+	var choice *Choice
+	if chosenColor != nil {
+		choice = &Choice{Color: *chosenColor, Target: gr.nextPlayerIndex(state)}
+	}
+	return gr.HandlePlayCardWithChoice(player, cardIndex, state, choice)
+}
+
+// HandlePlayCardWithChoice plays a card like HandlePlayCard, taking both the color
+// and the target player for wild cards that need one
+func (gr *GameRules) HandlePlayCardWithChoice(player *Player, cardIndex int, state *GameState, choice *Choice) error {
+	if debugChecks {
+		defer gr.operation(state, "playing a card")()
+	}
+
 	valid, message := gr.ValidateMove(player, cardIndex, state)
 	if(!valid) {
 		return errors.New(message)
 	}
+	return gr.playCard(player, cardIndex, state, choice)
+}
 
-	card, err := player.PlayCard(cardIndex)
-	if err != nil {
-		return fmt.Errorf("failed to play card: %v", err)
-	}
-
-	state.DiscardPile.AddToBottom(*card)
+// playCard carries out a play that was already validated
+func (gr *GameRules) playCard(player *Player, cardIndex int, state *GameState, choice *Choice) error {
+	// The index was validated, so the card is taken straight out of the hand
+	card := player.Hand.Remove(cardIndex)
+	player.hasPlayedCard = true
+	state.DiscardPile.AddToTop(card)
 
 	state.LastPlayedBy = state.CurrentPlayer
+	state.HasDrawn = false
 
-	if chosenColor != nil || card.Color != Wild {
-		err = gr.HandleCardEffect(card, state, chosenColor)
-		if err != nil {
+	played := Event{Kind: EventPlay, Player: state.CurrentPlayer, Card: card}
+	if choice != nil && card.Color == Wild {
+		played.Color = choice.Color
+	}
+	gr.emit(played)
+
+	// Hands are not exchanged once the round is over
+	if player.HasWon() && (card.Type == WildShuffleHands || card.Type == WildSwapHands) {
+		state.Phase = PhaseGameOver
+		gr.emit(Event{Kind: EventGameOver, Player: state.LastPlayedBy})
+		return nil
+	}
+
+	if choice != nil || card.Color != Wild {
+		if err := gr.handleCardEffect(&card, state, choice); err != nil {
 			return fmt.Errorf("failed to handle card effect: %v", err)
 		}
 	} else {
 		state.Phase = PhaseColorSelection
 	}
 
-	if player.HasWon() {
+	// In team games the round ends as soon as either partner goes out
+	if winner := state.Winner(); winner != -1 {
 		state.Phase = PhaseGameOver
+		gr.emit(Event{Kind: EventGameOver, Player: winner})
 	}
 
 	return nil
 }
 
 func (gr *GameRules) HandleDrawCard(player *Player, state *GameState) error {
+	if debugChecks {
+		defer gr.operation(state, "drawing a card")()
+	}
+
 	if !player.IsMyTurn {
 		return errors.New("it is not your turn")
 	}
@@ -391,45 +717,102 @@ func (gr *GameRules) HandleDrawCard(player *Player, state *GameState) error {
 		return errors.New("game is not in the play phase")
 	}
 
+	// One draw per turn, after which the player plays the drawn card or passes
+	if state.HasDrawn {
+		return errors.New("you have already drawn a card this turn")
+	}
+
+	return gr.drawCard(player, state)
+}
+
+// drawCard gives the player the top card of the draw pile, as a draw they were allowed to make
+func (gr *GameRules) drawCard(player *Player, state *GameState) error {
+	// If the draw pile is empty, shuffle the discard pile back into it
+	if state.DrawPile.IsEmpty() && !gr.recycleDiscardPile(state) {
+		// Not enough cards even after reshuffling
+		return errors.New("no more cards to draw")
+	}
+
 	card, err := state.DrawPile.Draw()
 	if err != nil {
-		// Handle the case where there aren't enough cards in the draw pile
-		if err.Error() == "cannot draw from an empty deck" {
-			// If there are cards in the discard pile, shuffle them into the draw pile
-			if len(state.DiscardPile.Cards) > 1 { // Keep the top card in the discard pile
-				topCard := state.DiscardPile.Cards[len(state.DiscardPile.Cards)-1]
-				state.DiscardPile.Cards = state.DiscardPile.Cards[:len(state.DiscardPile.Cards)-1]
-
-				// Add the cards from the discard pile to the draw pile
-				state.DrawPile.Cards = append(state.DrawPile.Cards, state.DiscardPile.Cards...)
-				state.DiscardPile.Cards = []Card{topCard}
-
-				// Shuffle the draw pile
-				state.DrawPile.Shuffle()
-
-				// Try to draw again
-				card, err = state.DrawPile.Draw()
-				if err != nil {
-					return fmt.Errorf("failed to draw card after reshuffling: %v", err)
-				}
-			} else {
-				// Not enough cards even after reshuffling
-				return errors.New("no more cards to draw")
-			}
-		} else {
-			return fmt.Errorf("failed to draw card: %v", err)
-		}
+		return fmt.Errorf("failed to draw card: %v", err)
+	}
+
+	player.AddCard(card)
+	state.HasDrawn = true
+	gr.emit(Event{Kind: EventDraw, Player: state.CurrentPlayer, Count: 1})
+	return nil
+}
+
+// HandleColorSelection resolves the wild card waiting on top of the discard pile
+// with the color, and for Wild Swap Hands the target, picked by the current player
+func (gr *GameRules) HandleColorSelection(player *Player, state *GameState, choice Choice) error {
+	if debugChecks {
+		defer gr.operation(state, "choosing a color")()
+	}
+
+	if !player.IsMyTurn {
+		return errors.New("it is not your turn")
+	}
+
+	if state.Phase != PhaseColorSelection {
+		return errors.New("game is not in the color selection phase")
+	}
+
+	if choice.Color < Red || choice.Color > Yellow {
+		return errors.New("invalid color choice")
+	}
+
+	topCard, err := state.DiscardPile.Top()
+	if err != nil {
+		return fmt.Errorf("failed to read the discard pile: %v", err)
+	}
+
+	chosen := Event{Kind: EventChooseColor, Player: state.CurrentPlayer, Color: choice.Color}
+
+	// A wild starting card only sets the color, the first player then takes their turn
+	if state.LastPlayedBy == -1 {
+		state.ActiveColor = choice.Color
+		state.Phase = PhasePlay
+		gr.emit(chosen)
+		return nil
+	}
+
+	if topCard.Type == WildSwapHands && (choice.Target < 0 || choice.Target >= len(state.Players) || choice.Target == state.CurrentPlayer) {
+		return errors.New("invalid target for Wild Swap Hands Card")
+	}
+
+	// The choice is announced before the effects it sets off
+	previousColor := state.ActiveColor
+	state.ActiveColor = choice.Color
+	gr.emit(chosen)
+
+	state.Phase = PhasePlay
+	if err := gr.handleCardEffect(&topCard, state, &choice); err != nil {
+		// The game keeps waiting for a choice, as if none was made
+		state.ActiveColor = previousColor
+		state.Phase = PhaseColorSelection
+		return fmt.Errorf("failed to handle card effect: %v", err)
 	}
 
-	player.AddCard(&card)
 	return nil
 }
 
 func (gr *GameRules) EndTurn(state *GameState) error {
+	if debugChecks {
+		defer gr.operation(state, "ending a turn")()
+	}
+
 	if state.Phase != PhasePlay {
 		return errors.New("game phase is not play phase")
 	}
 
-	gr.NextTurn(state)
+	gr.endTurn(state)
 	return nil
 }
+
+// endTurn passes the turn on, as a pass the current player was allowed to make
+func (gr *GameRules) endTurn(state *GameState) {
+	gr.emit(Event{Kind: EventPass, Player: state.CurrentPlayer})
+	gr.setCurrentPlayer(state, gr.nextPlayerIndex(state))
+}
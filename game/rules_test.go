@@ -1,6 +1,7 @@
 package game
 
 import (
+	"math/rand/v2"
 	"testing"
 )
 
@@ -91,13 +92,13 @@ func TestValidateMove(t *testing.T) {
 	state := createTestGameState()
 	
 	// Add cards to player 1's hand
-	redSeven := &Card{Color: Red, Type: Number, Value: 7}
-	blueFive := &Card{Color: Blue, Type: Number, Value: 5}
-	blueSkip := &Card{Color: Blue, Type: Skip}
-	wildCard := &Card{Color: Wild, Type: WildCard}
-	wildDrawFour := &Card{Color: Wild, Type: WildDrawFour}
+	redSeven := Card{Color: Red, Type: Number, Value: 7}
+	blueFive := Card{Color: Blue, Type: Number, Value: 5}
+	blueSkip := Card{Color: Blue, Type: Skip}
+	wildCard := Card{Color: Wild, Type: WildCard}
+	wildDrawFour := Card{Color: Wild, Type: WildDrawFour}
 	
-	state.Players[0].AddCardsToHand([]*Card{redSeven, blueFive, blueSkip, wildCard, wildDrawFour})
+	state.Players[0].AddCardsToHand([]Card{redSeven, blueFive, blueSkip, wildCard, wildDrawFour})
 	
 	// Test valid moves
 	valid, _ := rules.ValidateMove(state.Players[0], 0, state) // Red 7 on Red 5
@@ -465,7 +466,7 @@ func TestHandleUnoCall(t *testing.T) {
 	}
 	
 	// Add cards to player's hand
-	redSeven := &Card{Color: Red, Type: Number, Value: 7}
+	redSeven := Card{Color: Red, Type: Number, Value: 7}
 	state.Players[0].AddCard(redSeven)
 	
 	// Test calling UNO with more than one card
@@ -476,7 +477,7 @@ func TestHandleUnoCall(t *testing.T) {
 	
 	// Remove cards from hand to have one left and make player play at least one card
 	state.Players[0].hasPlayedCard = true
-	state.Players[0].Hand = []*Card{redSeven}
+	state.Players[0].Hand = NewHand(redSeven)
 	
 	// Test valid UNO call
 	success, _ = rules.HandleUnoCall(0, state)
@@ -502,7 +503,7 @@ func TestHandleUnoChallenge(t *testing.T) {
 	}
 	
 	// Add cards to player's hand
-	redSeven := &Card{Color: Red, Type: Number, Value: 7}
+	redSeven := Card{Color: Red, Type: Number, Value: 7}
 	state.Players[0].AddCard(redSeven)
 	
 	// Test challenging when player has more than one card
@@ -513,7 +514,7 @@ func TestHandleUnoChallenge(t *testing.T) {
 	
 	// Remove cards from hand to have one left and make player play at least one card
 	state.Players[0].hasPlayedCard = true
-	state.Players[0].Hand = []*Card{redSeven}
+	state.Players[0].Hand = NewHand(redSeven)
 	
 	// Test challenging a player with 1 card but who called UNO
 	state.Players[0].CallUno()
@@ -545,11 +546,11 @@ func TestHandlePlayCard(t *testing.T) {
 	state := createTestGameState()
 	
 	// Add cards to player 1's hand
-	redSeven := &Card{Color: Red, Type: Number, Value: 7}
-	redSkip := &Card{Color: Red, Type: Skip}
-	wildCard := &Card{Color: Wild, Type: WildCard}
+	redSeven := Card{Color: Red, Type: Number, Value: 7}
+	redSkip := Card{Color: Red, Type: Skip}
+	wildCard := Card{Color: Wild, Type: WildCard}
 	
-	state.Players[0].AddCardsToHand([]*Card{redSeven, redSkip, wildCard})
+	state.Players[0].AddCardsToHand([]Card{redSeven, redSkip, wildCard})
 	
 	// Test playing a number card
 	initialHandSize := state.Players[0].HandSize()
@@ -622,6 +623,43 @@ func TestHandlePlayCard(t *testing.T) {
 	}
 }
 
+// Test that every played card lands on top of the discard pile, above the starting card
+func TestPlayedCardsGoOnTop(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestGameState()
+
+	redSkip := Card{Color: Red, Type: Skip}
+	redTwo := Card{Color: Red, Type: Number, Value: 2}
+	blueTwo := Card{Color: Blue, Type: Number, Value: 2}
+	state.Players[0].AddCardsToHand([]Card{redSkip, redTwo, blueTwo})
+
+	// The Skip keeps the turn, so the same player plays on
+	if err := rules.HandlePlayCard(state.Players[0], 0, state, nil); err != nil {
+		t.Fatalf("Expected no error playing the Skip, got %v", err)
+	}
+	// Playing moves the last card of the hand into the gap, so the red 2 is now last
+	if err := rules.HandlePlayCard(state.Players[0], 1, state, nil); err != nil {
+		t.Fatalf("Expected no error playing the red 2, got %v", err)
+	}
+
+	expected := []Card{{Color: Red, Type: Number, Value: 5}, redSkip, redTwo}
+	if len(state.DiscardPile.Cards()) != len(expected) {
+		t.Fatalf("Expected %d cards on the discard pile, got %v", len(expected), state.DiscardPile.Cards())
+	}
+	for i, card := range expected {
+		if state.DiscardPile.Cards()[i] != card {
+			t.Errorf("Expected the discard pile to be %v from the bottom, got %v", expected, state.DiscardPile.Cards())
+			break
+		}
+	}
+
+	// A card is matched against the last one played, not the starting card
+	state.CurrentPlayer, state.Players[0].IsMyTurn, state.Players[1].IsMyTurn = 0, true, false
+	if valid, _ := rules.ValidateMove(state.Players[0], 0, state); !valid {
+		t.Error("Expected the blue 2 to be playable on the red 2")
+	}
+}
+
 // Test HandleDrawCard
 func TestHandleDrawCard(t *testing.T) {
 	rules := NewGameRules()
@@ -705,8 +743,8 @@ func TestWinningCondition(t *testing.T) {
 	state := createTestGameState()
 	
 	// Set up a scenario where player has one card
-	redSeven := &Card{Color: Red, Type: Number, Value: 7}
-	state.Players[0].Hand = []*Card{redSeven}
+	redSeven := Card{Color: Red, Type: Number, Value: 7}
+	state.Players[0].Hand = NewHand(redSeven)
 	state.Players[0].hasPlayedCard = true // Simulate having played cards before
 	
 	// Play the final card
@@ -777,3 +815,306 @@ func TestRecycleDiscardPile(t *testing.T) {
 		t.Errorf("Expected draw pile to have %d cards, got %d", expectedDrawPileSize, state.DrawPile.Size())
 	}
 }
+
+// Test handling Wild Shuffle Hands card effect
+func TestHandleShuffleHandsCardEffect(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestGameState()
+
+	state.Players[0].AddCardsToHand([]Card{
+		{Color: Red, Type: Number, Value: 1},
+		{Color: Red, Type: Number, Value: 2},
+	})
+	state.Players[1].AddCardsToHand([]Card{
+		{Color: Blue, Type: Number, Value: 3},
+		{Color: Blue, Type: Number, Value: 4},
+		{Color: Blue, Type: Number, Value: 5},
+	})
+
+	err := rules.handleShuffleHandsCard(state, Green)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// Five cards are redealt starting from the next player, so they get the extra card
+	if state.Players[1].HandSize() != 3 {
+		t.Errorf("Expected next player to have 3 cards, got %d", state.Players[1].HandSize())
+	}
+
+	if state.Players[0].HandSize() != 2 {
+		t.Errorf("Expected current player to have 2 cards, got %d", state.Players[0].HandSize())
+	}
+
+	if state.ActiveColor != Green {
+		t.Errorf("Expected active color to be Green, got %v", state.ActiveColor)
+	}
+
+	if state.CurrentPlayer != 1 {
+		t.Errorf("Expected current player to be 1, got %d", state.CurrentPlayer)
+	}
+
+	// Test invalid color
+	state = createTestGameState()
+	if err := rules.handleShuffleHandsCard(state, Wild); err == nil {
+		t.Error("Expected error when choosing invalid color for Wild Shuffle Hands card")
+	}
+}
+
+// Test handling Wild Swap Hands card effect
+func TestHandleSwapHandsCardEffect(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestGameState()
+
+	redOne := Card{Color: Red, Type: Number, Value: 1}
+	blueTwo := Card{Color: Blue, Type: Number, Value: 2}
+	blueThree := Card{Color: Blue, Type: Number, Value: 3}
+	state.Players[0].AddCard(redOne)
+	state.Players[1].AddCardsToHand([]Card{blueTwo, blueThree})
+
+	err := rules.handleSwapHandsCard(state, Choice{Color: Yellow, Target: 1})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if state.Players[0].HandSize() != 2 || state.Players[0].Hand.At(0) != blueTwo {
+		t.Error("Expected current player to receive the target's hand")
+	}
+
+	if state.Players[1].HandSize() != 1 || state.Players[1].Hand.At(0) != redOne {
+		t.Error("Expected target to receive the current player's hand")
+	}
+
+	if state.ActiveColor != Yellow {
+		t.Errorf("Expected active color to be Yellow, got %v", state.ActiveColor)
+	}
+
+	if state.CurrentPlayer != 1 {
+		t.Errorf("Expected current player to be 1, got %d", state.CurrentPlayer)
+	}
+
+	// Test swapping with yourself
+	state = createTestGameState()
+	if err := rules.handleSwapHandsCard(state, Choice{Color: Red, Target: 0}); err == nil {
+		t.Error("Expected error when swapping hands with yourself")
+	}
+
+	// Test invalid target index
+	if err := rules.handleSwapHandsCard(state, Choice{Color: Red, Target: 2}); err == nil {
+		t.Error("Expected error when swapping hands with an invalid target")
+	}
+}
+
+// Test handling Wild Customizable card effect for each house rule
+func TestHandleCustomizableCardEffect(t *testing.T) {
+	rules := NewGameRules()
+
+	tests := []struct {
+		rule             CustomRule
+		expectedPlayer   int
+		expectedHandSize int
+	}{
+		{CustomRulePlainWild, 1, 0},
+		{CustomRuleDrawTwo, 0, 2},
+		{CustomRuleSkip, 0, 0},
+		{CustomRuleOthersDrawOne, 1, 1},
+	}
+
+	for _, tt := range tests {
+		state := createTestGameState()
+		state.Options.CustomRule = tt.rule
+
+		err := rules.handleCustomizableCard(state, Blue)
+		if err != nil {
+			t.Errorf("%v: Expected no error, got %v", tt.rule, err)
+		}
+
+		if state.ActiveColor != Blue {
+			t.Errorf("%v: Expected active color to be Blue, got %v", tt.rule, state.ActiveColor)
+		}
+
+		if state.CurrentPlayer != tt.expectedPlayer {
+			t.Errorf("%v: Expected current player to be %d, got %d", tt.rule, tt.expectedPlayer, state.CurrentPlayer)
+		}
+
+		if state.Players[1].HandSize() != tt.expectedHandSize {
+			t.Errorf("%v: Expected other player to have %d cards, got %d", tt.rule, tt.expectedHandSize, state.Players[1].HandSize())
+		}
+	}
+}
+
+// Test resolving a pending wild card through the color selection phase
+func TestHandleColorSelection(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestGameState()
+	state.Options.SwapHandsCards = 1
+
+	redOne := Card{Color: Red, Type: Number, Value: 1}
+	swapHands := Card{Color: Wild, Type: WildSwapHands}
+	state.Players[0].AddCardsToHand([]Card{redOne, swapHands})
+	state.Players[1].AddCard(Card{Color: Blue, Type: Number, Value: 2})
+
+	// Selecting a color outside the color selection phase fails
+	err := rules.HandleColorSelection(state.Players[0], state, Choice{Color: Blue, Target: 1})
+	if err == nil {
+		t.Error("Expected error when selecting a color during the play phase")
+	}
+
+	err = rules.HandlePlayCardWithChoice(state.Players[0], 1, state, nil)
+	if err != nil {
+		t.Fatalf("Expected no error when playing Wild Swap Hands, got %v", err)
+	}
+
+	if state.Phase != PhaseColorSelection {
+		t.Fatalf("Expected phase to be ColorSelection, got %d", state.Phase)
+	}
+
+	// The played card is on top of the discard pile
+	top, _ := state.DiscardPile.Top()
+	if top.Type != WildSwapHands {
+		t.Errorf("Expected Wild Swap Hands on top of the discard pile, got %v", top)
+	}
+
+	// Invalid target keeps the game waiting for a choice
+	err = rules.HandleColorSelection(state.Players[0], state, Choice{Color: Blue, Target: 0})
+	if err == nil {
+		t.Error("Expected error when targeting yourself")
+	}
+
+	if state.Phase != PhaseColorSelection {
+		t.Errorf("Expected phase to stay ColorSelection after an invalid choice, got %d", state.Phase)
+	}
+
+	err = rules.HandleColorSelection(state.Players[0], state, Choice{Color: Blue, Target: 1})
+	if err != nil {
+		t.Fatalf("Expected no error when selecting a color, got %v", err)
+	}
+
+	if state.Phase != PhasePlay {
+		t.Errorf("Expected phase to be Play, got %d", state.Phase)
+	}
+
+	if state.ActiveColor != Blue {
+		t.Errorf("Expected active color to be Blue, got %v", state.ActiveColor)
+	}
+
+	if state.Players[1].HandSize() != 1 || state.Players[1].Hand.At(0) != redOne {
+		t.Error("Expected hands to be swapped after the color selection")
+	}
+
+	// A wild starting card only sets the color
+	state = createTestGameState()
+	state.DiscardPile = CreateDiscardPile(Card{Color: Wild, Type: WildCard})
+	state.Phase = PhaseColorSelection
+
+	err = rules.HandleColorSelection(state.Players[0], state, Choice{Color: Green})
+	if err != nil {
+		t.Errorf("Expected no error when choosing the starting color, got %v", err)
+	}
+
+	if state.CurrentPlayer != 0 || state.ActiveColor != Green || state.Phase != PhasePlay {
+		t.Error("Expected the first player to keep the turn with the chosen starting color")
+	}
+}
+
+// Test that a choice the card's effect rejects leaves the color as it was
+func TestHandleColorSelectionFailure(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestGameState()
+	state.Options.CustomRule = CustomRule(99)
+	state.DiscardPile.AddToTop(Card{Color: Wild, Type: WildCustomizable})
+	state.LastPlayedBy = 0
+	state.Phase = PhaseColorSelection
+
+	err := rules.HandleColorSelection(state.Players[0], state, Choice{Color: Blue})
+	if err == nil {
+		t.Fatal("Expected error for an unknown custom rule")
+	}
+
+	if state.ActiveColor != Red || state.Phase != PhaseColorSelection {
+		t.Errorf("Expected Red and the color selection phase kept, got %v and %d", state.ActiveColor, state.Phase)
+	}
+}
+
+// Test that exchanging hands with the last card still wins the game
+func TestSpecialWildAsLastCard(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestGameState()
+
+	state.Players[0].Hand = NewHand(Card{Color: Wild, Type: WildShuffleHands})
+	state.Players[0].hasPlayedCard = true
+	state.Players[1].AddCard(Card{Color: Blue, Type: Number, Value: 2})
+
+	chosenColor := Red
+	err := rules.HandlePlayCard(state.Players[0], 0, state, &chosenColor)
+	if err != nil {
+		t.Errorf("Expected no error when playing final card, got %v", err)
+	}
+
+	if state.Phase != PhaseGameOver {
+		t.Errorf("Expected game phase to be GameOver, got %d", state.Phase)
+	}
+
+	if state.Players[0].HandSize() != 0 {
+		t.Errorf("Expected winner's hand to stay empty, got %d cards", state.Players[0].HandSize())
+	}
+}
+
+// Test that the same seed deals the same game
+func TestNewGameStateWithRandom(t *testing.T) {
+	deal := func() *GameState {
+		players := []*Player{NewPlayer("Player 1"), NewPlayer("Player 2")}
+		state, err := NewGameStateWithRandom(players, ModernOptions(), rand.New(rand.NewPCG(7, 7)))
+		if err != nil {
+			t.Fatalf("Expected no error creating game state, got %v", err)
+		}
+		return state
+	}
+
+	first, second := deal(), deal()
+	for i := range first.Players {
+		for j, card := range first.Players[i].Hand.All() {
+			if card != second.Players[i].Hand.At(j) {
+				t.Errorf("Expected player %d card %d to be %v, got %v", i, j, card, second.Players[i].Hand.At(j))
+			}
+		}
+	}
+
+	for i, card := range first.DrawPile.Cards() {
+		if card != second.DrawPile.Cards()[i] {
+			t.Errorf("Expected draw pile card %d to be %v, got %v", i, card, second.DrawPile.Cards()[i])
+			break
+		}
+	}
+}
+
+// Test that a game cannot be created with a custom rule the rules do not know
+func TestNewGameStateUnknownCustomRule(t *testing.T) {
+	for _, rule := range []CustomRule{-1, CustomRuleOthersDrawOne + 1} {
+		players := []*Player{NewPlayer("Player 1"), NewPlayer("Player 2")}
+		opts := GameOptions{CustomizableCards: 4, CustomRule: rule}
+		if _, err := NewGameStateWithRandom(players, opts, rand.New(rand.NewPCG(1, 2))); err == nil {
+			t.Errorf("Expected an error for custom rule %d", int(rule))
+		}
+	}
+}
+
+// Test that custom rules round-trip through their text names
+func TestCustomRuleText(t *testing.T) {
+	for _, rule := range []CustomRule{CustomRulePlainWild, CustomRuleDrawTwo, CustomRuleSkip, CustomRuleOthersDrawOne} {
+		text, err := rule.MarshalText()
+		if err != nil {
+			t.Errorf("Expected no error marshaling %v, got %v", rule, err)
+			continue
+		}
+
+		var parsed CustomRule
+		if err := parsed.UnmarshalText(text); err != nil || parsed != rule {
+			t.Errorf("Expected %q to parse back to %v, got %v (%v)", text, rule, parsed, err)
+		}
+	}
+
+	var rule CustomRule
+	if err := rule.UnmarshalText([]byte("draw-ten")); err == nil {
+		t.Errorf("Expected an error for an unknown rule name")
+	}
+}
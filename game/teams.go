@@ -0,0 +1,63 @@
+package game
+
+// TeamOf returns the team index of the player at the given index
+// When everyone plays alone each player is their own team
+func (s *GameState) TeamOf(playerIndex int) int {
+	if s.Teams == nil {
+		return playerIndex
+	}
+	return s.Teams[playerIndex]
+}
+
+// AreTeammates checks if two players play for the same team
+func (s *GameState) AreTeammates(a, b int) bool {
+	return s.TeamOf(a) == s.TeamOf(b)
+}
+
+// CanSeeHand checks if the viewer is allowed to look at the owner's hand
+func (s *GameState) CanSeeHand(viewer, owner int) bool {
+	if viewer == owner {
+		return true
+	}
+	return s.Teams != nil && s.Options.PartnersSeeHands && s.AreTeammates(viewer, owner)
+}
+
+// Winner returns the index of the player who went out, or -1 if nobody has
+func (s *GameState) Winner() int {
+	for i, player := range s.Players {
+		if player.HasWon() {
+			return i
+		}
+	}
+	return -1
+}
+
+// WinningTeam returns the team of the player who went out, or -1 if the round is still going
+func (s *GameState) WinningTeam() int {
+	winner := s.Winner()
+	if winner == -1 {
+		return -1
+	}
+	return s.TeamOf(winner)
+}
+
+// ScoreRound returns the winning team and the points it scores from the cards
+// left in the opponents' hands. Partners' cards never count against their own team
+func (gr *GameRules) ScoreRound(state *GameState) (int, int) {
+	team := state.WinningTeam()
+	if team == -1 {
+		return -1, 0
+	}
+
+	points := 0
+	for i, player := range state.Players {
+		if state.TeamOf(i) == team {
+			continue
+		}
+		for _, card := range player.Hand.cards {
+			points += card.Points()
+		}
+	}
+
+	return team, points
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"testing"
+)
+
+// Helper function to create a four player team game state for testing
+func createTestTeamGameState() *GameState {
+	state := createTestGameState()
+	state.Players = append(state.Players, NewPlayer("Player 3"), NewPlayer("Player 4"))
+	state.Teams = []int{0, 1, 0, 1}
+	state.Options.Teams = true
+
+	return state
+}
+
+// Test NewGameStateWithOptions for team games
+func TestNewTeamGameState(t *testing.T) {
+	players := []*Player{NewPlayer("North"), NewPlayer("East"), NewPlayer("South"), NewPlayer("West")}
+
+	state, err := NewGameStateWithOptions(players, GameOptions{Teams: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i, player := range players {
+		if player.HandSize() < 7 {
+			t.Errorf("Expected player %d to have at least 7 cards, got %d", i, player.HandSize())
+		}
+	}
+
+	// Players sitting opposite each other are partners
+	if !state.AreTeammates(0, 2) || !state.AreTeammates(1, 3) {
+		t.Error("Expected opposite players to be partners")
+	}
+
+	if state.AreTeammates(0, 1) {
+		t.Error("Expected neighbouring players to be opponents")
+	}
+
+	// Team games need exactly four players
+	_, err = NewGameStateWithOptions([]*Player{NewPlayer("A"), NewPlayer("B")}, GameOptions{Teams: true})
+	if err == nil {
+		t.Error("Expected error when starting a team game with 2 players")
+	}
+}
+
+// Test turn progression with four players
+func TestTurnOrderWithFourPlayers(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestTeamGameState()
+
+	rules.NextTurn(state)
+	if state.CurrentPlayer != 1 {
+		t.Errorf("Expected current player to be 1, got %d", state.CurrentPlayer)
+	}
+
+	// Skip jumps over the next player
+	rules.SkipTurn(state)
+	if state.CurrentPlayer != 3 {
+		t.Errorf("Expected current player to be 3 after a skip, got %d", state.CurrentPlayer)
+	}
+
+	// Reverse flips the direction and passes the turn back
+	rules.ReverseTurn(state)
+	if state.CurrentPlayer != 2 {
+		t.Errorf("Expected current player to be 2 after a reverse, got %d", state.CurrentPlayer)
+	}
+
+	if !state.Reversed {
+		t.Error("Expected play to run counter-clockwise after a reverse")
+	}
+
+	// Draw Two hits the next player in the new direction and skips them
+	initialHandSize := state.Players[1].HandSize()
+	if err := rules.handleDrawTwoCard(state); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if state.Players[1].HandSize() != initialHandSize+2 {
+		t.Errorf("Expected player 1 to draw 2 cards, got %d", state.Players[1].HandSize()-initialHandSize)
+	}
+
+	if state.CurrentPlayer != 0 {
+		t.Errorf("Expected current player to be 0, got %d", state.CurrentPlayer)
+	}
+
+	turns := 0
+	for _, player := range state.Players {
+		if player.IsMyTurn {
+			turns++
+		}
+	}
+	if turns != 1 || !state.Players[0].IsMyTurn {
+		t.Error("Expected only the current player's turn flag to be true")
+	}
+}
+
+// Test that a partner going out ends the round for the team
+func TestTeamWinCondition(t *testing.T) {
+	rules := NewGameRules()
+	state := createTestTeamGameState()
+
+	state.Players[0].Hand = NewHand(Card{Color: Red, Type: Number, Value: 7})
+	state.Players[0].hasPlayedCard = true
+	state.Players[1].Hand = NewHand(Card{Color: Blue, Type: Skip}, Card{Color: Wild, Type: WildCard})
+	state.Players[2].Hand = NewHand(Card{Color: Green, Type: Number, Value: 9})
+	state.Players[3].Hand = NewHand(Card{Color: Yellow, Type: Number, Value: 3})
+
+	err := rules.HandlePlayCard(state.Players[0], 0, state, nil)
+	if err != nil {
+		t.Fatalf("Expected no error when playing final card, got %v", err)
+	}
+
+	if state.Phase != PhaseGameOver {
+		t.Errorf("Expected game phase to be GameOver, got %d", state.Phase)
+	}
+
+	if state.WinningTeam() != 0 {
+		t.Errorf("Expected team 0 to win, got %d", state.WinningTeam())
+	}
+
+	// The partner's Green 9 does not count, the opponents hold 20 + 50 + 3
+	team, points := rules.ScoreRound(state)
+	if team != 0 || points != 73 {
+		t.Errorf("Expected team 0 to score 73 points, got team %d with %d", team, points)
+	}
+}
+
+// Test hand visibility between partners
+func TestCanSeeHand(t *testing.T) {
+	state := createTestTeamGameState()
+
+	if !state.CanSeeHand(1, 1) {
+		t.Error("Expected players to see their own hand")
+	}
+
+	if state.CanSeeHand(0, 2) {
+		t.Error("Expected partners' hands to be hidden by default")
+	}
+
+	state.Options.PartnersSeeHands = true
+
+	if !state.CanSeeHand(0, 2) {
+		t.Error("Expected partners to see each other's hands")
+	}
+
+	if state.CanSeeHand(0, 1) {
+		t.Error("Expected opponents' hands to stay hidden")
+	}
+
+	// Without teams nobody else's hand is visible
+	solo := createTestGameState()
+	solo.Options.PartnersSeeHands = true
+	if solo.CanSeeHand(0, 1) {
+		t.Error("Expected opponent's hand to be hidden in a two player game")
+	}
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate checks the state for internal consistency: the whole deck is in play,
+// exactly the current player has the turn, the active color and the phase agree
+// with the top card, and the counts and hashes kept by hands and piles match their
+// cards. Every problem found is returned
+func (s *GameState) Validate() error {
+	if len(s.Players) == 0 || s.DrawPile == nil || s.DiscardPile == nil {
+		return errors.New("the game has no players or piles")
+	}
+
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	// Every card of the deck is in exactly one place
+	counts := make(map[Card]int)
+	for _, card := range NewDeckWithOptions(s.Options).Cards() {
+		counts[card]++
+	}
+	for i, player := range s.Players {
+		for _, card := range player.Hand.cards {
+			counts[card]--
+		}
+		if err := player.Hand.check(); err != nil {
+			fail("player %d: %v", i, err)
+		}
+	}
+	for _, card := range s.DrawPile.Cards() {
+		counts[card]--
+	}
+	for _, card := range s.DiscardPile.Cards() {
+		counts[card]--
+	}
+	if err := s.DrawPile.check(); err != nil {
+		fail("draw pile: %v", err)
+	}
+	if err := s.DiscardPile.check(); err != nil {
+		fail("discard pile: %v", err)
+	}
+	for card, count := range counts {
+		switch {
+		case count > 0:
+			fail("%d %v missing from the game", count, card)
+		case count < 0:
+			fail("%d %v more than the deck holds", -count, card)
+		}
+	}
+
+	if s.CurrentPlayer < 0 || s.CurrentPlayer >= len(s.Players) {
+		fail("current player %d is not at the table", s.CurrentPlayer)
+	}
+	for i, player := range s.Players {
+		if player.IsMyTurn != (i == s.CurrentPlayer) {
+			fail("player %d has IsMyTurn %t but the current player is %d", i, player.IsMyTurn, s.CurrentPlayer)
+		}
+	}
+
+	if s.LastPlayedBy < -1 || s.LastPlayedBy >= len(s.Players) {
+		fail("last player %d is not at the table", s.LastPlayedBy)
+	}
+	if s.Teams != nil && len(s.Teams) != len(s.Players) {
+		fail("%d teams for %d players", len(s.Teams), len(s.Players))
+	}
+
+	if s.ActiveColor < Red || s.ActiveColor > Wild {
+		fail("unknown active color %d", s.ActiveColor)
+	}
+
+	top, err := s.DiscardPile.Top()
+	winner := s.Winner()
+	switch s.Phase {
+	case PhasePlay, PhaseColorSelection:
+		if err != nil {
+			fail("the discard pile is empty during play")
+			break
+		}
+		if s.ActiveColor == Wild {
+			fail("the active color is wild during play")
+		}
+		if s.Phase == PhaseColorSelection && top.Color != Wild {
+			fail("choosing a color for %v, which is not a wild card", top)
+		}
+		if winner != -1 {
+			fail("player %d has won but the game goes on", winner)
+		}
+	case PhaseGameOver:
+		if winner == -1 {
+			fail("the game is over but nobody has won")
+		}
+	case PhaseSetup:
+	default:
+		fail("unknown phase %d", s.Phase)
+	}
+
+	if s.HasDrawn && s.CurrentPlayer >= 0 && s.CurrentPlayer < len(s.Players) && s.Players[s.CurrentPlayer].Hand.Len() == 0 {
+		fail("the current player drew a card but holds none")
+	}
+
+	return errors.Join(errs...)
+}
+
+// operation brackets a rule operation, call the returned function when it is done
+// In debug builds the state is validated once the outermost operation returns, as long
+// as it was consistent when that operation began. Rules must keep a consistent state
+// consistent, but many tests start them from hand-made states that are not
+func (gr *GameRules) operation(state *GameState, name any) func() {
+	if !debugChecks {
+		return func() {}
+	}
+
+	gr.depth++
+	if gr.depth > 1 {
+		return func() { gr.depth-- }
+	}
+
+	consistent := state.Validate() == nil
+	return func() {
+		gr.depth--
+		if consistent {
+			debugValidate(state, name)
+		}
+	}
+}
+
+// debugValidate panics if the state is inconsistent in debug builds, and does nothing otherwise
+func debugValidate(state *GameState, after any) {
+	if !debugChecks {
+		return
+	}
+	if err := state.Validate(); err != nil {
+		panic(fmt.Sprintf("invalid game state after %v: %v", after, err))
+	}
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestValidateRandomGames(t *testing.T) {
+	for _, opts := range []GameOptions{{}, ModernOptions(), {Teams: true, SwapHandsCards: 2}} {
+		players := []*Player{NewPlayer("A"), NewPlayer("B")}
+		if opts.Teams {
+			players = append(players, NewPlayer("C"), NewPlayer("D"))
+		}
+
+		r := rand.New(rand.NewPCG(1, 2))
+		state, err := NewGameStateWithRandom(players, opts, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Every move of a real game keeps the state consistent
+		rules := NewGameRules()
+		for moves := 0; state.Phase != PhaseGameOver && moves < 2000; moves++ {
+			if err := state.Validate(); err != nil {
+				t.Fatalf("Expected a consistent state after %d moves with %+v, got %v", moves, opts, err)
+			}
+
+			legal := LegalMoves(state, state.CurrentPlayer)
+			if err := rules.Apply(state, legal[r.IntN(len(legal))]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := state.Validate(); err != nil {
+			t.Errorf("Expected a consistent final state with %+v, got %v", opts, err)
+		}
+	}
+}
+
+// Test that debug builds check the state after rule methods called outside Apply too
+func TestDebugValidate(t *testing.T) {
+	if !debugChecks {
+		t.Skip("build with -tags debug to validate after rule operations")
+	}
+
+	players := []*Player{NewPlayer("A"), NewPlayer("B")}
+	state, err := NewGameStateWithRandom(players, GameOptions{}, rand.New(rand.NewPCG(3, 4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Phase = PhasePlay
+	if err := state.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A listener breaking the state in the middle of a draw is caught when the draw returns
+	rules := NewGameRules()
+	rules.Subscribe(func(event Event) {
+		if event.Kind == EventDraw {
+			state.ActiveColor = Wild
+		}
+	})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for the state left inconsistent by a draw")
+		}
+		if rules.depth != 0 {
+			t.Errorf("Expected no rule operation left in progress, got %d", rules.depth)
+		}
+	}()
+	rules.HandleDrawCard(state.Players[state.CurrentPlayer], state)
+}
+
+func TestValidate(t *testing.T) {
+	build := func() *GameState {
+		state, err := NewBuilder("Alice", "Bob").Hand(0, "R7 B2").Hand(1, "G3 W").Discard("R5").Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return state
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		corrupt func(s *GameState)
+	}{
+		{"lost card", "missing", func(s *GameState) { s.DrawPile.Draw() }},
+		{"extra card", "more than the deck holds", func(s *GameState) { s.DrawPile.AddToTop(Card{Color: Wild, Type: WildCard}) }},
+		{"stale hash", "do not match its cards", func(s *GameState) { s.Players[1].Hand.cards[0] = s.Players[0].Hand.At(0) }},
+		{"two turns", "IsMyTurn", func(s *GameState) { s.Players[1].IsMyTurn = true }},
+		{"turn mismatch", "IsMyTurn", func(s *GameState) { s.CurrentPlayer = 1 }},
+		{"wild color", "wild during play", func(s *GameState) { s.ActiveColor = Wild }},
+		{"negative color", "unknown active color", func(s *GameState) { s.ActiveColor = Red - 1 }},
+		{"color past wild", "unknown active color", func(s *GameState) { s.ActiveColor = Wild + 1 }},
+		{"no wild to choose for", "not a wild card", func(s *GameState) { s.Phase = PhaseColorSelection }},
+		{"over without winner", "nobody has won", func(s *GameState) { s.Phase = PhaseGameOver }},
+		{"last player", "not at the table", func(s *GameState) { s.LastPlayedBy = 5 }},
+		{"unknown phase", "unknown phase", func(s *GameState) { s.Phase = 9 }},
+	}
+
+	if err := build().Validate(); err != nil {
+		t.Fatalf("Expected a built state to be valid, got %v", err)
+	}
+
+	for _, tt := range tests {
+		state := build()
+		tt.corrupt(state)
+
+		err := state.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("Expected an error about %q for %s, got %v", tt.message, tt.name, err)
+		}
+	}
+
+	// Every problem is reported, not just the first
+	state := build()
+	state.ActiveColor = Wild
+	state.Players[1].IsMyTurn = true
+	if err := state.Validate(); err == nil || strings.Count(err.Error(), "\n") != 1 {
+		t.Errorf("Expected two problems, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package game
+
+// PlayerView is everything one player is allowed to know about the game
+// Hidden hands are left out so the view can be handed to bots or sent over the network
+type PlayerView struct {
+	Seat          int
+	Hands         [][]Card // Cards of every hand the player may look at, nil for hidden hands
+	HandSizes     []int
+	CalledUno     []bool
+	HasPlayed     []bool // Whether each player has played at least one card
+	TopCard       Card
+	DiscardPile   []Card // The discard pile is public, the top card is the last one
+	DrawPileSize  int
+	ActiveColor   CardColor
+	Phase         GamePhase
+	CurrentPlayer int
+	LastPlayedBy  int
+	Reversed      bool
+	HasDrawn      bool
+	Teams         []int
+	Options       GameOptions
+	LegalMoves    []Move
+}
+
+// View builds the redacted view of the game for the player at the given seat
+// The view owns all its slices, changes to the game never show up in it
+func (s *GameState) View(seat int) PlayerView {
+	n := len(s.Players)
+	discarded := s.DiscardPile.Size()
+
+	// Share one array between the per-player numbers and one between the visible cards
+	numbers := make([]int, n+len(s.Teams))
+	flags := make([]bool, 2*n)
+	visible := discarded
+	for i, player := range s.Players {
+		if s.CanSeeHand(seat, i) {
+			visible += player.Hand.Len()
+		}
+	}
+	cards := make([]Card, 0, visible)
+	cards = append(cards, s.DiscardPile.Cards()...)
+
+	view := PlayerView{
+		Seat:          seat,
+		Hands:         make([][]Card, n),
+		HandSizes:     numbers[:n:n],
+		CalledUno:     flags[:n:n],
+		HasPlayed:     flags[n:],
+		DiscardPile:   cards[:discarded:discarded],
+		DrawPileSize:  s.DrawPile.Size(),
+		ActiveColor:   s.ActiveColor,
+		Phase:         s.Phase,
+		CurrentPlayer: s.CurrentPlayer,
+		LastPlayedBy:  s.LastPlayedBy,
+		Reversed:      s.Reversed,
+		HasDrawn:      s.HasDrawn,
+		Options:       s.Options,
+		LegalMoves:    LegalMoves(s, seat),
+	}
+
+	if s.Teams != nil {
+		view.Teams = numbers[n:]
+		copy(view.Teams, s.Teams)
+	}
+
+	if top, err := s.DiscardPile.Top(); err == nil {
+		view.TopCard = top
+	}
+
+	for i, player := range s.Players {
+		view.HandSizes[i] = player.Hand.Len()
+		view.CalledUno[i] = player.HasCalledUno
+		view.HasPlayed[i] = player.hasPlayedCard
+
+		if s.CanSeeHand(seat, i) {
+			start := len(cards)
+			cards = append(cards, player.Hand.cards...)
+			view.Hands[i] = cards[start:len(cards):len(cards)]
+		}
+	}
+
+	return view
+}
+
+// MyHand returns the viewing player's own hand
+func (v PlayerView) MyHand() []Card {
+	return v.Hands[v.Seat]
+}
+
+// IsMyTurn checks if the viewing player is the one to act
+func (v PlayerView) IsMyTurn() bool {
+	return v.CurrentPlayer == v.Seat
+}
+
+// Opponents returns the seats of every player not on the viewer's team
+func (v PlayerView) Opponents() []int {
+	opponents := make([]int, 0, len(v.HandSizes))
+	for i := range v.HandSizes {
+		if i == v.Seat || (v.Teams != nil && v.Teams[i] == v.Teams[v.Seat]) {
+			continue
+		}
+		opponents = append(opponents, i)
+	}
+	return opponents
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"testing"
+)
+
+// Test that a view hides the opponent's hand
+func TestView(t *testing.T) {
+	state := createTestGameState()
+	state.Players[0].AddCardsToHand([]Card{{Color: Red, Type: Number, Value: 7}, {Color: Wild, Type: WildCard}})
+	state.Players[1].AddCardsToHand([]Card{{Color: Blue, Type: Skip}})
+
+	view := state.View(0)
+
+	if len(view.MyHand()) != 2 || view.MyHand()[0].Value != 7 {
+		t.Errorf("Expected to see own hand, got %v", view.MyHand())
+	}
+
+	if view.Hands[1] != nil {
+		t.Error("Expected the opponent's hand to be hidden")
+	}
+
+	if view.HandSizes[1] != 1 {
+		t.Errorf("Expected opponent hand size 1, got %d", view.HandSizes[1])
+	}
+
+	if view.TopCard != (Card{Color: Red, Type: Number, Value: 5}) {
+		t.Errorf("Expected top card Red 5, got %v", view.TopCard)
+	}
+
+	if view.DrawPileSize != state.DrawPile.Size() {
+		t.Errorf("Expected draw pile size %d, got %d", state.DrawPile.Size(), view.DrawPileSize)
+	}
+
+	if !view.IsMyTurn() || len(view.LegalMoves) == 0 {
+		t.Error("Expected the view to carry the legal moves for the current turn")
+	}
+
+	// Changing the view must not touch the game
+	view.MyHand()[0].Value = 1
+	view.DiscardPile[0].Value = 1
+	if state.Players[0].Hand.At(0).Value != 7 || state.DiscardPile.Cards()[0].Value != 5 {
+		t.Error("Expected the view to hold copies of the cards")
+	}
+}
+
+// Test that partners can see each other's hands when the team rule allows it
+func TestTeamView(t *testing.T) {
+	state := createTestTeamGameState()
+	state.Options.PartnersSeeHands = true
+	state.Players[2].AddCard(Card{Color: Green, Type: Number, Value: 2})
+
+	view := state.View(0)
+
+	if len(view.Hands[2]) != 1 {
+		t.Error("Expected to see the partner's hand")
+	}
+
+	if view.Hands[1] != nil || view.Hands[3] != nil {
+		t.Error("Expected opponents' hands to stay hidden")
+	}
+
+	opponents := view.Opponents()
+	if len(opponents) != 2 || opponents[0] != 1 || opponents[1] != 3 {
+		t.Errorf("Expected opponents to be seats 1 and 3, got %v", opponents)
+	}
+}
@@ -0,0 +1,621 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/vtigo/uno-clone/anim"
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Pacing of the table, in ticks
+const (
+	botDelay      = TPS     // Between bot moves, so people can follow them and call UNO in time
+	messageTicks  = 3 * TPS // How long a message stays up
+	gameOverTicks = 2 * TPS // Before the results show
+)
+
+// Layout of the table
+const (
+	handY        = ScreenHeight - CardHeight - 40 // Top of the hand's middle cards
+	handArc      = 24                             // How much higher the middle of the hand sits than its ends
+	handMaxWidth = ScreenWidth - 480              // The hand overlaps its cards to stay this narrow
+	hoverLift    = 16                             // How far the card under the cursor rises
+	pilesY       = ScreenHeight/2 - CardHeight/2 - 40
+	wheelRadius  = 40 // Of each color on the color wheel
+	wheelSpread  = 56 // From the middle of the wheel to the middle of each color
+)
+
+var (
+	drawPileRect    = cardRect(image.Pt(ScreenWidth/2-CardWidth-40, pilesY))
+	discardPileRect = cardRect(image.Pt(ScreenWidth/2+40, pilesY))
+	wheelCenter     = image.Pt(ScreenWidth/2, ScreenHeight/2-40)
+	shadeColor      = color.RGBA{A: 0x99}
+)
+
+// wheelColors are the colors on the color wheel, clockwise from the top
+var wheelColors = []game.CardColor{game.Red, game.Blue, game.Green, game.Yellow}
+
+// gameplayScreen is the table, where the player plays a bot
+type gameplayScreen struct {
+	m     *ScreenManager
+	seat  int // The player's seat, the bot has the other
+	names []string
+
+	state *game.GameState
+	rules *game.GameRules
+	table *bot.Table
+
+	ticks    int          // Ticks since the game started, for the time played
+	botWait  int          // Ticks before the bot may move
+	thought  *bot.Thought // The bot's move while it is still choosing
+	overWait int          // Ticks the game has been over
+	hover    int          // Index of the card under the cursor, -1 for none
+
+	// pending is the hand index of a wild card waiting for its color, -1 for none
+	pending int
+	// unoArmed calls UNO as soon as the next play leaves one card
+	unoArmed bool
+
+	message     string // Feedback on the player's actions
+	messageWait int
+	log         string // What happened last at the table
+	leave       *button
+	layout      []*button
+
+	// The table as far as the animations have got, behind the state while cards move
+	anims    anim.Timeline
+	flights  []*flight
+	effects  []*effect
+	top      game.Card // Card shown on the discard pile
+	topShown bool
+	incoming []int        // Cards on their way to each hand, not shown there yet
+	origin   image.Point  // Where the card the player is playing sets off from
+	queued   *image.Point // A click made while the table was busy, handled once it is not
+
+	legal []game.Move // The player's legal moves, worked out again every frame
+	plays []bool      // Which cards of the hand the legal moves play
+}
+
+func newGameplayScreen(m *ScreenManager) Screen {
+	s := &gameplayScreen{m: m}
+	s.leave = &button{label: "Leave Game", onClick: func() error { s.finish(); return nil }}
+	s.layout = []*button{
+		{label: "UNO!", onClick: func() error { return s.callUno() }},
+		{label: "Challenge", onClick: func() error { return s.challenge() }},
+		{label: "End Turn", onClick: func() error { return s.pass() }},
+		s.leave,
+	}
+
+	// Buttons stack on the right of the table
+	x := ScreenWidth - buttonWidth - 40
+	for i, b := range s.layout {
+		y := ScreenHeight/2 - 80 + i*(buttonHeight+buttonGap)
+		b.rect = image.Rect(x, y, x+buttonWidth, y+buttonHeight)
+	}
+	return s
+}
+
+func (s *gameplayScreen) buttons() []*button { return s.layout }
+func (s *gameplayScreen) OnExit()            {}
+
+// OnEnter deals a new game against the bot picked in the settings
+func (s *gameplayScreen) OnEnter() {
+	seed := s.m.Seed
+	s.m.Seed++
+
+	b, err := bot.ByName(s.m.Settings.Opponent, seed)
+	if err != nil {
+		b = bot.NewGreedyBot()
+	}
+	players := []*game.Player{game.NewPlayer(s.m.Settings.PlayerName), game.NewPlayer(b.Name())}
+	state, err := game.NewGameStateWithRandom(players, game.GameOptions{}, rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)))
+	if err != nil {
+		// Two players always make a valid game
+		panic(err)
+	}
+	s.start(state, b)
+}
+
+// start sits the player at seat 0 of the state and the bot at seat 1
+func (s *gameplayScreen) start(state *game.GameState, b bot.Bot) {
+	s.seat = 0
+	s.state = state
+	s.rules = game.NewGameRules()
+	s.rules.Subscribe(s.observe)
+	s.table, _ = bot.NewTable(state, s.rules, []bot.Bot{nil, b})
+
+	s.names = make([]string, len(state.Players))
+	for i, player := range state.Players {
+		s.names[i] = player.Name
+	}
+
+	s.ticks, s.botWait, s.overWait = 0, botDelay, 0
+	s.thought = nil
+	s.hover, s.pending = -1, -1
+	s.unoArmed = false
+	s.message, s.log = "", ""
+
+	s.anims = anim.Timeline{}
+	s.flights, s.effects, s.queued = nil, nil, nil
+	s.animateDeal()
+}
+
+// observe writes the events of the game to the log line and animates them
+func (s *gameplayScreen) observe(event game.Event) {
+	s.animate(event)
+	if sound := s.soundOf(event); sound != "" {
+		s.m.playSound(sound)
+	}
+
+	name := s.names[event.Player]
+	switch event.Kind {
+	case game.EventPlay:
+		s.log = name + " played " + cardName(event.Card)
+		if event.Card.Color == game.Wild {
+			s.log += " and chose " + event.Color.String()
+		}
+	case game.EventDraw:
+		s.log = name + " drew a card"
+	case game.EventPenalty:
+		s.log = fmt.Sprintf("%s took %d cards", name, event.Count)
+	case game.EventPass:
+		s.log = name + " ended their turn"
+	case game.EventChooseColor:
+		s.log = name + " chose " + event.Color.String()
+	case game.EventCallUno:
+		s.log = name + " called UNO!"
+	case game.EventSwapHands:
+		s.log = name + " swapped hands with " + s.names[event.Target]
+	case game.EventShuffleHands:
+		s.log = name + " shuffled every hand"
+	case game.EventReshuffle:
+		s.log = "The discard pile was shuffled into the draw pile"
+	case game.EventGameOver:
+		s.log = name + " went out!"
+	}
+}
+
+// soundOf returns the sound effect of an event, empty for events without one
+func (s *gameplayScreen) soundOf(event game.Event) string {
+	switch event.Kind {
+	case game.EventPlay:
+		if event.Card.Type == game.Number {
+			return "play"
+		}
+		return "special"
+	case game.EventDraw, game.EventPenalty:
+		return "draw"
+	case game.EventCallUno:
+		return "uno"
+	case game.EventGameOver:
+		if event.Player == s.seat {
+			return "win"
+		}
+	}
+	return ""
+}
+
+// cardName names a card for the log
+func cardName(card game.Card) string {
+	if card.Color == game.Wild {
+		return card.Type.String()
+	}
+	if card.Type == game.Number {
+		return fmt.Sprintf("%v %d", card.Color, card.Value)
+	}
+	return card.Color.String() + " " + card.Type.String()
+}
+
+func (s *gameplayScreen) player() *game.Player {
+	return s.state.Players[s.seat]
+}
+
+func (s *gameplayScreen) opponent() int {
+	return (s.seat + 1) % len(s.state.Players)
+}
+
+func (s *gameplayScreen) Update(in Input) error {
+	s.ticks++
+	if s.messageWait > 0 {
+		s.messageWait--
+		if s.messageWait == 0 {
+			s.message = ""
+		}
+	}
+
+	s.anims.Update()
+
+	if in.KeyPressed(ebiten.KeyEscape) {
+		if s.pending >= 0 {
+			s.pending = -1
+			return nil
+		}
+		s.finish()
+		return nil
+	}
+
+	if s.state.Phase == game.PhaseGameOver {
+		if !s.anims.Blocking() {
+			s.overWait++
+		}
+		if s.overWait >= gameOverTicks {
+			s.finish()
+		}
+		return s.leave.update(in)
+	}
+
+	if err := s.updateBot(); err != nil {
+		return err
+	}
+
+	// The table waits for cards to land, a click meanwhile is handled after
+	if s.anims.Blocking() {
+		s.hover = -1
+		if in.Clicked() && !in.Cursor().In(s.leave.rect) {
+			p := in.Cursor()
+			s.queued = &p
+		}
+		return s.leave.update(in)
+	}
+	if s.queued != nil {
+		in = queuedClick(*s.queued)
+		s.queued = nil
+	}
+
+	s.hover = s.cardAt(in.Cursor())
+	if in.Clicked() {
+		if err := s.click(in.Cursor()); err != nil {
+			return err
+		}
+	}
+	return updateButtons(in, s.layout)
+}
+
+// updateBot lets the bot make a move once it has waited and the table is still
+// The bot chooses in the background, a search would otherwise freeze the window
+func (s *gameplayScreen) updateBot() error {
+	if s.thought != nil {
+		done, err := s.table.Poll(s.thought)
+		if done {
+			s.thought, s.botWait = nil, botDelay
+		}
+		return err
+	}
+	if s.botWait > 0 {
+		s.botWait--
+		return nil
+	}
+	if s.anims.Blocking() {
+		return nil
+	}
+
+	s.thought = s.table.Think()
+	return nil
+}
+
+// click handles a click on the table, buttons are handled on their own
+func (s *gameplayScreen) click(p image.Point) error {
+	if s.choosingColor() {
+		if color, ok := wheelColorAt(p); ok {
+			return s.chooseColor(color)
+		}
+		// Clicking away from the wheel puts back a wild card not played yet
+		s.pending = -1
+		return nil
+	}
+
+	switch {
+	case s.hover >= 0:
+		return s.playCard(s.hover)
+	case p.In(drawPileRect):
+		return s.draw()
+	}
+	return nil
+}
+
+// choosingColor reports whether the color wheel is up, for a wild card being played
+// or a wild card that started the game
+func (s *gameplayScreen) choosingColor() bool {
+	if s.pending >= 0 {
+		return true
+	}
+	return s.state.Phase == game.PhaseColorSelection && s.state.CurrentPlayer == s.seat
+}
+
+// playCard plays the card from the hand, asking for a color first for wild cards
+func (s *gameplayScreen) playCard(index int) error {
+	if valid, message := s.rules.ValidateMove(s.player(), index, s.state); !valid {
+		s.refuse(message)
+		return nil
+	}
+
+	if s.player().Hand.At(index).Color == game.Wild {
+		s.pending = index
+		return nil
+	}
+	s.origin = handLayout(s.player().Hand.Len())[index]
+	return s.apply(game.Move{Kind: game.MovePlay, Player: s.seat, CardIndex: index})
+}
+
+// chooseColor finishes playing the pending wild card, or resolves the starting one
+func (s *gameplayScreen) chooseColor(color game.CardColor) error {
+	move := game.Move{Kind: game.MoveChooseColor, Player: s.seat, Color: color, Target: s.opponent()}
+	if s.pending >= 0 {
+		move.Kind = game.MovePlay
+		move.CardIndex = s.pending
+		s.origin = handLayout(s.player().Hand.Len())[s.pending]
+		s.pending = -1
+	}
+	return s.apply(move)
+}
+
+func (s *gameplayScreen) draw() error {
+	return s.apply(game.Move{Kind: game.MoveDraw, Player: s.seat})
+}
+
+func (s *gameplayScreen) pass() error {
+	if s.state.CurrentPlayer == s.seat && !s.state.HasDrawn {
+		s.refuse("Draw a card before ending your turn")
+		return nil
+	}
+	return s.apply(game.Move{Kind: game.MovePass, Player: s.seat})
+}
+
+// callUno calls UNO, or with two cards in hand, calls it along with the next play
+func (s *gameplayScreen) callUno() error {
+	player := s.player()
+	if !player.ShouldCallUno() && player.Hand.Len() == 2 && s.state.CurrentPlayer == s.seat {
+		s.unoArmed = true
+		s.say("UNO will be called as you play your next card")
+		return nil
+	}
+	return s.apply(game.Move{Kind: game.MoveCallUno, Player: s.seat})
+}
+
+func (s *gameplayScreen) challenge() error {
+	return s.apply(game.Move{Kind: game.MoveChallenge, Player: s.seat, Target: s.opponent()})
+}
+
+// apply makes the player's move, telling them why if the rules refuse it
+// Errors other than illegal moves mean the game broke and end it
+func (s *gameplayScreen) apply(move game.Move) error {
+	err := s.rules.Apply(s.state, move)
+	var illegal *game.IllegalMoveError
+	if errors.As(err, &illegal) {
+		s.refuse(sentence(illegal.Reason.Error()))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.message = ""
+	s.botWait = botDelay
+	if move.Kind == game.MovePlay && s.unoArmed {
+		s.unoArmed = false
+		if player := s.player(); player.ShouldCallUno() && !player.HasCalledUno {
+			return s.apply(game.Move{Kind: game.MoveCallUno, Player: s.seat})
+		}
+	}
+	return nil
+}
+
+// say shows a message to the player for a while
+func (s *gameplayScreen) say(message string) {
+	s.message = message
+	s.messageWait = messageTicks
+}
+
+// refuse tells the player why their action is not allowed
+func (s *gameplayScreen) refuse(message string) {
+	s.say(message)
+	s.m.playSound("invalid")
+}
+
+// sentence capitalizes a message from the rules
+func sentence(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+// finish records the game and shows the results
+func (s *gameplayScreen) finish() {
+	left := make([]int, len(s.state.Players))
+	for i, player := range s.state.Players {
+		left[i] = player.Hand.Len()
+	}
+
+	winner := -1
+	if s.state.Phase == game.PhaseGameOver {
+		winner = s.state.Winner()
+	}
+	s.m.Result = &GameResult{
+		Names:     s.names,
+		Winner:    winner,
+		CardsLeft: left,
+		Duration:  s.elapsed(),
+	}
+	s.m.Switch(StateResults)
+}
+
+// elapsed returns the time played, counted in ticks so it stops while the window is paused
+func (s *gameplayScreen) elapsed() time.Duration {
+	return time.Duration(s.ticks) * time.Second / TPS
+}
+
+// handLayout returns the top left corner of each of count cards in the hand,
+// spread in a shallow arc along the bottom of the screen
+func handLayout(count int) []image.Point {
+	if count == 0 {
+		return nil
+	}
+
+	step := CardWidth + 8
+	if count > 1 && (count-1)*step+CardWidth > handMaxWidth {
+		step = (handMaxWidth - CardWidth) / (count - 1)
+	}
+	width := (count-1)*step + CardWidth
+	left := (ScreenWidth - width) / 2
+
+	points := make([]image.Point, count)
+	for i := range points {
+		// From -1 at the left end to 1 at the right end
+		t := 0.0
+		if count > 1 {
+			t = float64(2*i)/float64(count-1) - 1
+		}
+		points[i] = image.Pt(left+i*step, handY+int(handArc*t*t))
+	}
+	return points
+}
+
+// cardAt returns the index of the hand card under p, -1 if there is none
+// Cards further right lie on top
+func (s *gameplayScreen) cardAt(p image.Point) int {
+	points := handLayout(s.player().Hand.Len())
+	for i := len(points) - 1; i >= 0; i-- {
+		rect := cardRect(points[i])
+		if i == s.hover {
+			rect.Min.Y -= hoverLift
+		}
+		if p.In(rect) {
+			return i
+		}
+	}
+	return -1
+}
+
+// wheelCenters returns the middle of each color on the color wheel
+func wheelCenters() []image.Point {
+	return []image.Point{
+		wheelCenter.Add(image.Pt(0, -wheelSpread)),
+		wheelCenter.Add(image.Pt(wheelSpread, 0)),
+		wheelCenter.Add(image.Pt(0, wheelSpread)),
+		wheelCenter.Add(image.Pt(-wheelSpread, 0)),
+	}
+}
+
+// wheelColorAt returns the color of the wheel under p
+func wheelColorAt(p image.Point) (game.CardColor, bool) {
+	for i, center := range wheelCenters() {
+		d := p.Sub(center)
+		if d.X*d.X+d.Y*d.Y <= wheelRadius*wheelRadius {
+			return wheelColors[i], true
+		}
+	}
+	return game.Wild, false
+}
+
+// status tells the player whose turn it is and what they can do
+func (s *gameplayScreen) status() string {
+	switch {
+	case s.state.Phase == game.PhaseGameOver:
+		if winner := s.state.Winner(); winner >= 0 {
+			return s.names[winner] + " wins!"
+		}
+		return "Game over"
+	case s.choosingColor():
+		return "Choose a color"
+	case s.state.CurrentPlayer != s.seat:
+		return s.names[s.state.CurrentPlayer] + "'s turn"
+	case s.state.HasDrawn:
+		return "Your turn: play the card you drew or end your turn"
+	default:
+		return "Your turn: play a card or draw one"
+	}
+}
+
+func (s *gameplayScreen) Draw(dst *ebiten.Image) {
+	dst.Fill(palette.Background)
+
+	// The bot's hand, face down, without the cards still on their way
+	opponent := s.opponent()
+	count := s.state.Players[opponent].Hand.Len()
+	for _, p := range opponentLayout(count)[:count-s.incoming[opponent]] {
+		s.m.Resources.Cards.DrawBack(dst, cardRect(p))
+	}
+	label := fmt.Sprintf("%s: %d cards", s.names[opponent], count-s.incoming[opponent])
+	if s.state.Players[opponent].HasCalledUno {
+		label += "  UNO!"
+	}
+	drawHeading(dst, label, 40+CardHeight+8)
+
+	// Piles and the active color
+	s.m.Resources.Cards.DrawBack(dst, drawPileRect)
+	drawTextCentered(dst, fmt.Sprintf("%d left", s.state.DrawPile.Size()), drawPileRect.Add(image.Pt(0, CardHeight/2+12)))
+	s.drawDiscard(dst)
+	swatch := image.Pt(discardPileRect.Max.X+48, discardPileRect.Min.Y+CardHeight/2)
+	vector.DrawFilledCircle(dst, float32(swatch.X), float32(swatch.Y), 20, cardColors[s.state.ActiveColor].Primary, true)
+	drawText(dst, s.state.ActiveColor.String(), swatch.X-20, swatch.Y+28)
+
+	// Whose turn it is, what happened and any message for the player
+	drawHeading(dst, s.status(), pilesY-40)
+	drawHeading(dst, s.log, discardPileRect.Max.Y+40)
+	if s.message != "" {
+		drawHeading(dst, s.message, handY-60)
+	}
+
+	s.drawHand(dst)
+	drawText(dst, s.names[s.seat], 40, ScreenHeight-30)
+	drawButtons(dst, s.layout)
+	s.drawFlights(dst)
+	s.drawEffects(dst)
+
+	if s.choosingColor() && !s.anims.Blocking() {
+		s.drawWheel(dst)
+	}
+}
+
+// drawHand draws the player's cards, marking those they can play
+func (s *gameplayScreen) drawHand(dst *ebiten.Image) {
+	myTurn := s.state.CurrentPlayer == s.seat && s.state.Phase == game.PhasePlay && !s.anims.Blocking()
+	playable := s.canPlay()
+	hand := s.player().Hand.Len()
+	for i, p := range handLayout(hand)[:hand-s.incoming[s.seat]] {
+		if i == s.hover || i == s.pending {
+			p.Y -= hoverLift
+		}
+		rect := cardRect(p)
+		s.m.Resources.Cards.DrawFace(dst, s.player().Hand.At(i), rect)
+		if myTurn && playable[i] {
+			strokeRect(dst, rect.Inset(-2), palette.Playable)
+		}
+	}
+}
+
+// canPlay marks the cards of the player's hand that one of their legal moves plays
+func (s *gameplayScreen) canPlay() []bool {
+	s.plays = s.plays[:0]
+	for range s.player().Hand.Len() {
+		s.plays = append(s.plays, false)
+	}
+
+	s.legal = game.AppendLegalMoves(s.legal[:0], s.state, s.seat)
+	for _, move := range s.legal {
+		if move.Kind == game.MovePlay {
+			s.plays[move.CardIndex] = true
+		}
+	}
+	return s.plays
+}
+
+// drawWheel shades the table and draws the four colors to pick from
+func (s *gameplayScreen) drawWheel(dst *ebiten.Image) {
+	drawRect(dst, image.Rect(0, 0, ScreenWidth, ScreenHeight), shadeColor)
+	for i, center := range wheelCenters() {
+		vector.DrawFilledCircle(dst, float32(center.X), float32(center.Y), wheelRadius, cardColors[wheelColors[i]].Primary, true)
+	}
+	drawHeading(dst, "Choose a color", wheelCenter.Y-wheelSpread-wheelRadius-30)
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"image"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// tableHarness opens the gameplay screen on the state the builder sets up
+func tableHarness(t *testing.T, b *game.Builder) (*harness, *gameplayScreen) {
+	t.Helper()
+
+	h := newHarness(t)
+	h.must(h.click("Play Game"))
+	h.must(h.click("Start"))
+	h.expectState(StateGameplay)
+
+	state, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := h.m.Top().(*gameplayScreen)
+	screen.start(state, bot.NewGreedyBot())
+	h.settle(screen)
+	return h, screen
+}
+
+// settle idles until the cards on the table have landed
+func (h *harness) settle(screen *gameplayScreen) {
+	h.t.Helper()
+	for ticks := 0; screen.anims.Blocking(); ticks++ {
+		if ticks > 10*TPS {
+			h.t.Fatal("Expected the animations to end")
+		}
+		h.idle(1)
+	}
+}
+
+// waitBot idles until the bot thinking in the background has moved
+func (h *harness) waitBot(screen *gameplayScreen) {
+	h.t.Helper()
+	for ticks := 0; screen.thought != nil; ticks++ {
+		if ticks > 10*TPS {
+			h.t.Fatal("Expected the bot to move")
+		}
+		time.Sleep(time.Millisecond)
+		h.idle(1)
+	}
+}
+
+// clickCard clicks the card at the index of the player's hand
+func (h *harness) clickCard(screen *gameplayScreen, index int) {
+	h.t.Helper()
+	p := handLayout(screen.player().Hand.Len())[index]
+	h.must(h.clickAt(p.Add(image.Pt(CardWidth/2, CardHeight/2))))
+}
+
+// clickColor clicks a color on the color wheel
+func (h *harness) clickColor(color game.CardColor) {
+	h.t.Helper()
+	for i, c := range wheelColors {
+		if c == color {
+			h.must(h.clickAt(wheelCenters()[i]))
+			return
+		}
+	}
+	h.t.Fatalf("Expected %v on the wheel", color)
+}
+
+func TestGameplayDeal(t *testing.T) {
+	h := newHarness(t)
+	h.must(h.click("Play Game"))
+	h.must(h.click("Start"))
+
+	screen := h.m.Top().(*gameplayScreen)
+	if screen.player().Hand.Len() != InitialHandSize || screen.names[0] != "Ann" || screen.names[1] != "greedy" {
+		t.Errorf("Expected Ann dealt %d cards against greedy, got %v with %d cards", InitialHandSize, screen.names, screen.player().Hand.Len())
+	}
+	if h.m.Seed != 1 {
+		t.Errorf("Expected the next game to be dealt with the next seed, got %d", h.m.Seed)
+	}
+}
+
+func TestGameplayIllegalPlay(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "R7 B3").Hand(1, "Y1 Y2").Discard("G5"))
+
+	h.clickCard(screen, 1)
+	_, expected := screen.rules.ValidateMove(screen.player(), 1, screen.state)
+	if screen.message != expected || expected == "" {
+		t.Errorf("Expected the message %q, got %q", expected, screen.message)
+	}
+	if screen.player().Hand.Len() != 2 {
+		t.Error("Expected the card to stay in the hand")
+	}
+
+	// The message goes away after a while
+	h.idle(messageTicks)
+	if screen.message != "" {
+		t.Errorf("Expected the message to go, got %q", screen.message)
+	}
+}
+
+// Test that the cards marked as playable are the ones the legal moves play
+func TestGameplayPlayable(t *testing.T) {
+	_, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3 G8").Hand(1, "Y1 Y2").Discard("G5"))
+	if playable := screen.canPlay(); !playable[0] || playable[1] || !playable[2] {
+		t.Errorf("Expected G7 and G8 to be playable, got %v", playable)
+	}
+
+	// After a draw only the drawn card may be played
+	_, screen = tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3 G8").Hand(1, "Y1 Y2").Discard("G5").Drawn())
+	if playable := screen.canPlay(); playable[0] || playable[1] || !playable[2] {
+		t.Errorf("Expected only the drawn G8 to be playable, got %v", playable)
+	}
+
+	// Nothing is playable on the bot's turn
+	_, screen = tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3 G8").Hand(1, "Y1 Y2").Discard("G5").Turn(1))
+	if playable := screen.canPlay(); playable[0] || playable[1] || playable[2] {
+		t.Errorf("Expected nothing to be playable on the bot's turn, got %v", playable)
+	}
+}
+
+func TestGameplayPlayAndBot(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3 B4").Hand(1, "Y1 Y2 G1").Discard("G5"))
+
+	h.clickCard(screen, 0)
+	top, _ := screen.state.DiscardPile.Top()
+	if top != (game.Card{Color: game.Green, Type: game.Number, Value: 7}) {
+		t.Errorf("Expected G7 on the discard pile, got %v", top)
+	}
+	if screen.state.CurrentPlayer != 1 || screen.log != "Ann played Green 7" {
+		t.Errorf("Expected the bot's turn after Ann's play, got %d and %q", screen.state.CurrentPlayer, screen.log)
+	}
+
+	// The bot takes its time, then plays its only green card
+	h.idle(botDelay - 1)
+	if screen.state.CurrentPlayer != 1 {
+		t.Error("Expected the bot to wait before moving")
+	}
+	h.idle(2)
+	h.waitBot(screen)
+	if screen.state.CurrentPlayer != 0 || screen.state.Players[1].Hand.Len() != 2 {
+		t.Errorf("Expected the bot to have played, got %q", screen.log)
+	}
+}
+
+// stuckBot thinks until released
+type stuckBot struct {
+	bot.GreedyBot
+	release chan struct{}
+}
+
+func (b *stuckBot) ChooseMove(view game.PlayerView) game.Move {
+	<-b.release
+	return b.GreedyBot.ChooseMove(view)
+}
+
+func TestGameplayBotThinksInBackground(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3").Hand(1, "Y1 G1").Discard("G5").Turn(1))
+	b := &stuckBot{release: make(chan struct{})}
+	screen.start(screen.state, b)
+	h.settle(screen)
+
+	// The frames go on while the bot makes up its mind
+	h.idle(botDelay + TPS)
+	if screen.thought == nil || screen.state.CurrentPlayer != 1 {
+		t.Fatal("Expected the bot to still be thinking")
+	}
+
+	close(b.release)
+	h.waitBot(screen)
+	if screen.state.CurrentPlayer != 0 {
+		t.Error("Expected the bot to move once it made up its mind")
+	}
+}
+
+func TestGameplayWild(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "W B3 B4").Hand(1, "Y1 Y2").Discard("G5"))
+
+	h.clickCard(screen, 0)
+	if !screen.choosingColor() || screen.player().Hand.Len() != 3 {
+		t.Fatal("Expected the color wheel before the wild card is played")
+	}
+
+	// Clicking away puts the card back
+	h.must(h.clickAt(image.Pt(10, 10)))
+	if screen.choosingColor() {
+		t.Fatal("Expected the wheel to close")
+	}
+
+	h.clickCard(screen, 0)
+	h.clickColor(game.Blue)
+	if screen.state.ActiveColor != game.Blue || screen.player().Hand.Len() != 2 {
+		t.Errorf("Expected the wild card played for blue, got %v", screen.state.ActiveColor)
+	}
+}
+
+func TestGameplayStartingWild(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "B3 B4").Hand(1, "Y1 Y2").Discard("W").Phase(game.PhaseColorSelection))
+
+	if !screen.choosingColor() || screen.status() != "Choose a color" {
+		t.Fatal("Expected the color wheel for the starting card")
+	}
+	h.clickColor(game.Yellow)
+	if screen.state.Phase != game.PhasePlay || screen.state.ActiveColor != game.Yellow {
+		t.Errorf("Expected yellow chosen, got %v in phase %v", screen.state.ActiveColor, screen.state.Phase)
+	}
+	if screen.state.CurrentPlayer != 0 {
+		t.Error("Expected Ann to take the first turn after choosing")
+	}
+}
+
+func TestGameplayDrawAndEndTurn(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "R7").Hand(1, "Y1 Y2").Discard("G5").DrawPile("B1"))
+
+	h.must(h.click("End Turn"))
+	if screen.message != "Draw a card before ending your turn" {
+		t.Errorf("Expected to be told to draw first, got %q", screen.message)
+	}
+
+	h.must(h.clickAt(drawPileRect.Min.Add(image.Pt(CardWidth/2, CardHeight/2))))
+	if screen.player().Hand.Len() != 2 || !screen.state.HasDrawn {
+		t.Fatal("Expected Ann to draw a card")
+	}
+
+	// Drawing twice is not allowed, the click waiting for the drawn card to land
+	h.must(h.clickAt(drawPileRect.Min.Add(image.Pt(CardWidth/2, CardHeight/2))))
+	if screen.queued == nil || screen.message != "" {
+		t.Error("Expected the click to wait for the card to land")
+	}
+	h.settle(screen)
+	if screen.player().Hand.Len() != 2 || screen.message == "" {
+		t.Errorf("Expected a second draw to be refused, got %q", screen.message)
+	}
+
+	h.must(h.click("End Turn"))
+	if screen.state.CurrentPlayer != 1 {
+		t.Error("Expected the bot's turn")
+	}
+}
+
+func TestGameplayUno(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3").Hand(1, "Y1 Y2").Discard("G5").Played(0))
+
+	// UNO with two cards is called along with the next play
+	h.must(h.click("UNO!"))
+	if !screen.unoArmed {
+		t.Fatal("Expected UNO to wait for the play")
+	}
+	h.clickCard(screen, 0)
+	if !screen.player().HasCalledUno {
+		t.Error("Expected UNO called with the play")
+	}
+}
+
+// soundLog records the sound effects played
+type soundLog []string
+
+func (l *soundLog) Play(name string) {
+	*l = append(*l, name)
+}
+
+func TestGameplaySounds(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3 GS").Hand(1, "Y1 Y2 Y3").Discard("G5"))
+	played := &soundLog{}
+	h.m.Sounds = played
+
+	h.clickCard(screen, 1)
+	h.clickCard(screen, 0)
+	if !slices.Equal(*played, []string{"invalid", "play"}) {
+		t.Errorf("Expected the sounds of a refused and a good play, got %v", *played)
+	}
+
+	// The bot has no yellow play on green and draws
+	h.idle(botDelay + 1)
+	h.waitBot(screen)
+	h.settle(screen)
+	if !slices.Contains(*played, "draw") {
+		t.Errorf("Expected the sound of the bot drawing, got %v", *played)
+	}
+
+	// Turned off in the settings, nothing plays
+	*played = nil
+	h.m.Settings.Sound = false
+	h.clickCard(screen, 0)
+	if len(*played) != 0 {
+		t.Errorf("Expected silence with sound off, got %v", *played)
+	}
+}
+
+func TestGameplayChallenge(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3").Hand(1, "Y1").Discard("G5").Played(1))
+
+	h.must(h.click("Challenge"))
+	if screen.state.Players[1].Hand.Len() != 3 {
+		t.Errorf("Expected the bot to take 2 cards, got %d", screen.state.Players[1].Hand.Len())
+	}
+
+	h.settle(screen)
+	h.must(h.click("Challenge"))
+	if screen.message != "The player cannot be challenged" {
+		t.Errorf("Expected a second challenge to be refused, got %q", screen.message)
+	}
+}
+
+func TestGameplayWin(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7").Hand(1, "Y1 Y2").Discard("G5").Played(0).CalledUno(0))
+
+	h.clickCard(screen, 0)
+	if screen.state.Phase != game.PhaseGameOver || screen.status() != "Ann wins!" {
+		t.Fatalf("Expected Ann to win, got %q", screen.status())
+	}
+
+	// The results wait for the card to land
+	h.settle(screen)
+	h.idle(gameOverTicks)
+	h.expectState(StateResults)
+	result := h.m.Result
+	if result.Winner != 0 || result.CardsLeft[1] != 2 {
+		t.Errorf("Expected Ann to win with greedy holding 2 cards, got %+v", result)
+	}
+}
+
+func TestHandLayout(t *testing.T) {
+	for _, count := range []int{1, 7, 30} {
+		points := handLayout(count)
+		if len(points) != count {
+			t.Fatalf("Expected %d cards, got %d", count, len(points))
+		}
+
+		width := points[count-1].X + CardWidth - points[0].X
+		if width > handMaxWidth {
+			t.Errorf("Expected %d cards to fit in %d pixels, got %d", count, handMaxWidth, width)
+		}
+		if left, right := points[0].X, ScreenWidth-(points[count-1].X+CardWidth); left-right > 1 || right-left > 1 {
+			t.Errorf("Expected %d cards centered, got margins %d and %d", count, left, right)
+		}
+		if points[count/2].Y > points[0].Y {
+			t.Errorf("Expected the middle of the hand to sit highest, got %v", points)
+		}
+	}
+}
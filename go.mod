@@ -2,14 +2,21 @@ module github.com/vtigo/uno-clone
 
 go 1.24.2
 
-require github.com/hajimehoshi/ebiten/v2 v2.8.7
+require (
+	github.com/hajimehoshi/ebiten/v2 v2.8.7
+	golang.org/x/crypto v0.28.0
+	golang.org/x/image v0.26.0
+	golang.org/x/term v0.25.0
+)
 
 require (
 	github.com/ebitengine/gomobile v0.0.0-20240911145611-4856209ac325 // indirect
 	github.com/ebitengine/hideconsole v1.0.0 // indirect
-	github.com/ebitengine/purego v0.8.0 // indirect
+	github.com/ebitengine/oto/v3 v3.4.0 // indirect
+	github.com/ebitengine/purego v0.9.0 // indirect
+	github.com/go-text/typesetting v0.2.1 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
-	golang.org/x/image v0.26.0 // indirect
-	golang.org/x/sync v0.8.0 // indirect
-	golang.org/x/sys v0.25.0 // indirect
+	golang.org/x/sync v0.13.0 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+	golang.org/x/text v0.24.0 // indirect
 )
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// fakeInput is the input of one tick, made up by a test
+type fakeInput struct {
+	cursor  image.Point
+	clicked bool
+	keys    map[ebiten.Key]bool
+	chars   []rune
+	wheel   float64
+}
+
+func (in *fakeInput) Cursor() image.Point            { return in.cursor }
+func (in *fakeInput) Clicked() bool                  { return in.clicked }
+func (in *fakeInput) KeyPressed(key ebiten.Key) bool { return in.keys[key] }
+func (in *fakeInput) Chars() []rune                  { return in.chars }
+func (in *fakeInput) Wheel() float64                 { return in.wheel }
+
+// harness drives a screen manager without a window, a tick at a time
+type harness struct {
+	t *testing.T
+	m *ScreenManager
+}
+
+func newHarness(t *testing.T) *harness {
+	resources, err := LoadResources(embeddedAssets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewScreenManager(Settings{PlayerName: "Ann", Opponent: "greedy", Sound: true})
+	m.Resources = resources
+	return &harness{t: t, m: m}
+}
+
+// tick runs one Update with the input, then lets any transition finish
+func (h *harness) tick(in *fakeInput) error {
+	h.t.Helper()
+
+	err := h.m.Update(in)
+	for ticks := 0; h.m.Transitioning(); ticks++ {
+		if ticks > 10*transitionTicks {
+			h.t.Fatal("Expected the transition to end")
+		}
+		h.m.Update(&fakeInput{})
+	}
+	return err
+}
+
+// idle runs ticks without any input
+func (h *harness) idle(ticks int) {
+	h.t.Helper()
+	for range ticks {
+		if err := h.tick(&fakeInput{}); err != nil {
+			h.t.Fatal(err)
+		}
+	}
+}
+
+// click clicks the button with the label on the screen shown
+func (h *harness) click(label string) error {
+	h.t.Helper()
+
+	rect, ok := h.button(label)
+	if !ok {
+		h.t.Fatalf("Expected a %q button on screen %d", label, h.m.State())
+	}
+	return h.clickAt(rect.Min.Add(image.Pt(rect.Dx()/2, rect.Dy()/2)))
+}
+
+// clickAt clicks a point of the screen, after moving the cursor there
+func (h *harness) clickAt(p image.Point) error {
+	h.t.Helper()
+	if err := h.tick(&fakeInput{cursor: p}); err != nil {
+		return err
+	}
+	return h.tick(&fakeInput{cursor: p, clicked: true})
+}
+
+// press presses a key
+func (h *harness) press(key ebiten.Key) error {
+	h.t.Helper()
+	return h.tick(&fakeInput{keys: map[ebiten.Key]bool{key: true}})
+}
+
+// typeText types the text in one tick
+func (h *harness) typeText(text string) error {
+	h.t.Helper()
+	return h.tick(&fakeInput{chars: []rune(text)})
+}
+
+// button finds a button by its label on the screen shown
+func (h *harness) button(label string) (image.Rectangle, bool) {
+	if screen, ok := h.m.Top().(buttoned); ok {
+		for _, b := range screen.buttons() {
+			if b.label == label {
+				return b.rect, true
+			}
+		}
+	}
+	return image.Rectangle{}, false
+}
+
+// expectState fails the test unless the screen shown is for the state
+func (h *harness) expectState(state int) {
+	h.t.Helper()
+	if got := h.m.State(); got != state {
+		h.t.Fatalf("Expected screen %d, got %d", state, got)
+	}
+}
+
+// must fails the test on an error from driving the screens
+func (h *harness) must(err error) {
+	h.t.Helper()
+	if err != nil {
+		h.t.Fatal(err)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Input is what screens read of the mouse and keyboard in a tick
+// Screens never ask Ebiten directly, so tests can drive them with made up input
+type Input interface {
+	Cursor() image.Point
+	Clicked() bool                  // Left button pressed this tick
+	KeyPressed(key ebiten.Key) bool // Key pressed this tick
+	Chars() []rune                  // Text typed this tick
+	Wheel() float64                 // Vertical scroll this tick
+}
+
+// ebitenInput reads the real mouse and keyboard
+type ebitenInput struct{}
+
+func (ebitenInput) Cursor() image.Point {
+	return image.Pt(ebiten.CursorPosition())
+}
+
+func (ebitenInput) Clicked() bool {
+	return inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+}
+
+func (ebitenInput) KeyPressed(key ebiten.Key) bool {
+	return inpututil.IsKeyJustPressed(key)
+}
+
+func (ebitenInput) Chars() []rune {
+	return ebiten.AppendInputChars(nil)
+}
+
+func (ebitenInput) Wheel() float64 {
+	_, y := ebiten.Wheel()
+	return y
+}
+
+// queuedClick replays a click made while the screen was busy
+type queuedClick image.Point
+
+func (c queuedClick) Cursor() image.Point          { return image.Point(c) }
+func (queuedClick) Clicked() bool                  { return true }
+func (queuedClick) KeyPressed(key ebiten.Key) bool { return false }
+func (queuedClick) Chars() []rune                  { return nil }
+func (queuedClick) Wheel() float64                 { return 0 }
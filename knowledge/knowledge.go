@@ -0,0 +1,402 @@
+// Package knowledge follows the public events of a game and keeps track of what one
+// player can work out about everyone else's hand
+package knowledge
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Tracker models the hands of a game from one seat's point of view
+// Feed it every event with Observe and the seat's latest view with Update
+// Players mostly draw when they have nothing to play, but may draw by choice, so a
+// draw makes the cards that matched the discard pile less likely without ruling them out
+type Tracker struct {
+	seat  int
+	view  game.PlayerView
+	hands []hand
+
+	top    game.Card
+	active game.CardColor
+
+	started    bool
+	finished   bool // The last game ended, the next view starts a new one
+	reshuffled bool // The discard pile was recycled since the last view
+
+	unseen []game.Card // Unseen worked out since the model last changed, nil before
+}
+
+// hand is what we know about one player's hand: some cards for certain,
+// the rest unknown, split into groups that are known not to hold certain cards
+type hand struct {
+	known    []game.Card
+	groups   []group
+	justDrew bool // The last group is the single card the player drew this turn
+}
+
+// drawConfidence is the chance that a player who drew or passed had nothing to play
+const drawConfidence = 0.9
+
+// group is a number of unknown cards that probably matched none of the discard
+// piles the player drew on
+type group struct {
+	count  int
+	misses [game.CardKinds]uint8 // Draws and passes every kind of card, see game.KindOf, would have been played on
+}
+
+// kindCards holds a card of every kind, see game.KindOf
+var kindCards = func() (cards [game.CardKinds]game.Card) {
+	deck := game.NewDeckWithOptions(game.GameOptions{ShuffleHandsCards: 1, SwapHandsCards: 1, CustomizableCards: 1})
+	for _, card := range deck.Cards() {
+		cards[game.KindOf(card)] = card
+	}
+	return cards
+}()
+
+// missWeights holds the weight of a card after every number of misses, see weight
+var missWeights = func() (weights [256]float64) {
+	weights[0] = 1
+	for i := 1; i < len(weights); i++ {
+		weights[i] = weights[i-1] * (1 - drawConfidence)
+	}
+	return weights
+}()
+
+// New creates a tracker, the seat is taken from the first view
+func New() *Tracker {
+	return &Tracker{}
+}
+
+// lack records that the player drew or passed rather than play a card of the group on the discard pile
+func (g *group) lack(top game.Card, active game.CardColor) {
+	for kind, card := range kindCards {
+		if game.MatchesTop(card, top, active) && g.misses[kind] < 255 {
+			g.misses[kind]++
+		}
+	}
+}
+
+func (g *group) allows(card game.Card) bool {
+	return g.misses[game.KindOf(card)] == 0
+}
+
+// weight returns how likely the group is to hold the card, compared to a card it
+// has no reason to lack: every draw or pass the card would have been played on
+// makes it less likely
+func (g *group) weight(card game.Card) float64 {
+	return missWeights[g.misses[game.KindOf(card)]]
+}
+
+func (h hand) size() int {
+	size := len(h.known)
+	for _, g := range h.groups {
+		size += g.count
+	}
+	return size
+}
+
+// Update syncs the tracker with the seat's latest view. Visible hands become known
+// and any hand whose size does not match what the events told us is reset
+func (t *Tracker) Update(view game.PlayerView) {
+	// A shorter discard pile without a reshuffle means a new game started
+	newGame := !t.started || t.finished || t.seat != view.Seat || len(t.hands) != len(view.HandSizes) ||
+		(len(view.DiscardPile) < len(t.view.DiscardPile) && !t.reshuffled)
+	if newGame {
+		t.seat = view.Seat
+		t.hands = make([]hand, len(view.HandSizes))
+		t.started = true
+		t.finished = false
+	}
+
+	for i := range t.hands {
+		switch {
+		case view.Hands[i] != nil:
+			t.hands[i] = hand{known: append([]game.Card(nil), view.Hands[i]...)}
+		case t.hands[i].size() != view.HandSizes[i]:
+			t.hands[i] = hand{groups: []group{{count: view.HandSizes[i]}}}
+		}
+	}
+
+	t.view = view
+	t.top = view.TopCard
+	t.active = view.ActiveColor
+	t.reshuffled = false
+	t.unseen = nil
+}
+
+// Observe updates the model with one public event
+func (t *Tracker) Observe(event game.Event) {
+	if !t.started || event.Player < 0 || event.Player >= len(t.hands) {
+		return
+	}
+	h := &t.hands[event.Player]
+	t.unseen = nil
+
+	switch event.Kind {
+	case game.EventPlay:
+		h.remove(event.Card)
+		h.justDrew = false
+		t.top = event.Card
+		if event.Card.Color != game.Wild {
+			t.active = event.Card.Color
+		} else {
+			t.active = event.Color
+		}
+	case game.EventChooseColor:
+		t.active = event.Color
+	case game.EventDraw:
+		// Most likely nothing in the hand could be played, the drawn card is still a mystery
+		for i := range h.groups {
+			h.groups[i].lack(t.top, t.active)
+		}
+		h.groups = append(h.groups, group{count: event.Count})
+		h.justDrew = true
+	case game.EventPass:
+		// The drawn card most likely did not fit either
+		if h.justDrew {
+			last := &h.groups[len(h.groups)-1]
+			last.lack(t.top, t.active)
+		}
+		h.justDrew = false
+	case game.EventPenalty:
+		h.groups = append(h.groups, group{count: event.Count})
+		h.justDrew = false
+	case game.EventShuffleHands:
+		// Nobody knows anything anymore, the next view tells the new sizes
+		for i := range t.hands {
+			t.hands[i] = hand{}
+		}
+	case game.EventSwapHands:
+		if event.Target >= 0 && event.Target < len(t.hands) {
+			t.hands[event.Player], t.hands[event.Target] = t.hands[event.Target], t.hands[event.Player]
+		}
+	case game.EventReshuffle:
+		t.reshuffled = true
+	case game.EventGameOver:
+		t.finished = true
+	}
+}
+
+// remove takes a played card out of the model of a hand
+func (h *hand) remove(card game.Card) {
+	// After drawing only the drawn card can be played
+	if h.justDrew && len(h.groups) > 0 {
+		h.takeFrom(len(h.groups) - 1)
+		return
+	}
+
+	for i, known := range h.known {
+		if known == card {
+			h.known = append(h.known[:i], h.known[i+1:]...)
+			return
+		}
+	}
+
+	// Take it from the biggest group with no reason to lack it
+	best := -1
+	for i, g := range h.groups {
+		if g.count > 0 && g.allows(card) && (best == -1 || g.count > h.groups[best].count) {
+			best = i
+		}
+	}
+
+	// No group could hold it, so the player drew without having to. Forget what the draws told us
+	if best == -1 {
+		for i := range h.groups {
+			h.groups[i].misses = [game.CardKinds]uint8{}
+			if h.groups[i].count > 0 && (best == -1 || h.groups[i].count > h.groups[best].count) {
+				best = i
+			}
+		}
+	}
+
+	if best != -1 {
+		h.takeFrom(best)
+	}
+}
+
+// takeFrom removes one card from a group, dropping the group once it is empty
+func (h *hand) takeFrom(i int) {
+	h.groups[i].count--
+	if h.groups[i].count == 0 {
+		h.groups = append(h.groups[:i], h.groups[i+1:]...)
+	}
+}
+
+// Known returns the cards the player at the given seat is known to hold
+func (t *Tracker) Known(seat int) []game.Card {
+	return append([]game.Card(nil), t.hands[seat].known...)
+}
+
+// Unknown returns how many of the player's cards are not known
+func (t *Tracker) Unknown(seat int) int {
+	return t.hands[seat].size() - len(t.hands[seat].known)
+}
+
+// Unseen returns the cards whose whereabouts are unknown: they lie in the draw
+// pile or among the unknown cards of some hand. Searches ask for them on every
+// iteration, so they are only worked out again once the model changes
+func (t *Tracker) Unseen() ([]game.Card, error) {
+	if t.unseen != nil {
+		return slices.Clone(t.unseen), nil
+	}
+
+	unseen, err := game.UnseenCards(t.view)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, h := range t.hands {
+		if t.view.Hands[i] != nil {
+			continue
+		}
+		for _, card := range h.known {
+			unseen = removeCard(unseen, card)
+		}
+	}
+	t.unseen = unseen
+	return slices.Clone(unseen), nil
+}
+
+// Probability returns the chance that the player at the given seat holds at least
+// one card the match function accepts, treating unseen cards as equally likely
+func (t *Tracker) Probability(seat int, match func(game.Card) bool) float64 {
+	h := t.hands[seat]
+	for _, card := range h.known {
+		if match(card) {
+			return 1
+		}
+	}
+
+	unseen, err := t.Unseen()
+	if err != nil {
+		return 0
+	}
+
+	// Every group draws its cards from the unseen cards, each counted by its weight
+	none := 1.0
+	for _, g := range h.groups {
+		pool, matching := 0.0, 0.0
+		for _, card := range unseen {
+			weight := g.weight(card)
+			pool += weight
+			if match(card) {
+				matching += weight
+			}
+		}
+		none *= noneDrawn(pool, matching, g.count)
+	}
+
+	return 1 - none
+}
+
+// HoldsColor returns the chance that the player holds at least one card of the color
+func (t *Tracker) HoldsColor(seat int, color game.CardColor) float64 {
+	return t.Probability(seat, func(card game.Card) bool {
+		return card.Color == color
+	})
+}
+
+// noneDrawn returns the chance that n cards taken from a pool holding matching
+// cards miss every one of them. Weighted cards count as part of a card
+func noneDrawn(pool, matching float64, n int) float64 {
+	p := 1.0
+	for i := range n {
+		left := pool - float64(i)
+		if left <= 0 {
+			break
+		}
+		p *= (left - matching) / left
+		if p <= 0 {
+			return 0
+		}
+	}
+	return p
+}
+
+// Determinize builds a complete game state that agrees with everything the tracker
+// knows: known cards stay where they are and unknown cards are dealt from the
+// unseen cards, each as likely as its weight in the group
+func (t *Tracker) Determinize(r *rand.Rand) (*game.GameState, error) {
+	if !t.started {
+		return nil, errors.New("the tracker has not seen the game yet")
+	}
+
+	unseen, err := t.Unseen()
+	if err != nil {
+		return nil, err
+	}
+	r.Shuffle(len(unseen), func(i, j int) {
+		unseen[i], unseen[j] = unseen[j], unseen[i]
+	})
+
+	hands := make([][]game.Card, len(t.hands))
+	var weights []float64
+	for i, h := range t.hands {
+		if t.view.Hands[i] != nil {
+			hands[i] = t.view.Hands[i]
+			continue
+		}
+
+		// Older groups come first, they have been through the most draws
+		hands[i] = append(hands[i], h.known...)
+		for _, g := range h.groups {
+			if len(unseen) < g.count {
+				return nil, errors.New("not enough unseen cards to fill the hidden hands")
+			}
+
+			// The unseen cards are shuffled, so a group with nothing to lack takes the first ones
+			if g.misses == ([game.CardKinds]uint8{}) {
+				hands[i] = append(hands[i], unseen[:g.count]...)
+				unseen = unseen[g.count:]
+				continue
+			}
+
+			weights = weights[:0]
+			total := 0.0
+			for _, card := range unseen {
+				weights = append(weights, g.weight(card))
+				total += weights[len(weights)-1]
+			}
+			for range g.count {
+				pick := pickWeighted(weights, total, r)
+				hands[i] = append(hands[i], unseen[pick])
+				total -= weights[pick]
+				unseen = append(unseen[:pick], unseen[pick+1:]...)
+				weights = append(weights[:pick], weights[pick+1:]...)
+			}
+		}
+	}
+
+	if len(unseen) != t.view.DrawPileSize {
+		return nil, fmt.Errorf("expected %d cards left for the draw pile, got %d", t.view.DrawPileSize, len(unseen))
+	}
+
+	return game.StateFromHands(t.view, hands, unseen), nil
+}
+
+// pickWeighted returns an index picked with a chance proportional to its weight
+func pickWeighted(weights []float64, total float64, r *rand.Rand) int {
+	x := r.Float64() * total
+	for i, weight := range weights {
+		x -= weight
+		if x < 0 {
+			return i
+		}
+	}
+	return len(weights) - 1
+}
+
+// removeCard removes one copy of the card from cards
+func removeCard(cards []game.Card, card game.Card) []game.Card {
+	for i, c := range cards {
+		if c == card {
+			return append(cards[:i], cards[i+1:]...)
+		}
+	}
+	return cards
+}
@@ -0,0 +1,225 @@
+package knowledge
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// newState deals the given hands with top on the discard pile and the rest of the deck as draw pile
+func newState(hands [][]game.Card, top game.Card) *game.GameState {
+	remaining := game.NewDeck().Cards()
+	take := func(card game.Card) {
+		for i, c := range remaining {
+			if c == card {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				return
+			}
+		}
+	}
+
+	state := &game.GameState{
+		DiscardPile:  game.CreateDiscardPile(top),
+		ActiveColor:  top.Color,
+		Phase:        game.PhasePlay,
+		LastPlayedBy: -1,
+	}
+	take(top)
+
+	for i, hand := range hands {
+		player := game.NewPlayer("Player")
+		for _, card := range hand {
+			take(card)
+			player.AddCard(card)
+		}
+		player.IsMyTurn = i == 0
+		state.Players = append(state.Players, player)
+	}
+
+	state.DrawPile = game.NewDeckOf(remaining)
+	return state
+}
+
+// watch creates a tracker for the seat that follows every event of the rules
+func watch(state *game.GameState, rules *game.GameRules, seat int) *Tracker {
+	tracker := New()
+	tracker.Update(state.View(seat))
+	rules.Subscribe(tracker.Observe)
+	return tracker
+}
+
+func red(value int) game.Card {
+	return game.Card{Color: game.Red, Type: game.Number, Value: value}
+}
+
+func blue(value int) game.Card {
+	return game.Card{Color: game.Blue, Type: game.Number, Value: value}
+}
+
+func TestDrawAndPassRevealMissingColor(t *testing.T) {
+	state := newState([][]game.Card{{red(7), red(8)}, {blue(1), blue(2), blue(3)}}, red(5))
+	rules := game.NewGameRules()
+	tracker := watch(state, rules, 0)
+
+	before := tracker.HoldsColor(1, game.Red)
+	if before <= 0 || before >= 1 {
+		t.Errorf("Expected an unknown hand to hold red with some chance, got %f", before)
+	}
+
+	moves := []game.Move{
+		{Kind: game.MovePlay, Player: 0, CardIndex: 0},
+		{Kind: game.MoveDraw, Player: 1},
+	}
+	for _, move := range moves {
+		if err := rules.Apply(state, move); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Passing only makes sense if the drawn card does not fit either
+	if state.Phase == game.PhasePlay && state.CurrentPlayer == 1 && game.IsLegalMove(state, game.Move{Kind: game.MovePass, Player: 1}) {
+		if err := rules.Apply(state, game.Move{Kind: game.MovePass, Player: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tracker.Update(state.View(0))
+
+	// Players may draw by choice, so red becomes unlikely but not impossible
+	after := tracker.HoldsColor(1, game.Red)
+	if after <= 0 || after >= before/2 {
+		t.Errorf("Expected a player who drew and passed on red to be unlikely to hold red, got %f after %f", after, before)
+	}
+
+	if p := tracker.HoldsColor(1, game.Blue); p <= 0 {
+		t.Errorf("Expected the player to hold blue with some chance, got %f", p)
+	}
+
+	// Sampled hands respect what the tracker learned
+	r := rand.New(rand.NewPCG(1, 2))
+	matching, cards := 0, 0
+	for range 200 {
+		sample, err := tracker.Determinize(r)
+		if err != nil {
+			t.Fatalf("Expected no error determinizing, got %v", err)
+		}
+
+		if sample.Players[1].HandSize() != state.Players[1].HandSize() {
+			t.Fatalf("Expected %d cards for player 2, got %d", state.Players[1].HandSize(), sample.Players[1].HandSize())
+		}
+
+		for _, card := range sample.Players[1].Hand.All() {
+			cards++
+			if card.Color == game.Red || card.Color == game.Wild {
+				matching++
+			}
+		}
+	}
+
+	// A third of the deck is red or wild, the samples should hold far fewer
+	if matching == 0 || matching*10 > cards {
+		t.Errorf("Expected few red or wild cards in the sampled hands, got %d of %d", matching, cards)
+	}
+}
+
+// Test that playing a card after drawing on it shows the draw was a choice
+func TestDrawByChoice(t *testing.T) {
+	state := newState([][]game.Card{{red(7), red(8), red(9)}, {red(1), blue(2), blue(3)}}, red(5))
+	rules := game.NewGameRules()
+	tracker := watch(state, rules, 0)
+
+	apply := func(moves ...game.Move) {
+		t.Helper()
+		for _, move := range moves {
+			if err := rules.Apply(state, move); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tracker.Update(state.View(0))
+	}
+
+	apply(
+		game.Move{Kind: game.MovePlay, Player: 0, CardIndex: 0},
+		game.Move{Kind: game.MoveDraw, Player: 1},
+		game.Move{Kind: game.MovePass, Player: 1},
+	)
+	drawn := tracker.HoldsColor(1, game.Red)
+
+	apply(
+		game.Move{Kind: game.MovePlay, Player: 0, CardIndex: 0},
+		game.Move{Kind: game.MovePlay, Player: 1, CardIndex: 0},
+	)
+
+	if tracker.Unknown(1) != state.Players[1].HandSize() {
+		t.Fatalf("Expected %d unknown cards, got %d", state.Players[1].HandSize(), tracker.Unknown(1))
+	}
+
+	// The Red 1 proves the draw on red was a choice, so red is as likely as ever
+	if p := tracker.HoldsColor(1, game.Red); p <= drawn {
+		t.Errorf("Expected red to be likelier after a red play than the %f after the draw, got %f", drawn, p)
+	}
+
+	if _, err := tracker.Determinize(rand.New(rand.NewPCG(1, 2))); err != nil {
+		t.Errorf("Expected no error determinizing, got %v", err)
+	}
+}
+
+func TestSwapRevealsHand(t *testing.T) {
+	swap := game.Card{Color: game.Wild, Type: game.WildSwapHands}
+	state := newState([][]game.Card{{swap, red(0), blue(0)}, {blue(1), blue(2)}}, red(5))
+	state.Options = game.GameOptions{SwapHandsCards: 1}
+	extra := game.NewDeckWithOptions(state.Options).Cards()[108:]
+	for _, card := range extra[:len(extra)-1] { // The swap card is in the hand
+		state.DrawPile.AddToTop(card)
+	}
+
+	rules := game.NewGameRules()
+	tracker := watch(state, rules, 0)
+
+	if err := rules.Apply(state, game.Move{Kind: game.MovePlay, Player: 0, CardIndex: 0, Color: game.Blue, Target: 1}); err != nil {
+		t.Fatal(err)
+	}
+	tracker.Update(state.View(0))
+
+	known := tracker.Known(1)
+	if len(known) != 2 || tracker.Unknown(1) != 0 {
+		t.Fatalf("Expected to know both cards given away, got %v and %d unknown", known, tracker.Unknown(1))
+	}
+
+	if p := tracker.Probability(1, func(card game.Card) bool { return card == red(0) }); p != 1 {
+		t.Errorf("Expected the Red 0 given away to be held for sure, got %f", p)
+	}
+
+	unseen, err := tracker.Unseen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Zeros have a single copy, so neither can be anywhere else
+	for _, card := range unseen {
+		if card == red(0) || card == blue(0) {
+			t.Errorf("Expected %v not to be unseen", card)
+		}
+	}
+}
+
+func TestPenaltyAndNewGame(t *testing.T) {
+	state := newState([][]game.Card{{{Color: game.Red, Type: game.DrawTwo}, red(8)}, {blue(1)}}, red(5))
+	rules := game.NewGameRules()
+	tracker := watch(state, rules, 0)
+
+	if err := rules.Apply(state, game.Move{Kind: game.MovePlay, Player: 0, CardIndex: 0}); err != nil {
+		t.Fatal(err)
+	}
+	tracker.Update(state.View(0))
+
+	if tracker.Unknown(1) != 3 {
+		t.Errorf("Expected 3 unknown cards after the penalty, got %d", tracker.Unknown(1))
+	}
+
+	// A fresh game resets the model
+	fresh := newState([][]game.Card{{red(1)}, {blue(1), blue(2), blue(3), blue(4)}}, red(5))
+	tracker.Update(fresh.View(0))
+	if tracker.Unknown(1) != 4 || len(tracker.Known(0)) != 1 {
+		t.Errorf("Expected the model to follow the new game, got %d unknown", tracker.Unknown(1))
+	}
+}
@@ -0,0 +1,81 @@
+package lobby
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Join connects to the server at addr as user and plays in its lobby, reading keys
+// from in and drawing on out, for people without an SSH client at hand. A width
+// above 0 asks the server for a terminal of that size. hostKey checks the server's
+// key, see KnownHosts
+func Join(addr, user string, hostKey ssh.HostKeyCallback, in io.Reader, out io.Writer, width, height int) error {
+	conn, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{User: user, HostKeyCallback: hostKey})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	session, err := conn.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	if width > 0 {
+		if err := session.RequestPty("xterm", height, width, ssh.TerminalModes{}); err != nil {
+			return err
+		}
+	}
+
+	// Copied by hand, since the session would otherwise wait for in to end before returning
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	go func() {
+		io.Copy(stdin, in)
+		stdin.Close()
+	}()
+	session.Stdout = out
+
+	if err := session.Shell(); err != nil {
+		return err
+	}
+	return session.Wait()
+}
+
+// KnownHosts checks server keys against the known_hosts file at path, the one
+// OpenSSH keeps. Servers missing from the file, or all of them when there is no
+// file, are trusted with their key's fingerprint written to warn, a key that
+// differs from the one listed is refused
+func KnownHosts(path string, warn io.Writer) (ssh.HostKeyCallback, error) {
+	trust := func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		fmt.Fprintf(warn, "Warning: %s is not a known host, its %s key fingerprint is %s\n", hostname, key.Type(), ssh.FingerprintSHA256(key))
+		return nil
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return trust, nil
+	}
+
+	known, err := knownhosts.New(path)
+	if err != nil {
+		return nil, err
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := known(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) && len(keyErr.Want) == 0 {
+			return trust(hostname, remote, key)
+		}
+		return err
+	}, nil
+}
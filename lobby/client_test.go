@@ -0,0 +1,70 @@
+package lobby
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestJoin(t *testing.T) {
+	addr := startServer(t, Config{})
+
+	var warning bytes.Buffer
+	hostKey, err := KnownHosts(t.TempDir()+"/known_hosts", &warning)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := Join(addr, "zoe", hostKey, strings.NewReader("who\rquit\r"), &out, 80, 24); err != nil {
+		t.Fatalf("Expected the session to end cleanly, got %v", err)
+	}
+
+	for _, want := range []string{"Welcome to UNO, zoe!", "Connected: zoe", "Bye!"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in output, got %q", want, out.String())
+		}
+	}
+	if !strings.Contains(warning.String(), "is not a known host") {
+		t.Errorf("Expected a warning about the unknown host, got %q", warning.String())
+	}
+}
+
+func TestKnownHosts(t *testing.T) {
+	key, err := LoadHostKey(t.TempDir() + "/host_key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := LoadHostKey(t.TempDir() + "/other_key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := t.TempDir() + "/known_hosts"
+	line := knownhosts.Line([]string{knownhosts.Normalize("uno.local:2222")}, key.PublicKey())
+	if err := os.WriteFile(path, []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var warning bytes.Buffer
+	check, err := KnownHosts(path, &warning)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 2222}
+
+	if err := check("uno.local:2222", remote, key.PublicKey()); err != nil || warning.Len() > 0 {
+		t.Errorf("Expected the listed key to be trusted quietly, got %v and %q", err, warning.String())
+	}
+	if err := check("uno.local:2222", remote, other.PublicKey()); err == nil {
+		t.Error("Expected a changed host key to be refused")
+	}
+	if err := check("other.local:2222", remote, other.PublicKey()); err != nil || !strings.Contains(warning.String(), ssh.FingerprintSHA256(other.PublicKey())) {
+		t.Errorf("Expected an unlisted host to be trusted with a warning, got %v and %q", err, warning.String())
+	}
+}
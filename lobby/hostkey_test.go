@@ -0,0 +1,39 @@
+package lobby
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestLoadHostKey(t *testing.T) {
+	path := t.TempDir() + "/host_key"
+
+	first, err := LoadHostKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected the key to be private, got mode %v", info.Mode())
+	}
+
+	// The saved key is used from then on
+	second, err := LoadHostKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first.PublicKey().Marshal(), second.PublicKey().Marshal()) {
+		t.Error("Expected the same host key on the second run")
+	}
+
+	if err := os.WriteFile(path, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadHostKey(path); err == nil {
+		t.Error("Expected error for a broken key")
+	}
+}
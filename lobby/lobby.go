@@ -0,0 +1,501 @@
+// Package lobby runs an SSH server for playing in the terminal without installing
+// anything: people connect with any SSH client, meet in a lobby under their user
+// name and play each other, or bots, through the text-mode UI of package tui.
+// Join connects to a server for those without an SSH client
+package lobby
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+	"github.com/vtigo/uno-clone/tui"
+)
+
+// Config describes the games played on a server
+type Config struct {
+	Options game.GameOptions // House rules for every game
+	Bot     string           // Bot for games against a bot and for seats people leave, "greedy" when empty
+	Seed    uint64           // Game i on the server is dealt with seed Seed+i
+}
+
+// Server accepts SSH connections and seats the people in games
+// There is no authentication, the server is meant for a trusted network
+type Server struct {
+	cfg    Config
+	config *ssh.ServerConfig
+
+	mu       sync.Mutex
+	users    map[string]bool
+	waiting  []*session
+	games    uint64
+	listener net.Listener
+	closed   bool
+}
+
+// seat is a place at a table handed to a waiting session
+type seat struct {
+	table *tui.Table
+	index int
+}
+
+// New creates a server identifying itself with the host key
+func New(cfg Config, hostKey ssh.Signer) (*Server, error) {
+	if cfg.Bot == "" {
+		cfg.Bot = "greedy"
+	}
+	if _, err := bot.ByName(cfg.Bot, 0); err != nil {
+		return nil, err
+	}
+
+	s := &Server{cfg: cfg, users: make(map[string]bool)}
+	s.config = &ssh.ServerConfig{NoClientAuth: true}
+	s.config.AddHostKey(hostKey)
+	return s, nil
+}
+
+// Serve accepts connections until the listener fails or the server is closed
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	s.listener = l
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// Close stops accepting connections, sessions already running go on
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Close()
+}
+
+// handle runs one SSH connection, which usually opens a single session
+func (s *Server) handle(nc net.Conn) {
+	defer nc.Close()
+
+	conn, channels, requests, err := ssh.NewServerConn(nc, s.config)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.serveSession(conn.User(), channel, requests)
+	}
+}
+
+// serveSession answers the session's requests and runs the lobby once a shell is asked for
+func (s *Server) serveSession(user string, channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+
+	t := term.NewTerminal(channel, "> ")
+	shell := make(chan struct{})
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		started := false
+		for req := range requests {
+			ok := false
+			switch req.Type {
+			case "pty-req":
+				if width, height, parsed := parsePtyRequest(req.Payload); parsed {
+					t.SetSize(width, height)
+				}
+				ok = true
+			case "window-change":
+				if width, height, parsed := parseWindowChange(req.Payload); parsed {
+					t.SetSize(width, height)
+				}
+				ok = true
+			case "shell":
+				ok = !started
+				if !started {
+					started = true
+					close(shell)
+				}
+			}
+			if req.WantReply {
+				req.Reply(ok, nil)
+			}
+		}
+	}()
+
+	select {
+	case <-shell:
+	case <-closed:
+		return
+	}
+
+	name := s.join(user)
+	defer s.leave(name)
+
+	sess := newSession(name, t)
+	sess.run(s)
+	channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+}
+
+// join registers a user in the lobby, numbering names already taken
+func (s *Server) join(user string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := cleanName(user)
+	if name == "" {
+		name = "guest"
+	}
+	unique := name
+	for i := 2; s.users[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	s.users[unique] = true
+	return unique
+}
+
+// maxNameLength caps the runes of a name shown to the other users
+const maxNameLength = 16
+
+// cleanName keeps the printable runes of an SSH user name, dropping bytes that are not
+// UTF-8, so that nobody can send escape sequences to other terminals through their name
+func cleanName(user string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, user)
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = strings.TrimSpace(string(runes[:maxNameLength]))
+	}
+	return name
+}
+
+// leave removes a user from the lobby
+func (s *Server) leave(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.users, name)
+}
+
+// who lists the users in the lobby and in games
+func (s *Server) who() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.users))
+	for name := range s.users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// players returns how many people a game takes
+func (s *Server) players() int {
+	if s.cfg.Options.Teams {
+		return 4
+	}
+	return 2
+}
+
+// nextSeed returns the seed of the next game
+func (s *Server) nextSeed() uint64 {
+	s.games++
+	return s.cfg.Seed + s.games - 1
+}
+
+// enqueue adds the session to the people waiting for a game, and deals a game once
+// enough are waiting. Returns how many more players the game needs
+func (s *Server) enqueue(sess *session) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.waiting = append(s.waiting, sess)
+	if len(s.waiting) < s.players() {
+		return s.players() - len(s.waiting), nil
+	}
+
+	sessions := s.waiting[:s.players()]
+	s.waiting = s.waiting[s.players():]
+
+	names := make([]string, len(sessions))
+	for i, waiting := range sessions {
+		names[i] = waiting.name
+	}
+	table, err := tui.NewTable(names, make([]string, len(names)), s.cfg.Options, s.nextSeed())
+	if err != nil {
+		return 0, err
+	}
+
+	for i, waiting := range sessions {
+		waiting.seats <- seat{table: table, index: i}
+	}
+	return 0, nil
+}
+
+// cancel takes the session off the waiting list, false if it was seated already
+func (s *Server) cancel(sess *session) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, waiting := range s.waiting {
+		if waiting == sess {
+			s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// botGame deals a game between the person and bots filling the other seats
+func (s *Server) botGame(name string) (*tui.Table, error) {
+	s.mu.Lock()
+	seed := s.nextSeed()
+	s.mu.Unlock()
+
+	names := make([]string, s.players())
+	seats := make([]string, s.players())
+	names[0] = name
+	for i := 1; i < len(seats); i++ {
+		seats[i] = s.cfg.Bot
+	}
+	return tui.NewTable(names, seats, s.cfg.Options, seed)
+}
+
+const lobbyHelp = `Commands:
+  play   wait for other people to play with
+  bot    play a bot right away
+  who    list the people connected
+  quit   leave`
+
+// session is one person connected to the server
+type session struct {
+	name  string
+	term  *term.Terminal
+	seats chan seat
+
+	// lines and done are closed once reading fails, with err telling why
+	lines chan string
+	done  chan struct{}
+	err   error
+}
+
+func newSession(name string, t *term.Terminal) *session {
+	sess := &session{name: name, term: t, lines: make(chan string), done: make(chan struct{}), seats: make(chan seat, 1)}
+
+	// Lines are read in one place so waiting for a game can also watch the keyboard
+	go func() {
+		for {
+			text, err := t.ReadLine()
+			if err != nil {
+				sess.err = err
+				close(sess.done)
+				close(sess.lines)
+				return
+			}
+			sess.lines <- text
+		}
+	}()
+	return sess
+}
+
+// ReadLine reads the next line, so the session can serve as the UI's terminal
+// Ctrl-C and Ctrl-D end the connection, as they would a shell
+func (sess *session) ReadLine() (string, error) {
+	text, ok := <-sess.lines
+	if !ok {
+		return "", sess.err
+	}
+	return text, nil
+}
+
+// ReadLineOrCancel reads the next line like ReadLine, or returns an empty one once
+// cancel is closed, so the UI can leave a game someone else ended
+func (sess *session) ReadLineOrCancel(cancel <-chan struct{}) (string, error) {
+	select {
+	case text, ok := <-sess.lines:
+		if !ok {
+			return "", sess.err
+		}
+		return text, nil
+	case <-cancel:
+		return "", nil
+	}
+}
+
+// ended reports whether the connection ended
+func (sess *session) ended() bool {
+	select {
+	case <-sess.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (sess *session) Write(p []byte) (int, error) {
+	return sess.term.Write(p)
+}
+
+func (sess *session) printf(format string, args ...any) {
+	fmt.Fprintf(sess, format, args...)
+}
+
+// run shows the lobby until the person leaves
+func (sess *session) run(s *Server) {
+	sess.printf("Welcome to UNO, %s!\n%s\n", sess.name, lobbyHelp)
+
+	for {
+		text, err := sess.ReadLine()
+		if err != nil {
+			s.cancel(sess)
+			return
+		}
+
+		switch strings.ToLower(strings.TrimSpace(text)) {
+		case "":
+		case "play":
+			if err := sess.waitAndPlay(s); errors.Is(err, io.EOF) {
+				return
+			} else if err != nil {
+				sess.printf("%v\n", err)
+			}
+		case "bot":
+			table, err := s.botGame(sess.name)
+			if err != nil {
+				sess.printf("%v\n", err)
+				continue
+			}
+			if err := sess.play(s, seat{table: table}); errors.Is(err, io.EOF) {
+				return
+			} else if err != nil {
+				sess.printf("%v\n", err)
+			}
+		case "who":
+			sess.printf("Connected: %s\n", strings.Join(s.who(), ", "))
+		case "quit", "exit", "q":
+			sess.printf("Bye!\n")
+			return
+		default:
+			sess.printf("Unknown command %q\n%s\n", text, lobbyHelp)
+		}
+	}
+}
+
+// waitAndPlay waits for enough people to start a game and plays it
+// Pressing Enter while waiting goes back to the lobby
+func (sess *session) waitAndPlay(s *Server) error {
+	missing, err := s.enqueue(sess)
+	if err != nil {
+		return err
+	}
+	if missing > 0 {
+		sess.printf("Waiting for %d more player(s), press Enter to stop waiting\n", missing)
+	}
+
+	select {
+	case seated := <-sess.seats:
+		return sess.play(s, seated)
+	case _, ok := <-sess.lines:
+		if s.cancel(sess) {
+			if !ok {
+				return io.EOF
+			}
+			sess.printf("Stopped waiting\n")
+			return nil
+		}
+
+		// Seated just as the line came in, the line is dropped
+		return sess.play(s, <-sess.seats)
+	}
+}
+
+// play runs the UI for the seat, a bot takes over if the person leaves early
+// Returns io.EOF if the connection ended
+func (sess *session) play(s *Server, seated seat) error {
+	err := tui.New(seated.table.Seat(seated.index), sess, true).Run()
+	if !seated.table.Over() {
+		seated.table.Leave(seated.index, sess.stand(s, seated.index))
+	}
+	if err != nil {
+		return err
+	}
+	if sess.ended() {
+		return io.EOF
+	}
+
+	sess.printf("\nBack in the lobby.\n%s\n", lobbyHelp)
+	return nil
+}
+
+// stand creates the bot taking over a seat
+func (sess *session) stand(s *Server, index int) bot.Bot {
+	b, _ := bot.ByName(s.cfg.Bot, s.cfg.Seed+uint64(index))
+	return b
+}
+
+// parsePtyRequest reads the terminal size from a pty-req payload
+func parsePtyRequest(payload []byte) (int, int, bool) {
+	var req struct {
+		Term          string
+		Columns, Rows uint32
+		Width, Height uint32
+		Modes         string
+	}
+	if err := ssh.Unmarshal(payload, &req); err != nil {
+		return 0, 0, false
+	}
+	return int(req.Columns), int(req.Rows), true
+}
+
+// parseWindowChange reads the terminal size from a window-change payload
+func parseWindowChange(payload []byte) (int, int, bool) {
+	var req struct {
+		Columns, Rows uint32
+		Width, Height uint32
+	}
+	if err := ssh.Unmarshal(payload, &req); err != nil {
+		return 0, 0, false
+	}
+	return int(req.Columns), int(req.Rows), true
+}
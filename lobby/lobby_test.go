@@ -0,0 +1,243 @@
+package lobby
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// client is a person connected over SSH with a terminal
+type client struct {
+	t       *testing.T
+	session *ssh.Session
+	stdin   io.WriteCloser
+
+	mu     sync.Mutex
+	output bytes.Buffer
+	read   int
+}
+
+func startServer(t *testing.T, cfg Config) string {
+	t.Helper()
+
+	key, err := LoadHostKey(t.TempDir() + "/host_key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := New(cfg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+	return l.Addr().String()
+}
+
+func connect(t *testing.T, addr, user string) *client {
+	t.Helper()
+
+	conn, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User:            user,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	session, err := conn.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &client{t: t, session: session}
+	if c.stdin, err = session.StdinPipe(); err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := stdout.Read(buf)
+			c.mu.Lock()
+			c.output.Write(buf[:n])
+			c.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := session.RequestPty("xterm", 40, 120, ssh.TerminalModes{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.Shell(); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// send types a line
+func (c *client) send(line string) {
+	c.t.Helper()
+	if _, err := io.WriteString(c.stdin, line+"\r"); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// expect waits for text to show up in the output after what was already expected
+func (c *client) expect(text string) {
+	c.t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		output := c.output.String()[c.read:]
+		if i := strings.Index(output, text); i >= 0 {
+			c.read += i + len(text)
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t.Fatalf("Expected %q in output, got %q", text, c.output.String()[c.read:])
+}
+
+func TestPlayEachOther(t *testing.T) {
+	addr := startServer(t, Config{Seed: 1})
+
+	alice := connect(t, addr, "alice")
+	alice.expect("Welcome to UNO, alice!")
+	bob := connect(t, addr, "bob")
+	bob.expect("Welcome to UNO, bob!")
+
+	alice.send("who")
+	alice.expect("Connected: alice, bob")
+
+	alice.send("play")
+	alice.expect("Waiting for 1 more player(s)")
+	bob.send("play")
+
+	alice.expect("UNO  alice vs bob")
+	bob.expect("UNO  alice vs bob")
+
+	// Both see the moves made on the shared table
+	alice.expect("alice (you)")
+	bob.expect("bob (you)")
+	alice.send("d")
+	alice.send("q")
+	alice.expect("Back in the lobby")
+
+	// Alice's seat goes to a bot, Bob plays on until leaving too
+	bob.expect("alice drew a card")
+	bob.send("q")
+	bob.expect("Back in the lobby")
+
+	bob.send("quit")
+	bob.expect("Bye!")
+	if err := bob.session.Wait(); err != nil {
+		t.Errorf("Expected the session to end cleanly, got %v", err)
+	}
+}
+
+func TestPlayBot(t *testing.T) {
+	addr := startServer(t, Config{Options: game.GameOptions{Teams: true}, Bot: "random"})
+
+	c := connect(t, addr, "carol")
+	c.expect("Welcome to UNO, carol!")
+	c.send("bot")
+	c.expect("UNO  carol vs random")
+	c.expect("(partner)")
+	c.send("q")
+	c.expect("Back in the lobby")
+}
+
+func TestStopWaiting(t *testing.T) {
+	addr := startServer(t, Config{})
+
+	c := connect(t, addr, "dave")
+	c.expect("Welcome to UNO, dave!")
+	c.send("play")
+	c.expect("Waiting for 1 more player(s)")
+	c.send("")
+	c.expect("Stopped waiting")
+
+	// Dave is no longer waiting, so the next person waits alone
+	e := connect(t, addr, "erin")
+	e.expect("Welcome to UNO, erin!")
+	e.send("play")
+	e.expect("Waiting for 1 more player(s)")
+}
+
+func TestSameName(t *testing.T) {
+	addr := startServer(t, Config{})
+
+	first := connect(t, addr, "frank")
+	first.expect("Welcome to UNO, frank!")
+	second := connect(t, addr, "frank")
+	second.expect("Welcome to UNO, frank2!")
+}
+
+func TestCleanName(t *testing.T) {
+	cases := map[string]string{
+		"\x1b[2Jmallory":            "[2Jmallory",
+		"  grace\r\n":               "grace",
+		"heidi\x07\x00":             "heidi",
+		"\x1b\x9b\u009b":            "",
+		"ivan ivanovich ivanov the": "ivan ivanovich i",
+		"zoë":                       "zoë",
+	}
+	for user, expected := range cases {
+		if name := cleanName(user); name != expected {
+			t.Errorf("Expected %q to become %q, got %q", user, expected, name)
+		}
+	}
+}
+
+// Test that a name cannot clear the screens of the other users
+func TestEscapeName(t *testing.T) {
+	addr := startServer(t, Config{})
+
+	mallory := connect(t, addr, "\x1b[2Jmallory")
+	mallory.expect("Welcome to UNO, [2Jmallory!")
+
+	judy := connect(t, addr, "judy")
+	judy.expect("Welcome to UNO, judy!")
+	judy.send("who")
+	judy.expect("[2Jmallory")
+
+	judy.mu.Lock()
+	defer judy.mu.Unlock()
+	if strings.Contains(judy.output.String(), "\x1b[2J") {
+		t.Error("Expected the escape sequence to be stripped from the name")
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	key, err := LoadHostKey(t.TempDir() + "/host_key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(Config{Bot: "nobody"}, key); err == nil {
+		t.Error("Expected error for an unknown bot")
+	}
+}
@@ -1,19 +1,29 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"os"
+	"time"
+
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/audio"
 )
 
-type Game struct {}
+// Game runs the window, showing the screens of the screen manager
+type Game struct {
+	screens *ScreenManager
+}
 
 func (g *Game) Update() error {
-	return nil
+	if ebiten.IsFullscreen() != g.screens.Settings.Fullscreen {
+		ebiten.SetFullscreen(g.screens.Settings.Fullscreen)
+	}
+	return g.screens.Update(ebitenInput{})
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
-	ebitenutil.DebugPrint(screen, "Uno Clone")
+	g.screens.Draw(screen)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
@@ -21,13 +31,49 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeigh
 }
 
 func main() {
+	if err := runWindow(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runWindow opens the game window
+// Usage: uno-clone -assets .
+func runWindow(args []string) error {
+	flags := flag.NewFlagSet("uno-clone", flag.ContinueOnError)
+	assets := flags.String("assets", "", "folder whose assets directory replaces files of the built in one")
+	themes := flags.String("themes", "", "folder of theme packs to choose from in Settings, besides those built in")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	resources, err := LoadResources(assetFS(*assets))
+	if err != nil {
+		return err
+	}
+	// A broken theme pack should not keep the game from starting
+	if resources.Themes, err = LoadThemes(themeDirs(*themes)...); err != nil {
+		log.Print(err)
+	}
+
 	ebiten.SetWindowSize(ScreenWidth, ScreenHeight)
 	ebiten.SetWindowTitle(WindowTitle)
 	ebiten.SetTPS(TPS)
 
-	game := &Game{}
-	if err := ebiten.RunGame(game); err != nil {
-		log.Fatal(err)
+	game := &Game{screens: NewScreenManager(Settings{PlayerName: defaultName(), Opponent: "greedy", Sound: true})}
+	game.screens.Seed = uint64(time.Now().UnixNano())
+	game.screens.Resources = resources
+	if game.screens.Sounds, err = NewSoundBoard(audio.NewContext(sampleRate), resources.Sounds); err != nil {
+		return err
 	}
+	return ebiten.RunGame(game)
 }
 
+// defaultName returns the user's login name, which is what people go by on shared machines
+func defaultName() string {
+	for _, key := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return name
+		}
+	}
+	return "You"
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+
+	"github.com/vtigo/uno-clone/cardart"
+)
+
+// embeddedAssets are built into the binary, so a release is a single file
+//
+//go:embed assets
+var embeddedAssets embed.FS
+
+// soundNames are the sound effects the game plays, each read from a WAV file in AudioPath
+var soundNames = []string{"play", "draw", "special", "uno", "invalid", "win"}
+
+// Sample rate of the audio context and format of the generated placeholder sounds
+const (
+	sampleRate    = 44100
+	toneFrequency = 440 // Hz
+	toneLength    = 0.1 // Seconds
+)
+
+// Resources hold everything the game loads from the assets folder
+type Resources struct {
+	Cards *CardAtlas
+
+	// Font holds the TrueType data of FontPath, nil when there is none and text
+	// is drawn in Ebiten's debug font. The font in use draws all text, see face
+	Font []byte
+
+	// Sounds hold WAV data by name, a short beep standing in for any missing file
+	Sounds map[string][]byte
+
+	// Themes are the packs to choose from, Theme the one in use or nil for the built in look
+	Themes []*Theme
+	Theme  *Theme
+
+	cardImages fs.FS  // Built in card images, under those of the theme
+	font       []byte // Built in font, for themes without one
+}
+
+// LoadResources loads the assets of fsys, generating placeholders for missing files
+func LoadResources(fsys fs.FS) (*Resources, error) {
+	cardImages, err := fs.Sub(fsys, path.Clean(CardImgPath))
+	if err != nil {
+		return nil, err
+	}
+	cards, err := NewCardAtlas(cardart.Palette, cardImages)
+	if err != nil {
+		return nil, err
+	}
+	r := &Resources{Cards: cards, Sounds: make(map[string][]byte), cardImages: cardImages}
+
+	if r.font, err = readOptional(fsys, FontPath); err != nil {
+		return nil, err
+	}
+	if err := r.useFont(r.font); err != nil {
+		return nil, fmt.Errorf("font %s: %v", FontPath, err)
+	}
+
+	for _, name := range soundNames {
+		data, err := readOptional(fsys, path.Join(AudioPath, name+".wav"))
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			data = toneWAV(toneFrequency, toneLength)
+		}
+		r.Sounds[name] = data
+	}
+	return r, nil
+}
+
+// UseTheme switches to the cards, colors and font of the theme, nil going back to
+// the built in look
+func (r *Resources) UseTheme(theme *Theme) error {
+	sources := []fs.FS{r.cardImages}
+	colors, shades, font := defaultPalette, cardart.Palette, r.font
+	if theme != nil {
+		sources = append([]fs.FS{theme.Cards}, sources...)
+		colors, shades = theme.Palette, theme.CardColors
+		if theme.Font != nil {
+			font = theme.Font
+		}
+	}
+
+	cards, err := NewCardAtlas(shades, sources...)
+	if err != nil {
+		return err
+	}
+	if err := r.useFont(font); err != nil {
+		return fmt.Errorf("theme font: %v", err)
+	}
+	r.Cards, r.Theme = cards, theme
+	palette, cardColors = colors, shades
+	return nil
+}
+
+// useFont switches all text to the font, nil data going back to Ebiten's debug font
+func (r *Resources) useFont(data []byte) error {
+	f, err := newFace(data)
+	if err != nil {
+		return err
+	}
+	r.Font, face = data, f
+	return nil
+}
+
+// readOptional reads a file, returning nil data without an error when it does not exist
+func readOptional(fsys fs.FS, name string) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// assetFS returns the built in assets, overridden by the files of dir/assets when
+// dir is set so new art shows without rebuilding
+func assetFS(dir string) fs.FS {
+	if dir == "" {
+		return embeddedAssets
+	}
+	return overlayFS{top: os.DirFS(dir), bottom: embeddedAssets}
+}
+
+// overlayFS opens files from top, falling back to bottom for the files top lacks
+type overlayFS struct {
+	top, bottom fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.top.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.bottom.Open(name)
+	}
+	return f, err
+}
+
+// toneWAV generates a sine tone as 16 bit mono WAV data, fading out to avoid a click
+func toneWAV(frequency, seconds float64) []byte {
+	samples := int(sampleRate * seconds)
+
+	var buf bytes.Buffer
+	header := []any{
+		[]byte("RIFF"), uint32(36 + 2*samples), []byte("WAVE"),
+		[]byte("fmt "), uint32(16), uint16(1), uint16(1), uint32(sampleRate), uint32(2 * sampleRate), uint16(2), uint16(16),
+		[]byte("data"), uint32(2 * samples),
+	}
+	for _, field := range header {
+		binary.Write(&buf, binary.LittleEndian, field)
+	}
+
+	for i := range samples {
+		fade := 1 - float64(i)/float64(samples)
+		sample := math.Sin(2*math.Pi*frequency*float64(i)/sampleRate) * fade * math.MaxInt16 / 4
+		binary.Write(&buf, binary.LittleEndian, int16(sample))
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/vtigo/uno-clone/cardart"
+	"github.com/vtigo/uno-clone/game"
+)
+
+func TestLoadResources(t *testing.T) {
+	r, err := LoadResources(embeddedAssets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, card := range cardart.Cards() {
+		if r.Cards.Face(card) == r.Cards.Back() {
+			t.Errorf("Expected a face for %v", card)
+		}
+	}
+	if r.Cards.Face(game.Card{Color: game.Red, Type: game.WildCard}) != r.Cards.Back() {
+		t.Error("Expected the back for a card without a face")
+	}
+
+	// Without the files, every sound is a placeholder
+	for _, name := range soundNames {
+		if !bytes.HasPrefix(r.Sounds[name], []byte("RIFF")) {
+			t.Errorf("Expected WAV data for %s", name)
+		}
+	}
+}
+
+func TestOverlayFS(t *testing.T) {
+	t.Cleanup(func() { face = nil })
+
+	top := fstest.MapFS{"assets/fonts/main.ttf": {Data: goregular.TTF}}
+	bottom := fstest.MapFS{
+		"assets/fonts/main.ttf": {Data: []byte("built in")},
+		"assets/audio/uno.wav":  {Data: []byte("uno")},
+	}
+	fsys := overlayFS{top: top, bottom: bottom}
+
+	if data, _ := fs.ReadFile(fsys, "assets/fonts/main.ttf"); !bytes.Equal(data, goregular.TTF) {
+		t.Errorf("Expected the file on top, got %q", data)
+	}
+	if data, _ := fs.ReadFile(fsys, "assets/audio/uno.wav"); string(data) != "uno" {
+		t.Errorf("Expected the file underneath, got %q", data)
+	}
+
+	r, err := LoadResources(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r.Font, goregular.TTF) || face == nil || string(r.Sounds["uno"]) != "uno" {
+		t.Errorf("Expected the font and sound files loaded, got %d font bytes and %q", len(r.Font), r.Sounds["uno"])
+	}
+
+	// Text is measured in the font, not the debug font
+	if width := textWidth("UNO"); width == 3*glyphWidth || width == 0 {
+		t.Errorf("Expected the width of the text in the font, got %d", width)
+	}
+
+	broken := fstest.MapFS{"assets/fonts/main.ttf": {Data: []byte("not a font")}}
+	if _, err := LoadResources(broken); err == nil {
+		t.Error("Expected error for a font that is not TrueType")
+	}
+}
+
+func TestToneWAV(t *testing.T) {
+	data := toneWAV(toneFrequency, 0.5)
+	if len(data) != 44+2*sampleRate/2 {
+		t.Errorf("Expected a header and half a second of samples, got %d bytes", len(data))
+	}
+	if string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		t.Errorf("Expected a WAV header, got %q", data[:12])
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// transitionTicks is how long a screen takes to fade out, and the next to fade in
+const transitionTicks = TPS / 4
+
+// Screen is one screen of the game, like the title or the table
+type Screen interface {
+	// Update handles a tick of input, an error ends the game
+	Update(in Input) error
+	Draw(dst *ebiten.Image)
+
+	// OnEnter and OnExit are called as the screen comes to the top of the stack
+	// and as it leaves it, covered by another screen or closed
+	OnEnter()
+	OnExit()
+}
+
+// Settings are the player's choices, shared by every screen
+type Settings struct {
+	PlayerName string
+	Opponent   string // Bot playing the other seat
+	Sound      bool
+	Fullscreen bool
+	Theme      string // Name of the theme pack, empty for the built in look
+}
+
+// GameResult is the outcome of the last game, for the results screen
+type GameResult struct {
+	Names     []string
+	Winner    int   // Seat of the winner, -1 if the game was abandoned
+	CardsLeft []int // Cards in every hand at the end
+	Duration  time.Duration
+}
+
+// ScreenManager keeps a stack of screens, the top one being shown, and fades
+// between them as they change
+type ScreenManager struct {
+	Settings  Settings
+	Result    *GameResult
+	Seed      uint64 // Deals the next game, counting up with every game
+	Resources *Resources
+	Sounds    SoundPlayer // Plays the sound effects, nil for none
+
+	screens map[int]func(m *ScreenManager) Screen
+	stack   []stackEntry
+
+	// change is the stack change waiting for the current screen to fade out
+	change func()
+	fade   int // Ticks into the transition, 0 when there is none
+}
+
+type stackEntry struct {
+	state  int
+	screen Screen
+}
+
+// NewScreenManager creates a manager showing the title screen
+func NewScreenManager(settings Settings) *ScreenManager {
+	m := &ScreenManager{
+		Settings: settings,
+		screens: map[int]func(m *ScreenManager) Screen{
+			StateTitle:        newTitleScreen,
+			StateRules:        newRulesScreen,
+			StateSettings:     newSettingsScreen,
+			StateNetworkSetup: newNetworkSetupScreen,
+			StateGameplay:     newGameplayScreen,
+			StateResults:      newResultsScreen,
+		},
+	}
+	m.push(StateTitle)
+	return m
+}
+
+// playSound plays the named sound effect, unless sound is off in the settings
+func (m *ScreenManager) playSound(name string) {
+	if m.Settings.Sound && m.Sounds != nil {
+		m.Sounds.Play(name)
+	}
+}
+
+// State returns the state of the screen on top
+func (m *ScreenManager) State() int {
+	return m.stack[len(m.stack)-1].state
+}
+
+// Top returns the screen shown
+func (m *ScreenManager) Top() Screen {
+	return m.stack[len(m.stack)-1].screen
+}
+
+// Transitioning reports whether the screens are fading, which ignores input
+func (m *ScreenManager) Transitioning() bool {
+	return m.fade > 0
+}
+
+// Push opens the screen for the state over the current one
+func (m *ScreenManager) Push(state int) {
+	m.transition(func() {
+		m.Top().OnExit()
+		m.push(state)
+	})
+}
+
+// Pop closes the current screen, going back to the one under it
+func (m *ScreenManager) Pop() {
+	if len(m.stack) < 2 {
+		return
+	}
+	m.transition(func() {
+		m.Top().OnExit()
+		m.stack = m.stack[:len(m.stack)-1]
+		m.Top().OnEnter()
+	})
+}
+
+// Switch closes every screen and opens the one for the state
+func (m *ScreenManager) Switch(state int) {
+	m.transition(func() {
+		m.Top().OnExit()
+		m.stack = m.stack[:0]
+		m.push(state)
+	})
+}
+
+// transition fades out to make the change, a change asked for during a
+// transition is dropped so a double click does not skip a screen
+func (m *ScreenManager) transition(change func()) {
+	if m.Transitioning() {
+		return
+	}
+	m.change = change
+	m.fade = 1
+}
+
+func (m *ScreenManager) push(state int) {
+	screen := m.screens[state](m)
+	m.stack = append(m.stack, stackEntry{state: state, screen: screen})
+	screen.OnEnter()
+}
+
+// Update advances the transition or passes the input to the screen on top
+func (m *ScreenManager) Update(in Input) error {
+	if !m.Transitioning() {
+		return m.Top().Update(in)
+	}
+
+	if m.fade == transitionTicks {
+		m.change()
+		m.change = nil
+	}
+	m.fade++
+	if m.fade > 2*transitionTicks {
+		m.fade = 0
+	}
+	return nil
+}
+
+// Draw draws the screen on top, darkened while fading
+func (m *ScreenManager) Draw(dst *ebiten.Image) {
+	m.Top().Draw(dst)
+
+	if !m.Transitioning() {
+		return
+	}
+	// Darkest at the change, in the middle of the transition
+	progress := float64(m.fade) / transitionTicks
+	if progress > 1 {
+		progress = 2 - progress
+	}
+	bounds := dst.Bounds()
+	vector.DrawFilledRect(dst, 0, 0, float32(bounds.Dx()), float32(bounds.Dy()), color.RGBA{A: uint8(255 * progress)}, false)
+}
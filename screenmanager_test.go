@@ -0,0 +1,127 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// recordingScreen notes the calls it gets
+type recordingScreen struct {
+	name  string
+	calls *[]string
+}
+
+func (s *recordingScreen) Update(Input) error {
+	*s.calls = append(*s.calls, s.name+".Update")
+	return nil
+}
+func (s *recordingScreen) Draw(*ebiten.Image) {}
+func (s *recordingScreen) OnEnter()           { *s.calls = append(*s.calls, s.name+".OnEnter") }
+func (s *recordingScreen) OnExit()            { *s.calls = append(*s.calls, s.name+".OnExit") }
+
+func recordingManager(calls *[]string) *ScreenManager {
+	m := &ScreenManager{screens: map[int]func(*ScreenManager) Screen{}}
+	for state, name := range map[int]string{StateTitle: "title", StateRules: "rules", StateGameplay: "gameplay"} {
+		m.screens[state] = func(*ScreenManager) Screen { return &recordingScreen{name: name, calls: calls} }
+	}
+	m.push(StateTitle)
+	return m
+}
+
+// settle ticks until the transition is over
+func settle(t *testing.T, m *ScreenManager) {
+	t.Helper()
+	for ticks := 0; m.Transitioning(); ticks++ {
+		if ticks > 2*transitionTicks {
+			t.Fatal("Expected the transition to end")
+		}
+		m.Update(&fakeInput{})
+	}
+}
+
+func TestScreenStack(t *testing.T) {
+	var calls []string
+	m := recordingManager(&calls)
+
+	m.Push(StateRules)
+	settle(t, m)
+	if m.State() != StateRules || len(m.stack) != 2 {
+		t.Errorf("Expected rules over the title, got %v", m.stack)
+	}
+
+	m.Pop()
+	settle(t, m)
+	if m.State() != StateTitle || len(m.stack) != 1 {
+		t.Errorf("Expected the title alone, got %v", m.stack)
+	}
+
+	// Popping the last screen does nothing
+	m.Pop()
+	if m.Transitioning() {
+		t.Error("Expected no transition popping the last screen")
+	}
+
+	m.Push(StateRules)
+	settle(t, m)
+	m.Switch(StateGameplay)
+	settle(t, m)
+	if m.State() != StateGameplay || len(m.stack) != 1 {
+		t.Errorf("Expected only the gameplay screen, got %v", m.stack)
+	}
+
+	expected := []string{
+		"title.OnEnter",
+		"title.OnExit", "rules.OnEnter",
+		"rules.OnExit", "title.OnEnter",
+		"title.OnExit", "rules.OnEnter",
+		"rules.OnExit", "gameplay.OnEnter",
+	}
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Expected call %d to be %s, got %s", i, expected[i], calls[i])
+		}
+	}
+}
+
+func TestTransition(t *testing.T) {
+	var calls []string
+	m := recordingManager(&calls)
+	m.Update(&fakeInput{})
+
+	m.Push(StateRules)
+	if !m.Transitioning() {
+		t.Fatal("Expected a transition")
+	}
+
+	// A second change during the transition is dropped
+	m.Switch(StateGameplay)
+
+	calls = calls[:0]
+	ticks := 0
+	for ; m.Transitioning(); ticks++ {
+		m.Update(&fakeInput{})
+	}
+	if ticks != 2*transitionTicks {
+		t.Errorf("Expected the transition to take %d ticks, got %d", 2*transitionTicks, ticks)
+	}
+	if m.State() != StateRules {
+		t.Errorf("Expected the rules screen, got %d", m.State())
+	}
+
+	// Screens get no input while fading
+	for _, call := range calls {
+		if call == "title.Update" || call == "rules.Update" {
+			t.Errorf("Expected no updates during the transition, got %v", calls)
+			break
+		}
+	}
+
+	m.Update(&fakeInput{})
+	if calls[len(calls)-1] != "rules.Update" {
+		t.Errorf("Expected the rules screen to update after the transition, got %v", calls)
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/vtigo/uno-clone/bot"
+)
+
+// titleScreen is the main menu
+type titleScreen struct {
+	m      *ScreenManager
+	layout []*button
+}
+
+func newTitleScreen(m *ScreenManager) Screen {
+	s := &titleScreen{m: m}
+	s.layout = buttonColumn(ScreenHeight/2-60,
+		&button{label: "Play Game", onClick: func() error { m.Push(StateNetworkSetup); return nil }},
+		&button{label: "Rules", onClick: func() error { m.Push(StateRules); return nil }},
+		&button{label: "Settings", onClick: func() error { m.Push(StateSettings); return nil }},
+		&button{label: "Exit", onClick: func() error { return ebiten.Termination }},
+	)
+	return s
+}
+
+func (s *titleScreen) buttons() []*button { return s.layout }
+func (s *titleScreen) OnEnter()           {}
+func (s *titleScreen) OnExit()            {}
+
+func (s *titleScreen) Update(in Input) error {
+	return updateButtons(in, s.layout)
+}
+
+func (s *titleScreen) Draw(dst *ebiten.Image) {
+	dst.Fill(palette.Background)
+	drawHeading(dst, WindowTitle, ScreenHeight/2-160)
+	drawHeading(dst, "Welcome, "+s.m.Settings.PlayerName, ScreenHeight/2-120)
+	drawButtons(dst, s.layout)
+}
+
+// rulesText explains the game on the rules screen
+var rulesText = strings.Split(`Each player starts with 7 cards. On your turn, play a card matching the
+top of the discard pile by color, number or symbol, or play a wild card.
+If you cannot or do not want to play, draw a card. You may play the card
+you drew, or pass.
+
+Skip           The other player misses their turn, so you play again
+Reverse        With two players it works like Skip, you play again
+Draw Two       The other player draws 2 cards and misses their turn
+Wild           Choose the color to play next
+Wild Draw Four Choose the color, the other player draws 4 cards and
+               misses their turn. Only play it without a card of the
+               active color
+
+Call UNO when you play your second to last card. If the other player
+catches you before you call it, you draw 2 cards.
+
+The first player to play all their cards wins.`, "\n")
+
+// Rules text layout
+const (
+	rulesTop     = 100
+	rulesLines   = 24 // Lines shown at once
+	rulesLeading = glyphHeight + 4
+)
+
+// rulesScreen shows the rules, scrolling with the wheel or arrow keys
+type rulesScreen struct {
+	m      *ScreenManager
+	layout []*button
+	scroll int // First line shown
+}
+
+func newRulesScreen(m *ScreenManager) Screen {
+	s := &rulesScreen{m: m}
+	s.layout = buttonColumn(ScreenHeight-buttonHeight-40,
+		&button{label: "Back", onClick: func() error { m.Pop(); return nil }},
+	)
+	return s
+}
+
+func (s *rulesScreen) buttons() []*button { return s.layout }
+func (s *rulesScreen) OnEnter()           { s.scroll = 0 }
+func (s *rulesScreen) OnExit()            {}
+
+func (s *rulesScreen) Update(in Input) error {
+	if in.KeyPressed(ebiten.KeyEscape) {
+		s.m.Pop()
+		return nil
+	}
+
+	switch {
+	case in.Wheel() < 0 || in.KeyPressed(ebiten.KeyDown):
+		s.scroll++
+	case in.Wheel() > 0 || in.KeyPressed(ebiten.KeyUp):
+		s.scroll--
+	}
+	s.scroll = max(0, min(s.scroll, len(rulesText)-rulesLines))
+	return updateButtons(in, s.layout)
+}
+
+func (s *rulesScreen) Draw(dst *ebiten.Image) {
+	dst.Fill(palette.Background)
+	drawHeading(dst, "Rules", 50)
+
+	x := (ScreenWidth - 72*glyphWidth) / 2
+	for i, line := range s.visible() {
+		drawText(dst, line, x, rulesTop+i*rulesLeading)
+	}
+	drawButtons(dst, s.layout)
+}
+
+// visible returns the lines shown at the scroll position
+func (s *rulesScreen) visible() []string {
+	end := min(s.scroll+rulesLines, len(rulesText))
+	return rulesText[s.scroll:end]
+}
+
+// maxNameLength keeps names short enough for the table
+const maxNameLength = 16
+
+// settingsScreen edits the player's name, toggles sound and full screen and picks the theme
+type settingsScreen struct {
+	m       *ScreenManager
+	name    *button // Clicking the name edits it
+	sound   *button
+	screen  *button
+	theme   *button
+	layout  []*button
+	editing bool
+}
+
+func newSettingsScreen(m *ScreenManager) Screen {
+	s := &settingsScreen{m: m}
+	s.name = &button{onClick: func() error { s.editing = true; return nil }}
+	s.sound = &button{onClick: func() error { m.Settings.Sound = !m.Settings.Sound; return nil }}
+	s.screen = &button{onClick: func() error { m.Settings.Fullscreen = !m.Settings.Fullscreen; return nil }}
+	s.theme = &button{onClick: s.nextTheme}
+	s.layout = buttonColumn(ScreenHeight/2-130,
+		s.name,
+		s.sound,
+		s.screen,
+		s.theme,
+		&button{label: "Back", onClick: func() error { m.Pop(); return nil }},
+	)
+	s.label()
+	return s
+}
+
+func (s *settingsScreen) buttons() []*button { return s.layout }
+func (s *settingsScreen) OnEnter()           {}
+func (s *settingsScreen) OnExit()            { s.finishEditing() }
+
+func (s *settingsScreen) Update(in Input) error {
+	if s.editing {
+		s.edit(in)
+	} else if in.KeyPressed(ebiten.KeyEscape) {
+		s.m.Pop()
+		return nil
+	}
+
+	// Clicking anywhere else ends editing the name
+	if s.editing && in.Clicked() && !in.Cursor().In(s.name.rect) {
+		s.finishEditing()
+	}
+	err := updateButtons(in, s.layout)
+	s.label()
+	return err
+}
+
+// edit types into the name
+func (s *settingsScreen) edit(in Input) {
+	name := []rune(s.m.Settings.PlayerName)
+	for _, r := range in.Chars() {
+		if unicode.IsPrint(r) && len(name) < maxNameLength {
+			name = append(name, r)
+		}
+	}
+	if in.KeyPressed(ebiten.KeyBackspace) && len(name) > 0 {
+		name = name[:len(name)-1]
+	}
+	s.m.Settings.PlayerName = string(name)
+
+	if in.KeyPressed(ebiten.KeyEnter) || in.KeyPressed(ebiten.KeyEscape) {
+		s.finishEditing()
+	}
+}
+
+// finishEditing stops editing the name, which cannot be left blank
+func (s *settingsScreen) finishEditing() {
+	s.editing = false
+	s.m.Settings.PlayerName = strings.TrimSpace(s.m.Settings.PlayerName)
+	if s.m.Settings.PlayerName == "" {
+		s.m.Settings.PlayerName = defaultName()
+	}
+}
+
+// nextTheme switches to the next theme pack, after the last going back to the built in look
+func (s *settingsScreen) nextTheme() error {
+	themes := s.m.Resources.Themes
+	var next *Theme
+	if i := slices.IndexFunc(themes, func(t *Theme) bool { return t.Name == s.m.Settings.Theme }); i+1 < len(themes) {
+		next = themes[i+1]
+	}
+	if err := s.m.Resources.UseTheme(next); err != nil {
+		return err
+	}
+
+	s.m.Settings.Theme = ""
+	if next != nil {
+		s.m.Settings.Theme = next.Name
+	}
+	return nil
+}
+
+// label writes the current settings on the buttons
+func (s *settingsScreen) label() {
+	onOff := map[bool]string{true: "On", false: "Off"}
+
+	s.name.label = "Name: " + s.m.Settings.PlayerName
+	if s.editing {
+		s.name.label += "_"
+	}
+	s.sound.label = "Sound: " + onOff[s.m.Settings.Sound]
+	s.screen.label = "Full Screen: " + onOff[s.m.Settings.Fullscreen]
+	s.theme.label = "Theme: " + defaultThemeName
+	if s.m.Settings.Theme != "" {
+		s.theme.label = "Theme: " + s.m.Settings.Theme
+	}
+}
+
+func (s *settingsScreen) Draw(dst *ebiten.Image) {
+	dst.Fill(palette.Background)
+	drawHeading(dst, "Settings", 50)
+	drawButtons(dst, s.layout)
+	if s.editing {
+		strokeRect(dst, s.name.rect, palette.Focus)
+		drawHeading(dst, "Type your name, Enter when done", s.name.rect.Min.Y-30)
+	}
+}
+
+// networkSetupScreen sets up a game before it starts
+// Games in the window are against a bot, people play each other over SSH
+type networkSetupScreen struct {
+	m        *ScreenManager
+	opponent *button
+	layout   []*button
+}
+
+func newNetworkSetupScreen(m *ScreenManager) Screen {
+	s := &networkSetupScreen{m: m}
+	s.opponent = &button{onClick: func() error { s.nextOpponent(); return nil }}
+	s.layout = buttonColumn(ScreenHeight/2-60,
+		s.opponent,
+		&button{label: "Start", onClick: func() error { m.Switch(StateGameplay); return nil }},
+		&button{label: "Back", onClick: func() error { m.Pop(); return nil }},
+	)
+	s.label()
+	return s
+}
+
+func (s *networkSetupScreen) buttons() []*button { return s.layout }
+func (s *networkSetupScreen) OnEnter()           {}
+func (s *networkSetupScreen) OnExit()            {}
+
+func (s *networkSetupScreen) Update(in Input) error {
+	if in.KeyPressed(ebiten.KeyEscape) {
+		s.m.Pop()
+		return nil
+	}
+	if in.KeyPressed(ebiten.KeyEnter) {
+		s.m.Switch(StateGameplay)
+		return nil
+	}
+	err := updateButtons(in, s.layout)
+	s.label()
+	return err
+}
+
+// nextOpponent cycles through the bots, from weakest to strongest
+func (s *networkSetupScreen) nextOpponent() {
+	next := 0
+	for i, name := range bot.Names {
+		if name == s.m.Settings.Opponent {
+			next = (i + 1) % len(bot.Names)
+		}
+	}
+	s.m.Settings.Opponent = bot.Names[next]
+}
+
+// label writes the opponent on its button
+func (s *networkSetupScreen) label() {
+	s.opponent.label = "Opponent: " + s.m.Settings.Opponent
+}
+
+func (s *networkSetupScreen) Draw(dst *ebiten.Image) {
+	dst.Fill(palette.Background)
+	drawHeading(dst, "New Game", 50)
+	drawHeading(dst, s.m.Settings.PlayerName+" vs "+s.m.Settings.Opponent, ScreenHeight/2-120)
+	drawButtons(dst, s.layout)
+	drawHeading(dst, "To play friends, host with `uno ssh` and have them connect with an SSH client", ScreenHeight-60)
+}
+
+// resultsScreen shows how the last game ended
+type resultsScreen struct {
+	m      *ScreenManager
+	layout []*button
+}
+
+func newResultsScreen(m *ScreenManager) Screen {
+	s := &resultsScreen{m: m}
+	s.layout = buttonColumn(ScreenHeight/2+40,
+		&button{label: "Play Again", onClick: func() error { m.Switch(StateGameplay); return nil }},
+		&button{label: "Back to Title", onClick: func() error { m.Switch(StateTitle); return nil }},
+	)
+	return s
+}
+
+func (s *resultsScreen) buttons() []*button { return s.layout }
+func (s *resultsScreen) OnEnter()           {}
+func (s *resultsScreen) OnExit()            {}
+
+func (s *resultsScreen) Update(in Input) error {
+	return updateButtons(in, s.layout)
+}
+
+func (s *resultsScreen) Draw(dst *ebiten.Image) {
+	dst.Fill(palette.Background)
+	for i, line := range s.summary() {
+		drawHeading(dst, line, ScreenHeight/2-160+i*(glyphHeight+8))
+	}
+	drawButtons(dst, s.layout)
+}
+
+// summary describes the last game, line by line
+func (s *resultsScreen) summary() []string {
+	result := s.m.Result
+	if result == nil {
+		return []string{"No game played yet"}
+	}
+
+	lines := []string{"Game abandoned"}
+	if result.Winner >= 0 {
+		lines[0] = result.Names[result.Winner] + " wins!"
+	}
+	lines = append(lines, "")
+	for i, name := range result.Names {
+		if i != result.Winner {
+			lines = append(lines, fmt.Sprintf("%s had %d cards left", name, result.CardsLeft[i]))
+		}
+	}
+	lines = append(lines, "", "Time played "+result.Duration.Round(time.Second).String())
+	return lines
+}
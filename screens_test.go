@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestTitleScreen(t *testing.T) {
+	h := newHarness(t)
+	h.expectState(StateTitle)
+
+	h.must(h.click("Rules"))
+	h.expectState(StateRules)
+	h.must(h.click("Back"))
+	h.expectState(StateTitle)
+
+	h.must(h.click("Settings"))
+	h.expectState(StateSettings)
+	h.must(h.press(ebiten.KeyEscape))
+	h.expectState(StateTitle)
+
+	h.must(h.click("Play Game"))
+	h.expectState(StateNetworkSetup)
+	h.must(h.click("Back"))
+	h.expectState(StateTitle)
+
+	if err := h.click("Exit"); !errors.Is(err, ebiten.Termination) {
+		t.Errorf("Expected Exit to end the game, got %v", err)
+	}
+}
+
+func TestRulesScreen(t *testing.T) {
+	h := newHarness(t)
+	h.must(h.click("Rules"))
+
+	screen := h.m.Top().(*rulesScreen)
+	if len(screen.visible()) == 0 || screen.visible()[0] != rulesText[0] {
+		t.Errorf("Expected the rules from the top, got %v", screen.visible())
+	}
+
+	// Scrolling stops at the ends of the text
+	h.must(h.tick(&fakeInput{wheel: 1}))
+	if screen.scroll != 0 {
+		t.Errorf("Expected no scrolling above the top, got %d", screen.scroll)
+	}
+	for range len(rulesText) {
+		h.must(h.press(ebiten.KeyDown))
+	}
+	if last := screen.visible(); last[len(last)-1] != rulesText[len(rulesText)-1] {
+		t.Errorf("Expected the last line at the bottom, got %v", last)
+	}
+
+	h.must(h.press(ebiten.KeyEscape))
+	h.expectState(StateTitle)
+}
+
+func TestSettingsScreen(t *testing.T) {
+	h := newHarness(t)
+	h.must(h.click("Settings"))
+
+	h.must(h.click("Sound: On"))
+	h.must(h.click("Full Screen: Off"))
+	if h.m.Settings.Sound || !h.m.Settings.Fullscreen {
+		t.Errorf("Expected sound off and full screen on, got %+v", h.m.Settings)
+	}
+
+	// Editing the name
+	h.must(h.click("Name: Ann"))
+	h.must(h.press(ebiten.KeyBackspace))
+	h.must(h.typeText("dy"))
+	h.must(h.press(ebiten.KeyEnter))
+	if h.m.Settings.PlayerName != "Andy" {
+		t.Errorf("Expected the name Andy, got %q", h.m.Settings.PlayerName)
+	}
+	if _, ok := h.button("Name: Andy"); !ok {
+		t.Error("Expected the button to show the new name")
+	}
+
+	// Typing is ignored unless the name is being edited, and names are kept short
+	h.must(h.typeText("x"))
+	h.must(h.click("Name: Andy"))
+	h.must(h.typeText("abcdefghijklmnopqrstuvwxyz"))
+	if got := len([]rune(h.m.Settings.PlayerName)); got != maxNameLength {
+		t.Errorf("Expected a name of %d letters, got %q", maxNameLength, h.m.Settings.PlayerName)
+	}
+
+	// A blank name falls back to the default
+	for range maxNameLength {
+		h.must(h.press(ebiten.KeyBackspace))
+	}
+	h.must(h.click("Back"))
+	h.expectState(StateTitle)
+	if h.m.Settings.PlayerName != defaultName() {
+		t.Errorf("Expected the default name, got %q", h.m.Settings.PlayerName)
+	}
+}
+
+func TestSettingsTheme(t *testing.T) {
+	t.Cleanup(func() { palette = defaultPalette })
+
+	h := newHarness(t)
+	theme, err := LoadTheme(highContrast(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.m.Resources.Themes = []*Theme{theme}
+	h.must(h.click("Settings"))
+
+	h.must(h.click("Theme: Classic"))
+	if h.m.Settings.Theme != "High Contrast" || h.m.Resources.Theme != theme || palette != theme.Palette {
+		t.Errorf("Expected the high contrast theme in use, got %q", h.m.Settings.Theme)
+	}
+
+	// After the last theme comes the built in look
+	h.must(h.click("Theme: High Contrast"))
+	if h.m.Settings.Theme != "" || h.m.Resources.Theme != nil || palette != defaultPalette {
+		t.Errorf("Expected the built in look, got %q", h.m.Settings.Theme)
+	}
+}
+
+func TestNetworkSetupScreen(t *testing.T) {
+	h := newHarness(t)
+	h.must(h.click("Play Game"))
+
+	h.must(h.click("Opponent: greedy"))
+	if h.m.Settings.Opponent != "ismcts" {
+		t.Errorf("Expected the next bot, got %q", h.m.Settings.Opponent)
+	}
+	h.must(h.click("Opponent: ismcts"))
+	if h.m.Settings.Opponent != "random" {
+		t.Errorf("Expected the bots to wrap around, got %q", h.m.Settings.Opponent)
+	}
+
+	h.must(h.click("Start"))
+	h.expectState(StateGameplay)
+	if len(h.m.stack) != 1 {
+		t.Errorf("Expected the game to replace the menus, got %d screens", len(h.m.stack))
+	}
+}
+
+func TestGameFlow(t *testing.T) {
+	h := newHarness(t)
+	h.must(h.click("Play Game"))
+	h.must(h.press(ebiten.KeyEnter))
+	h.expectState(StateGameplay)
+
+	h.idle(TPS * 3)
+	h.must(h.click("Leave Game"))
+	h.expectState(StateResults)
+
+	result := h.m.Result
+	if result == nil || result.Winner != -1 || result.Names[0] != "Ann" || result.Names[1] != "greedy" {
+		t.Fatalf("Expected an abandoned game between Ann and greedy, got %+v", result)
+	}
+	if result.Duration.Seconds() < 3 {
+		t.Errorf("Expected at least 3 seconds played, got %v", result.Duration)
+	}
+	if summary := h.m.Top().(*resultsScreen).summary(); summary[0] != "Game abandoned" {
+		t.Errorf("Expected the game to show as abandoned, got %v", summary)
+	}
+
+	h.must(h.click("Play Again"))
+	h.expectState(StateGameplay)
+	h.must(h.press(ebiten.KeyEscape))
+	h.expectState(StateResults)
+	h.must(h.click("Back to Title"))
+	h.expectState(StateTitle)
+}
+
+func TestResultsSummary(t *testing.T) {
+	h := newHarness(t)
+	screen := newResultsScreen(h.m).(*resultsScreen)
+
+	if summary := screen.summary(); summary[0] != "No game played yet" {
+		t.Errorf("Expected no game, got %v", summary)
+	}
+
+	h.m.Result = &GameResult{Names: []string{"Ann", "Bo"}, Winner: 1, CardsLeft: []int{4, 0}, Duration: 90e9}
+	expected := []string{"Bo wins!", "", "Ann had 4 cards left", "", "Time played 1m30s"}
+	summary := screen.summary()
+	if len(summary) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, summary)
+	}
+	for i := range expected {
+		if summary[i] != expected[i] {
+			t.Errorf("Expected line %d to be %q, got %q", i, expected[i], summary[i])
+		}
+	}
+}
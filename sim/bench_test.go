@@ -0,0 +1,20 @@
+package sim
+
+import (
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+func BenchmarkPlayGame(b *testing.B) {
+	cfg := Config{Bots: []string{"greedy", "random"}, Options: game.ModernOptions(), Seed: 1}
+
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		if _, err := PlayGame(cfg, i); err != nil {
+			b.Fatal(err)
+		}
+		i++
+	}
+}
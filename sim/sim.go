@@ -0,0 +1,281 @@
+// Package sim plays bot-vs-bot games headless through the real rules and
+// collects statistics, used to balance house rules before playing them
+package sim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Config describes a batch of simulated games
+type Config struct {
+	Games    int              // Number of games to play
+	Bots     []string         // Bot name for each seat, see bot.Names
+	Options  game.GameOptions // House rules for every game
+	Seed     uint64           // Game i is dealt and played with seed Seed+i
+	Workers  int              // Games played at once, 0 for one per CPU core
+	MaxMoves int              // Moves after which a game counts as unfinished, 0 for 10000
+
+	// NewBot creates the bot for a name, bot.ByName when nil
+	// Bots that implement io.Closer are closed after their game
+	NewBot func(name string, seed uint64) (bot.Bot, error)
+}
+
+// Result is the outcome of one simulated game
+type Result struct {
+	Winners   []int // Indexes into Config.Bots of the winning bots, empty if unfinished
+	Moves     int
+	Exhausted bool // Whether the draw pile ran out and the discard pile was reshuffled
+	Effects   map[game.CardType]int
+}
+
+// Stats sums up the results of a batch
+type Stats struct {
+	Bots       []string
+	Games      int
+	Wins       []int // Wins per bot, in the order of Config.Bots
+	Unfinished int
+	Moves      int
+	Exhausted  int
+	Effects    map[game.CardType]int // How often each card type was played
+}
+
+// LoadOptions reads house rules from a JSON file
+func LoadOptions(path string) (game.GameOptions, error) {
+	var opts game.GameOptions
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return opts, err
+	}
+
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return opts, fmt.Errorf("failed to parse rules file %s: %v", path, err)
+	}
+	return opts, nil
+}
+
+// Run plays the configured games in parallel and returns their statistics
+// Results only depend on the seed, not on the number of workers
+func Run(cfg Config) (*Stats, error) {
+	if cfg.Games <= 0 {
+		return nil, errors.New("the number of games must be positive")
+	}
+
+	for _, name := range cfg.Bots {
+		b, err := cfg.newBot(name, 0)
+		if err != nil {
+			return nil, err
+		}
+		closeBot(b)
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	results := make([]Result, cfg.Games)
+	errs := make([]error, cfg.Games)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = PlayGame(cfg, i)
+			}
+		}()
+	}
+
+	for i := range cfg.Games {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	stats := &Stats{
+		Bots:    cfg.Bots,
+		Wins:    make([]int, len(cfg.Bots)),
+		Effects: make(map[game.CardType]int),
+	}
+	for i, result := range results {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("game %d: %v", i, errs[i])
+		}
+		stats.add(result)
+	}
+
+	return stats, nil
+}
+
+// PlayGame plays game number i of a batch. Seats rotate from game to game so
+// every bot gets its share of going first
+func PlayGame(cfg Config, i int) (Result, error) {
+	return PlayDeal(cfg, cfg.Seed+uint64(i), i)
+}
+
+// PlayDeal plays the game dealt and played with the seed, the bots moved shift seats
+// along. Playing one seed at every shift gives every bot every hand of the deal
+func PlayDeal(cfg Config, seed uint64, shift int) (Result, error) {
+	result := Result{Effects: make(map[game.CardType]int)}
+	n := len(cfg.Bots)
+
+	players := make([]*game.Player, n)
+	seats := make([]bot.Bot, n)
+	botAt := make([]int, n) // Index into cfg.Bots for every seat
+	defer func() {
+		for _, b := range seats {
+			closeBot(b)
+		}
+	}()
+
+	for seat := range n {
+		botAt[seat] = (seat + shift) % n
+
+		b, err := cfg.newBot(cfg.Bots[botAt[seat]], seed*uint64(n)+uint64(seat))
+		if err != nil {
+			return result, err
+		}
+		seats[seat] = b
+		players[seat] = game.NewPlayer(fmt.Sprintf("%s %d", b.Name(), seat+1))
+	}
+
+	r := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	state, err := game.NewGameStateWithRandom(players, cfg.Options, r)
+	if err != nil {
+		return result, err
+	}
+
+	rules := game.NewGameRules()
+	rules.Subscribe(func(event game.Event) {
+		switch event.Kind {
+		case game.EventPlay:
+			result.Effects[event.Card.Type]++
+		case game.EventReshuffle:
+			result.Exhausted = true
+		case game.EventDraw, game.EventPenalty:
+			if state.DrawPile.IsEmpty() {
+				result.Exhausted = true
+			}
+		}
+	})
+
+	table, err := bot.NewTable(state, rules, seats)
+	if err != nil {
+		return result, err
+	}
+
+	maxMoves := cfg.MaxMoves
+	if maxMoves <= 0 {
+		maxMoves = 10000
+	}
+
+	result.Moves, err = table.Run(maxMoves)
+	if err != nil {
+		return result, err
+	}
+
+	// In team games both partners win
+	if team := state.WinningTeam(); team != -1 {
+		for seat := range n {
+			if state.TeamOf(seat) == team {
+				result.Winners = append(result.Winners, botAt[seat])
+			}
+		}
+	}
+	return result, nil
+}
+
+func (cfg Config) newBot(name string, seed uint64) (bot.Bot, error) {
+	if cfg.NewBot != nil {
+		return cfg.NewBot(name, seed)
+	}
+	return bot.ByName(name, seed)
+}
+
+// closeBot releases what a bot holds on to, like an engine's process
+func closeBot(b bot.Bot) {
+	if closer, ok := b.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+func (s *Stats) add(result Result) {
+	s.Games++
+	s.Moves += result.Moves
+
+	if len(result.Winners) == 0 {
+		s.Unfinished++
+	}
+	for _, winner := range result.Winners {
+		s.Wins[winner]++
+	}
+
+	if result.Exhausted {
+		s.Exhausted++
+	}
+
+	for cardType, count := range result.Effects {
+		s.Effects[cardType] += count
+	}
+}
+
+// WinRate returns the share of games the bot at the given index won
+func (s *Stats) WinRate(i int) float64 {
+	return float64(s.Wins[i]) / float64(s.Games)
+}
+
+// AverageMoves returns the average number of moves per game
+func (s *Stats) AverageMoves() float64 {
+	return float64(s.Moves) / float64(s.Games)
+}
+
+// ExhaustionRate returns the share of games in which the draw pile ran out
+func (s *Stats) ExhaustionRate() float64 {
+	return float64(s.Exhausted) / float64(s.Games)
+}
+
+// Print writes a report of the statistics with a histogram of the card effects
+func (s *Stats) Print(w io.Writer) {
+	fmt.Fprintf(w, "Games: %d (%d unfinished)\n", s.Games, s.Unfinished)
+	fmt.Fprintf(w, "Average length: %.1f moves\n", s.AverageMoves())
+	fmt.Fprintf(w, "Draw pile exhausted: %.1f%% of games\n", 100*s.ExhaustionRate())
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nWins:")
+	for i, name := range s.Bots {
+		fmt.Fprintf(tw, "  %d. %s\t%d\t%.1f%%\n", i+1, name, s.Wins[i], 100*s.WinRate(i))
+	}
+
+	total, most := 0, 0
+	for _, count := range s.Effects {
+		total += count
+		most = max(most, count)
+	}
+
+	fmt.Fprintln(tw, "\nCards played:")
+	for cardType := game.Number; cardType <= game.WildCustomizable; cardType++ {
+		count := s.Effects[cardType]
+		if count == 0 {
+			continue
+		}
+
+		bar := strings.Repeat("#", max(1, 40*count/most))
+		fmt.Fprintf(tw, "  %v\t%d\t%.1f%%\t%s\n", cardType, count, 100*float64(count)/float64(total), bar)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,106 @@
+package sim
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+func TestRun(t *testing.T) {
+	cfg := Config{Games: 40, Bots: []string{"greedy", "random"}, Options: game.ModernOptions(), Seed: 1, Workers: 4}
+
+	stats, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error running games, got %v", err)
+	}
+
+	if stats.Games != 40 {
+		t.Errorf("Expected 40 games, got %d", stats.Games)
+	}
+
+	if stats.Wins[0]+stats.Wins[1]+stats.Unfinished != 40 {
+		t.Errorf("Expected wins and unfinished games to add up to 40, got %v and %d", stats.Wins, stats.Unfinished)
+	}
+
+	if stats.Wins[0] <= stats.Wins[1] {
+		t.Errorf("Expected greedy to beat random, got %v", stats.Wins)
+	}
+
+	if stats.Effects[game.Number] == 0 || stats.AverageMoves() <= 0 {
+		t.Errorf("Expected number cards to be played, got %v", stats.Effects)
+	}
+
+	// The same seed gives the same games whatever the number of workers
+	cfg.Workers = 1
+	again, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error running games, got %v", err)
+	}
+	if !reflect.DeepEqual(stats, again) {
+		t.Errorf("Expected the same statistics with one worker, got %+v and %+v", stats, again)
+	}
+
+	var out bytes.Buffer
+	stats.Print(&out)
+	if !strings.Contains(out.String(), "greedy") || !strings.Contains(out.String(), "Number") {
+		t.Errorf("Expected the report to list bots and card types, got:\n%s", out.String())
+	}
+}
+
+func TestRunTeams(t *testing.T) {
+	cfg := Config{Games: 8, Bots: []string{"greedy", "random", "greedy", "random"}, Options: game.GameOptions{Teams: true}, Seed: 3}
+
+	stats, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error running team games, got %v", err)
+	}
+
+	// Partners share every win
+	if stats.Wins[0] != stats.Wins[2] || stats.Wins[1] != stats.Wins[3] {
+		t.Errorf("Expected partners to have the same wins, got %v", stats.Wins)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if _, err := Run(Config{Games: 1, Bots: []string{"greedy", "cheater"}}); err == nil {
+		t.Error("Expected error for an unknown bot")
+	}
+
+	if _, err := Run(Config{Games: 0, Bots: []string{"greedy", "random"}}); err == nil {
+		t.Error("Expected error for zero games")
+	}
+
+	if _, err := Run(Config{Games: 1, Bots: []string{"greedy"}}); err == nil {
+		t.Error("Expected error for a single player")
+	}
+}
+
+func TestLoadOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	rules := `{"customizableCards": 3, "customRule": "draw-two", "swapHandsCards": 1}`
+	if err := os.WriteFile(path, []byte(rules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := LoadOptions(path)
+	if err != nil {
+		t.Fatalf("Expected no error loading rules, got %v", err)
+	}
+
+	expected := game.GameOptions{CustomizableCards: 3, CustomRule: game.CustomRuleDrawTwo, SwapHandsCards: 1}
+	if opts != expected {
+		t.Errorf("Expected %+v, got %+v", expected, opts)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"customRule": "draw-ten"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOptions(path); err == nil {
+		t.Error("Expected error for an unknown custom rule")
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+// SoundPlayer plays the sound effects named in soundNames
+type SoundPlayer interface {
+	Play(name string)
+}
+
+// SoundBoard plays the sounds of Resources through Ebiten's audio context
+type SoundBoard struct {
+	context *audio.Context
+	samples map[string][]byte // Decoded sounds by name, ready for the context
+}
+
+// NewSoundBoard decodes the WAV data of every sound for the context
+func NewSoundBoard(context *audio.Context, sounds map[string][]byte) (*SoundBoard, error) {
+	b := &SoundBoard{context: context, samples: make(map[string][]byte, len(sounds))}
+	for name, data := range sounds {
+		stream, err := wav.DecodeWithSampleRate(context.SampleRate(), bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("sound %s: %v", name, err)
+		}
+		if b.samples[name], err = io.ReadAll(stream); err != nil {
+			return nil, fmt.Errorf("sound %s: %v", name, err)
+		}
+	}
+	return b, nil
+}
+
+// Play starts the sound, over any already playing. Unknown names are ignored
+func (b *SoundBoard) Play(name string) {
+	if samples, ok := b.samples[name]; ok {
+		b.context.NewPlayerFromBytes(samples).Play()
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+func TestSoundBoard(t *testing.T) {
+	context := audio.NewContext(sampleRate)
+
+	tone := toneWAV(toneFrequency, toneLength)
+	board, err := NewSoundBoard(context, map[string][]byte{"uno": tone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(board.samples["uno"]) == 0 {
+		t.Error("Expected the samples of the sound decoded")
+	}
+	board.Play("uno")
+	board.Play("missing")
+
+	if _, err := NewSoundBoard(context, map[string][]byte{"uno": []byte("not a sound")}); err == nil {
+		t.Error("Expected error for a sound that is not WAV data")
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/vtigo/uno-clone/anim"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Pacing of the table animations, in ticks
+const (
+	flightTicks = TPS / 4  // A card crossing the table
+	dealStagger = TPS / 15 // Between cards dealt
+	drawStagger = TPS / 10 // Between cards of a penalty
+	effectTicks = TPS / 2  // Skip, Reverse and wild card effects
+)
+
+// flight is a card crossing the table, drawn over everything else
+type flight struct {
+	card    game.Card
+	faceUp  bool
+	pos     image.Point
+	visible bool // Staggered cards only show once they set off
+}
+
+// effectKind is the kind of effect shown when a card lands on the discard pile
+type effectKind int
+
+const (
+	effectSkip    effectKind = iota // A no entry sign pops up
+	effectReverse                   // The discard pile pulses
+	effectWheel                     // The color wheel opens and closes on the chosen color
+)
+
+// effect is a special card's effect on the discard pile
+type effect struct {
+	kind  effectKind
+	color game.CardColor // Chosen color, for the wheel
+	t     float64        // Progress from 0 to 1
+}
+
+// fly queues a card moving from one point of the table to another, calling land
+// as it arrives
+func (s *gameplayScreen) fly(card game.Card, faceUp bool, from, to image.Point, land func()) anim.Animation {
+	f := &flight{card: card, faceUp: faceUp, pos: from}
+	return anim.NewTween(flightTicks, anim.OutCubic, func(v float64) {
+		if !f.visible {
+			f.visible = true
+			s.flights = append(s.flights, f)
+		}
+		f.pos = image.Pt(int(anim.Lerp(float64(from.X), float64(to.X), v)), int(anim.Lerp(float64(from.Y), float64(to.Y), v)))
+	}).OnDone(func() {
+		s.flights = slices.DeleteFunc(s.flights, func(other *flight) bool { return other == f })
+		land()
+	})
+}
+
+// animateDeal deals the hands one card at a time, then turns the first card over
+func (s *gameplayScreen) animateDeal() {
+	s.incoming = make([]int, len(s.state.Players))
+	most := 0
+	for i, player := range s.state.Players {
+		s.incoming[i] = player.Hand.Len()
+		most = max(most, player.Hand.Len())
+	}
+	s.topShown = false
+
+	var cards []anim.Animation
+	for k := range most {
+		for seat, player := range s.state.Players {
+			if k < player.Hand.Len() {
+				cards = append(cards, s.fly(player.Hand.At(k), false, drawPileRect.Min, s.handSlot(seat, k), func() { s.incoming[seat]-- }))
+			}
+		}
+	}
+	if top, err := s.state.DiscardPile.Top(); err == nil {
+		cards = append(cards, s.fly(top, true, drawPileRect.Min, discardPileRect.Min, func() { s.top, s.topShown = top, true }))
+	}
+	s.anims.Block(anim.Stagger(dealStagger, cards...))
+}
+
+// animate plays an event of the game back on the table. The state has already
+// changed, so cards on their way stay hidden where they are going until they land
+func (s *gameplayScreen) animate(event game.Event) {
+	switch event.Kind {
+	case game.EventPlay:
+		from := s.origin
+		if event.Player != s.seat {
+			count := s.state.Players[event.Player].Hand.Len() + 1
+			from = opponentLayout(count)[count/2]
+		}
+		s.anims.Block(s.fly(event.Card, true, from, discardPileRect.Min, func() { s.showTop(event.Card, event.Color) }))
+	case game.EventDraw, game.EventPenalty:
+		count := max(event.Count, 1)
+		hand := s.state.Players[event.Player].Hand.Len()
+		s.incoming[event.Player] += count
+
+		cards := make([]anim.Animation, count)
+		for k := range cards {
+			slot := hand - count + k
+			card := s.state.Players[event.Player].Hand.At(slot)
+			cards[k] = s.fly(card, false, drawPileRect.Min, s.handSlot(event.Player, slot), func() { s.incoming[event.Player]-- })
+		}
+		s.anims.Block(anim.Stagger(drawStagger, cards...))
+	case game.EventChooseColor:
+		// Waits for the card it colors to turn over
+		s.anims.Block(anim.Call(func() { s.playEffect(effectWheel, event.Color) }))
+	case game.EventShuffleHands, game.EventSwapHands:
+		// Every hand changed at once, so cards on their way land where they are
+		s.anims.Finish()
+		clear(s.incoming)
+	}
+}
+
+// showTop turns over the card that landed on the discard pile and starts its effect
+func (s *gameplayScreen) showTop(card game.Card, chosen game.CardColor) {
+	s.top, s.topShown = card, true
+	switch {
+	case card.Color == game.Wild:
+		s.playEffect(effectWheel, chosen)
+	case card.Type == game.Skip:
+		s.playEffect(effectSkip, card.Color)
+	case card.Type == game.Reverse:
+		s.playEffect(effectReverse, card.Color)
+	}
+}
+
+// playEffect shows an effect over the discard pile, without holding up the game
+func (s *gameplayScreen) playEffect(kind effectKind, c game.CardColor) {
+	e := &effect{kind: kind, color: c}
+	s.effects = append(s.effects, e)
+	s.anims.Play(anim.NewTween(effectTicks, anim.Linear, func(v float64) { e.t = v }).OnDone(func() {
+		s.effects = slices.DeleteFunc(s.effects, func(other *effect) bool { return other == e })
+	}))
+}
+
+// handSlot returns where the card at the index of a seat's hand sits on the table
+func (s *gameplayScreen) handSlot(seat, index int) image.Point {
+	count := s.state.Players[seat].Hand.Len()
+	if seat == s.seat {
+		return handLayout(count)[index]
+	}
+	return opponentLayout(count)[index]
+}
+
+// opponentLayout returns the top left corner of each of count cards in the
+// bot's hand, overlapping along the top of the screen
+func opponentLayout(count int) []image.Point {
+	points := make([]image.Point, count)
+	for i := range points {
+		points[i] = image.Pt(ScreenWidth/2-(count*CardWidth/3)/2+i*CardWidth/3, 40)
+	}
+	return points
+}
+
+// drawDiscard draws the top of the discard pile as far as the animations have got,
+// pulsing after a Reverse
+func (s *gameplayScreen) drawDiscard(dst *ebiten.Image) {
+	if !s.topShown {
+		return
+	}
+	rect := discardPileRect
+	for _, e := range s.effects {
+		if e.kind == effectReverse {
+			grow := int(float64(CardWidth) / 6 * anim.Pulse(e.t))
+			rect = rect.Inset(-grow)
+		}
+	}
+	s.m.Resources.Cards.DrawFace(dst, s.top, rect)
+}
+
+// drawFlights draws the cards crossing the table
+func (s *gameplayScreen) drawFlights(dst *ebiten.Image) {
+	for _, f := range s.flights {
+		rect := cardRect(f.pos)
+		if f.faceUp {
+			s.m.Resources.Cards.DrawFace(dst, f.card, rect)
+		} else {
+			s.m.Resources.Cards.DrawBack(dst, rect)
+		}
+	}
+}
+
+// drawEffects draws the skip sign and the color wheel over the discard pile
+func (s *gameplayScreen) drawEffects(dst *ebiten.Image) {
+	center := discardPileRect.Min.Add(image.Pt(CardWidth/2, CardHeight/2))
+	cx, cy := float32(center.X), float32(center.Y)
+
+	for _, e := range s.effects {
+		// Effects pop in over the first half and fade over the second
+		scale := float32(anim.OutBack(min(1, 2*e.t)))
+		alpha := uint8(255 * min(1, 2-2*e.t))
+
+		switch e.kind {
+		case effectSkip:
+			radius := 28 * scale
+			red := fade(cardColors[game.Red].Primary, alpha)
+			vector.StrokeCircle(dst, cx, cy, radius, 6, red, true)
+			vector.StrokeLine(dst, cx-radius*0.7, cy+radius*0.7, cx+radius*0.7, cy-radius*0.7, 6, red, true)
+		case effectWheel:
+			for i, c := range wheelColors {
+				offset := wheelCenters()[i].Sub(wheelCenter)
+				x, y := cx+float32(offset.X)/2*scale, cy+float32(offset.Y)/2*scale
+				a := alpha
+				if c != e.color {
+					// Only the chosen color stays to the end
+					a = uint8(255 * min(1, max(0, 1.5-2*e.t)))
+				}
+				vector.DrawFilledCircle(dst, x, y, wheelRadius/2*scale, fade(cardColors[c].Primary, a), true)
+			}
+		}
+	}
+}
+
+// fade returns the color at the opacity, premultiplied as Ebiten expects
+func fade(c color.RGBA, alpha uint8) color.RGBA {
+	scale := func(v uint8) uint8 { return uint8(uint16(v) * uint16(alpha) / 255) }
+	return color.RGBA{R: scale(c.R), G: scale(c.G), B: scale(c.B), A: alpha}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/vtigo/uno-clone/anim"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// effectKinds lists the effects showing on the table
+func effectKinds(screen *gameplayScreen) []effectKind {
+	var kinds []effectKind
+	for _, e := range screen.effects {
+		kinds = append(kinds, e.kind)
+	}
+	return kinds
+}
+
+func TestAnimateDeal(t *testing.T) {
+	h := newHarness(t)
+	h.must(h.click("Play Game"))
+	h.must(h.click("Start"))
+	screen := h.m.Top().(*gameplayScreen)
+
+	// Every card starts on the draw pile
+	if screen.incoming[0] != screen.player().Hand.Len() || screen.incoming[1] != screen.state.Players[1].Hand.Len() || screen.topShown {
+		t.Fatalf("Expected the hands and the first card still to come, got %v", screen.incoming)
+	}
+
+	// Clicks wait for the deal
+	h.must(h.clickAt(drawPileRect.Min.Add(image.Pt(CardWidth/2, CardHeight/2))))
+	if screen.queued == nil || screen.state.HasDrawn {
+		t.Error("Expected the click to wait for the deal")
+	}
+
+	h.settle(screen)
+	top, _ := screen.state.DiscardPile.Top()
+	if screen.incoming[0] != 0 || screen.incoming[1] != 0 || !screen.topShown || screen.top != top {
+		t.Errorf("Expected every card dealt and %v turned over, got %v and %v", top, screen.incoming, screen.top)
+	}
+	if len(screen.flights) != 0 || screen.queued != nil {
+		t.Error("Expected no card left moving and the click handled")
+	}
+}
+
+func TestAnimateLeaveWhileDealing(t *testing.T) {
+	h := newHarness(t)
+	h.must(h.click("Play Game"))
+	h.must(h.click("Start"))
+
+	// Leaving never waits
+	h.must(h.click("Leave Game"))
+	h.expectState(StateResults)
+}
+
+func TestAnimatePlay(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3").Hand(1, "Y1 Y2 G1").Discard("G5"))
+
+	h.clickCard(screen, 0)
+	if len(screen.flights) != 0 || !screen.anims.Blocking() {
+		t.Fatal("Expected the card to set off on the next tick")
+	}
+	h.idle(1)
+	if len(screen.flights) != 1 {
+		t.Fatalf("Expected one card moving, got %d", len(screen.flights))
+	}
+	if pos := screen.flights[0].pos; pos.Y >= handLayout(2)[0].Y || pos.Y <= discardPileRect.Min.Y {
+		t.Errorf("Expected the card on its way up from the hand, got %v", pos)
+	}
+
+	// The old card shows until the new one lands
+	h.idle(flightTicks - 2)
+	if screen.top != (game.Card{Color: game.Green, Type: game.Number, Value: 5}) {
+		t.Errorf("Expected G5 shown while the card flies, got %v", screen.top)
+	}
+	h.idle(1)
+	if screen.top != (game.Card{Color: game.Green, Type: game.Number, Value: 7}) || len(screen.flights) != 0 {
+		t.Errorf("Expected G7 to land, got %v", screen.top)
+	}
+}
+
+func TestAnimateEffects(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "GS GR W B3 B4").Hand(1, "Y1 Y2").Discard("G5"))
+
+	// play clicks the card of the hand and lets it land
+	play := func(notation string) {
+		t.Helper()
+		card, _ := game.ParseCard(notation)
+		i := slices.Index(screen.player().Hand.Cards(), card)
+		h.clickCard(screen, i)
+		if card.Color == game.Wild {
+			h.clickColor(game.Blue)
+		}
+		h.settle(screen)
+	}
+
+	play("GS")
+	if kinds := effectKinds(screen); len(kinds) != 1 || kinds[0] != effectSkip {
+		t.Errorf("Expected the skip sign, got %v", kinds)
+	}
+
+	play("GR")
+	if kinds := effectKinds(screen); kinds[len(kinds)-1] != effectReverse {
+		t.Errorf("Expected the reverse pulse, got %v", kinds)
+	}
+
+	play("W")
+	last := screen.effects[len(screen.effects)-1]
+	if last.kind != effectWheel || last.color != game.Blue {
+		t.Errorf("Expected the wheel closing on blue, got %+v", last)
+	}
+
+	// Effects are for show and go away on their own
+	h.idle(effectTicks)
+	if len(screen.effects) != 0 {
+		t.Errorf("Expected the effects to end, got %v", effectKinds(screen))
+	}
+}
+
+func TestAnimateStartingWild(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "B3 B4").Hand(1, "Y1 Y2").Discard("W").Phase(game.PhaseColorSelection))
+
+	h.clickColor(game.Green)
+	h.idle(1)
+	if len(screen.effects) != 1 || screen.effects[0].kind != effectWheel || screen.effects[0].color != game.Green {
+		t.Errorf("Expected the wheel closing on green, got %v", effectKinds(screen))
+	}
+}
+
+func TestAnimatePenalty(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3").Hand(1, "Y1").Discard("G5").Played(1))
+
+	h.must(h.click("Challenge"))
+	if screen.incoming[1] != 2 {
+		t.Fatalf("Expected 2 cards on their way to the bot, got %d", screen.incoming[1])
+	}
+
+	// The cards set off one after the other
+	h.idle(flightTicks)
+	if screen.incoming[1] != 1 {
+		t.Errorf("Expected the first card to land, got %d still coming", screen.incoming[1])
+	}
+	h.settle(screen)
+	if screen.incoming[1] != 0 {
+		t.Errorf("Expected both cards to land, got %d still coming", screen.incoming[1])
+	}
+}
+
+func TestAnimateBotWaits(t *testing.T) {
+	h, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "G7 B3").Hand(1, "Y1 G1").Discard("G5").Turn(1))
+
+	screen.botWait = 0
+	screen.anims.Block(anim.Wait(5))
+	h.idle(3)
+	if screen.state.CurrentPlayer != 1 {
+		t.Fatal("Expected the bot to wait for the table")
+	}
+	h.settle(screen)
+	h.idle(1)
+	h.waitBot(screen)
+	if screen.state.CurrentPlayer != 0 {
+		t.Error("Expected the bot to move once the table is still")
+	}
+}
+
+func TestAnimateSwapHands(t *testing.T) {
+	_, screen := tableHarness(t, game.NewBuilder("Ann", "greedy").Hand(0, "Y1").Hand(1, "B3").Discard("G5"))
+
+	// Cards on their way land at once when the hands change
+	screen.incoming[1] = 1
+	screen.anims.Block(anim.Wait(TPS))
+	screen.animate(game.Event{Kind: game.EventSwapHands, Player: 0, Target: 1})
+	if screen.anims.Busy() || screen.incoming[1] != 0 {
+		t.Error("Expected the animations finished")
+	}
+}
+
+func TestOpponentLayout(t *testing.T) {
+	points := opponentLayout(7)
+	if len(points) != 7 || points[1].X-points[0].X != CardWidth/3 {
+		t.Errorf("Expected 7 overlapping cards, got %v", points)
+	}
+}
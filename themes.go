@@ -0,0 +1,255 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vtigo/uno-clone/cardart"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Files of a theme folder
+const (
+	themeManifest = "theme.json"
+	themeCards    = "cards"
+)
+
+// defaultThemeName is what Settings calls the built in look
+const defaultThemeName = "Classic"
+
+// embeddedThemes are the themes coming with the game, built into the binary so
+// they show wherever it runs
+//
+//go:embed themes
+var embeddedThemes embed.FS
+
+// Theme is a pack of card images, UI colors and a font replacing the built in ones.
+// Cards and colors the pack leaves out keep their built in look
+type Theme struct {
+	Name       string
+	Palette    UIPalette
+	CardColors cardart.Colors // Colors of the cards the pack has no image for
+	Font       []byte         // TrueType data, nil to keep the built in font
+	Cards      fs.FS          // Card faces named by notation and back.png
+}
+
+// manifest is the theme.json of a theme folder
+type manifest struct {
+	Name    string            `json:"name"`
+	Font    string            `json:"font"`    // File of the font in the theme folder
+	Palette map[string]string `json:"palette"` // Colors by paletteFields key, as #rrggbb
+
+	// CardColors by cardColorNames key, as #rrggbb
+	CardColors map[string]string `json:"cardColors"`
+}
+
+// cardColorNames names the card colors in theme manifests
+var cardColorNames = map[string]game.CardColor{
+	"red":    game.Red,
+	"blue":   game.Blue,
+	"green":  game.Green,
+	"yellow": game.Yellow,
+	"wild":   game.Wild,
+}
+
+// paletteFields names the colors of a palette in theme manifests
+func paletteFields(p *UIPalette) map[string]*color.RGBA {
+	return map[string]*color.RGBA{
+		"background":  &p.Background,
+		"button":      &p.Button,
+		"buttonHover": &p.ButtonHover,
+		"buttonEdge":  &p.ButtonEdge,
+		"focus":       &p.Focus,
+		"playable":    &p.Playable,
+	}
+}
+
+// LoadTheme reads the theme folder of fsys and checks every file in it, reporting
+// all problems at once so a pack can be fixed in one go
+func LoadTheme(fsys fs.FS) (*Theme, error) {
+	data, err := fs.ReadFile(fsys, themeManifest)
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", themeManifest, err)
+	}
+
+	var errs []error
+	theme := &Theme{Name: strings.TrimSpace(m.Name), Palette: defaultPalette, CardColors: maps.Clone(cardart.Palette)}
+	if theme.Name == "" {
+		errs = append(errs, fmt.Errorf("%s has no name", themeManifest))
+	}
+
+	fields := paletteFields(&theme.Palette)
+	for _, key := range slices.Sorted(maps.Keys(m.Palette)) {
+		field, ok := fields[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown palette color %q", key))
+			continue
+		}
+		if *field, err = parseColor(m.Palette[key]); err != nil {
+			errs = append(errs, fmt.Errorf("palette color %s: %v", key, err))
+		}
+	}
+
+	// Cards are shaded from the one color given, like the built in ones
+	for _, key := range slices.Sorted(maps.Keys(m.CardColors)) {
+		c, ok := cardColorNames[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown card color %q", key))
+			continue
+		}
+		primary, err := parseColor(m.CardColors[key])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("card color %s: %v", key, err))
+			continue
+		}
+		theme.CardColors[c] = cardart.Shade(primary)
+	}
+
+	if m.Font != "" {
+		if theme.Font, err = fs.ReadFile(fsys, m.Font); err != nil {
+			errs = append(errs, fmt.Errorf("font: %v", err))
+		} else if _, err := newFace(theme.Font); err != nil {
+			errs = append(errs, fmt.Errorf("font %s: %v", m.Font, err))
+		}
+	}
+
+	if theme.Cards, err = fs.Sub(fsys, themeCards); err != nil {
+		return nil, err
+	}
+	errs = append(errs, checkCardImages(theme.Cards)...)
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+// checkCardImages makes sure every file in a folder of card images is named after
+// a card and has the card size
+func checkCardImages(fsys fs.FS) []error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return []error{err}
+	}
+
+	known := map[string]bool{backImage: true}
+	for _, card := range cardart.Cards() {
+		name, _ := card.Notation()
+		known[name+".png"] = true
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		file := path.Join(themeCards, name)
+		if !known[name] {
+			errs = append(errs, fmt.Errorf("%s is not named after a card", file))
+			continue
+		}
+
+		size, err := imageSize(fsys, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", file, err))
+			continue
+		}
+		if err := checkCardSize(file, size); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// imageSize reads the size of an image without decoding its pixels
+func imageSize(fsys fs.FS, name string) (image.Point, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return image.Point{}, err
+	}
+	defer f.Close()
+
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return image.Point{}, err
+	}
+	return image.Pt(config.Width, config.Height), nil
+}
+
+// parseColor reads a color written as #rrggbb
+func parseColor(s string) (color.RGBA, error) {
+	hex, ok := strings.CutPrefix(s, "#")
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if !ok || len(hex) != 6 || err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, expected #rrggbb", s)
+	}
+	return color.RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xff}, nil
+}
+
+// LoadThemes loads every theme folder in the dirs, sorted by name. Broken themes
+// are left out and reported, a missing dir simply has no themes
+func LoadThemes(dirs ...fs.FS) ([]*Theme, error) {
+	var themes []*Theme
+	var errs []error
+	names := map[string]bool{defaultThemeName: true}
+	for _, dir := range dirs {
+		entries, err := fs.ReadDir(dir, ".")
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			sub, err := fs.Sub(dir, entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			theme, err := LoadTheme(sub)
+			if err == nil && names[theme.Name] {
+				err = fmt.Errorf("another theme is already called %s", theme.Name)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("theme %s: %w", entry.Name(), err))
+				continue
+			}
+			names[theme.Name] = true
+			themes = append(themes, theme)
+		}
+	}
+
+	sort.Slice(themes, func(i, j int) bool { return themes[i].Name < themes[j].Name })
+	return themes, errors.Join(errs...)
+}
+
+// themeDirs returns the folders of themes to choose from: those coming with the
+// game, then dir when it is set
+func themeDirs(dir string) []fs.FS {
+	bundled, _ := fs.Sub(embeddedThemes, "themes")
+	if dir == "" {
+		return []fs.FS{bundled}
+	}
+	return []fs.FS{bundled, os.DirFS(dir)}
+}
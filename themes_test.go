@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/image/font/gofont/gobold"
+
+	"github.com/vtigo/uno-clone/cardart"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// highContrast is a theme pack changing the back, one card, two colors, the red
+// of the cards and the font
+func highContrast(t *testing.T) fstest.MapFS {
+	return fstest.MapFS{
+		themeManifest: {Data: []byte(`{
+			"name": "High Contrast",
+			"font": "bold.ttf",
+			"palette": {"background": "#000000", "focus": "#00FFFF"},
+			"cardColors": {"red": "#cc0000"}
+		}`)},
+		"bold.ttf":       {Data: gobold.TTF},
+		"cards/back.png": pngFile(t, CardWidth, CardHeight, color.White),
+		"cards/W+4.png":  pngFile(t, CardWidth, CardHeight, color.Black),
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	theme, err := LoadTheme(highContrast(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if theme.Name != "High Contrast" || !bytes.Equal(theme.Font, gobold.TTF) {
+		t.Errorf("Expected the name and font of the manifest, got %q and %q", theme.Name, theme.Font)
+	}
+	expected := defaultPalette
+	expected.Background = color.RGBA{A: 0xff}
+	expected.Focus = color.RGBA{G: 0xff, B: 0xff, A: 0xff}
+	if theme.Palette != expected {
+		t.Errorf("Expected the colors of the manifest over the default ones, got %+v", theme.Palette)
+	}
+	if theme.CardColors[game.Red] != cardart.Shade(color.RGBA{R: 0xcc, A: 0xff}) || theme.CardColors[game.Blue] != cardart.Palette[game.Blue] {
+		t.Errorf("Expected the card colors of the manifest over the default ones, got %+v", theme.CardColors)
+	}
+
+	atlas, err := packCards(theme.CardColors, theme.Cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards := cardart.Cards()
+	back := atlasCell(len(cards))
+	wild := atlasCell(slices.Index(cards, game.Card{Color: game.Wild, Type: game.WildDrawFour}))
+	if atlas.RGBAAt(back.Min.X, back.Min.Y) != (color.RGBA{0xff, 0xff, 0xff, 0xff}) || atlas.RGBAAt(wild.Min.X, wild.Min.Y) != (color.RGBA{A: 0xff}) {
+		t.Error("Expected the back and W+4 of the theme")
+	}
+	// Cards without an image are drawn in the theme's colors
+	red := atlasCell(slices.Index(cards, game.Card{Color: game.Red, Type: game.Number, Value: 7}))
+	if got := atlas.RGBAAt(red.Min.X+3, red.Min.Y+CardHeight/2); got != (color.RGBA{R: 0xcc, A: 0xff}) {
+		t.Errorf("Expected R7 drawn in the red of the theme, got %v", got)
+	}
+}
+
+func TestLoadThemeProblems(t *testing.T) {
+	fsys := fstest.MapFS{
+		themeManifest: {Data: []byte(`{
+			"font": "missing.ttf",
+			"palette": {"background": "black", "shadow": "#000000"},
+			"cardColors": {"red": "red", "purple": "#800080"}
+		}`)},
+		"cards/R7.png":   pngFile(t, 32, 48, color.White),
+		"cards/R10.png":  pngFile(t, CardWidth, CardHeight, color.White),
+		"cards/back.png": {Data: []byte("not an image")},
+	}
+
+	_, err := LoadTheme(fsys)
+	if err == nil {
+		t.Fatal("Expected error for a broken theme")
+	}
+	// Every problem is reported at once
+	for _, problem := range []string{
+		"has no name",
+		`invalid color "black"`,
+		`unknown palette color "shadow"`,
+		`card color red: invalid color "red"`,
+		`unknown card color "purple"`,
+		"font:",
+		"cards/R7.png is 32x48, cards are 64x96",
+		"cards/R10.png is not named after a card",
+		"cards/back.png:",
+	} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Expected %q among the problems, got %v", problem, err)
+		}
+	}
+
+	_, err = LoadTheme(fstest.MapFS{
+		themeManifest: {Data: []byte(`{"name": "Broken Font", "font": "font.ttf"}`)},
+		"font.ttf":    {Data: []byte("not a font")},
+	})
+	if err == nil || !strings.Contains(err.Error(), "font font.ttf:") {
+		t.Errorf("Expected a font that is not TrueType refused, got %v", err)
+	}
+
+	if _, err := LoadTheme(fstest.MapFS{themeManifest: {Data: []byte("{")}}); err == nil {
+		t.Error("Expected error for a manifest that is not JSON")
+	}
+	if _, err := LoadTheme(fstest.MapFS{}); err == nil {
+		t.Error("Expected error for a folder without a manifest")
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("zebra/theme.json", `{"name": "Zebra"}`)
+	write("acme/theme.json", `{"name": "Acme Corp", "palette": {"button": "#ff6600"}}`)
+	write("copy/theme.json", `{"name": "Acme Corp"}`)
+	write("classic/theme.json", `{"name": "Classic"}`)
+	write("broken/theme.json", `{"name": "Broken", "palette": {"button": "orange"}}`)
+	write("notes.txt", "not a theme")
+
+	themes, err := LoadThemes(os.DirFS(dir))
+	if len(themes) != 2 || themes[0].Name != "Acme Corp" || themes[1].Name != "Zebra" {
+		t.Fatalf("Expected the good themes sorted by name, got %d", len(themes))
+	}
+	if err == nil || !strings.Contains(err.Error(), "theme broken") {
+		t.Errorf("Expected the broken theme reported, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "already called Acme Corp") || !strings.Contains(err.Error(), "already called Classic") {
+		t.Errorf("Expected themes taking a used name reported, got %v", err)
+	}
+
+	themes, err = LoadThemes(os.DirFS(filepath.Join(dir, "missing")))
+	if themes != nil || err != nil {
+		t.Errorf("Expected no themes without a themes folder, got %v", err)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	c, err := parseColor("#ff6600")
+	if err != nil || c != (color.RGBA{R: 0xff, G: 0x66, A: 0xff}) {
+		t.Errorf("Expected orange, got %v and %v", c, err)
+	}
+	for _, s := range []string{"ff6600", "#f60", "#ff660g", "#ff660000"} {
+		if _, err := parseColor(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestUseTheme(t *testing.T) {
+	t.Cleanup(func() { palette, cardColors, face = defaultPalette, cardart.Palette, nil })
+
+	r, err := LoadResources(embeddedAssets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	theme, err := LoadTheme(highContrast(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.UseTheme(theme); err != nil {
+		t.Fatal(err)
+	}
+	if r.Theme != theme || palette != theme.Palette || cardColors[game.Red] != theme.CardColors[game.Red] || !bytes.Equal(r.Font, gobold.TTF) || face == nil {
+		t.Error("Expected the theme's colors and font in use")
+	}
+
+	if err := r.UseTheme(nil); err != nil {
+		t.Fatal(err)
+	}
+	if r.Theme != nil || palette != defaultPalette || cardColors[game.Red] != cardart.Palette[game.Red] || r.Font != nil || face != nil {
+		t.Error("Expected the built in look back")
+	}
+}
+
+func TestExampleThemes(t *testing.T) {
+	themes, err := LoadThemes(themeDirs("")...)
+	if err != nil || len(themes) == 0 {
+		t.Fatalf("Expected the example themes to load, got %d and %v", len(themes), err)
+	}
+
+	// The high contrast theme recolors the cards too, not only the chrome
+	i := slices.IndexFunc(themes, func(theme *Theme) bool { return theme.Name == "High Contrast" })
+	if i < 0 || themes[i].CardColors[game.Yellow] == cardart.Palette[game.Yellow] {
+		t.Error("Expected the high contrast theme to change the card colors")
+	}
+
+	// Bundled themes do not depend on where the game runs, and a folder of
+	// themes adds to them
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "acme"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "acme", themeManifest), []byte(`{"name": "Acme Corp"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(t.TempDir())
+	more, err := LoadThemes(themeDirs(dir)...)
+	if err != nil || len(more) != len(themes)+1 {
+		t.Errorf("Expected the bundled themes and Acme Corp, got %d and %v", len(more), err)
+	}
+}
@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// ANSI escape sequences
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	reset       = "\x1b[0m"
+	bold        = "\x1b[1m"
+	dim         = "\x1b[2m"
+)
+
+var colorCodes = map[game.CardColor]string{
+	game.Red:    "\x1b[31m",
+	game.Blue:   "\x1b[34m",
+	game.Green:  "\x1b[32m",
+	game.Yellow: "\x1b[33m",
+}
+
+// rainbow colors the letters of wild cards in turn
+var rainbow = []game.CardColor{game.Red, game.Yellow, game.Green, game.Blue}
+
+// styler applies ANSI styles, or leaves text plain when colors are off
+type styler bool
+
+func (s styler) style(text string, codes ...string) string {
+	if !s || len(codes) == 0 {
+		return text
+	}
+	return strings.Join(codes, "") + text + reset
+}
+
+// card writes a card in compact notation, in its color or in all four for wilds
+func (s styler) card(card game.Card) string {
+	code, err := card.Notation()
+	if err != nil {
+		code = "?"
+	}
+
+	if card.Color != game.Wild {
+		return s.style(code, bold, colorCodes[card.Color])
+	}
+	if !s {
+		return code
+	}
+
+	var b strings.Builder
+	for i, r := range code {
+		b.WriteString(s.style(string(r), bold, colorCodes[rainbow[i%len(rainbow)]]))
+	}
+	return b.String()
+}
+
+// color writes a color's name in that color
+func (s styler) color(color game.CardColor) string {
+	return s.style(color.String(), bold, colorCodes[color])
+}
+
+// screen draws the whole table as the seat sees it
+func (s styler) screen(view game.PlayerView, names []string, log []string, message string) string {
+	var b strings.Builder
+	if s {
+		b.WriteString(clearScreen)
+	}
+
+	fmt.Fprintf(&b, "%s\n\n", s.style("UNO  "+strings.Join(names, " vs "), bold))
+
+	for i, name := range names {
+		marker := "  "
+		if i == view.CurrentPlayer && view.Phase != game.PhaseGameOver {
+			marker = s.style("> ", bold)
+		}
+
+		label := name
+		switch {
+		case i == view.Seat:
+			label += " (you)"
+		case view.Teams != nil && view.Teams[i] == view.Teams[view.Seat]:
+			label += " (partner)"
+		}
+
+		fmt.Fprintf(&b, "%s%-20s %2d cards", marker, label, view.HandSizes[i])
+		if view.CalledUno[i] {
+			b.WriteString("  " + s.style("UNO!", bold))
+		}
+		if i != view.Seat && view.Hands[i] != nil {
+			b.WriteString("  " + s.hand(view.Hands[i], false))
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "\nDiscard %s   Draw pile %d   Color %s", s.card(view.TopCard), view.DrawPileSize, s.color(view.ActiveColor))
+	if view.Reversed && len(names) > 2 {
+		b.WriteString("   Reversed")
+	}
+	b.WriteString("\n\n")
+
+	for _, line := range log {
+		b.WriteString(s.style(line, dim) + "\n")
+	}
+	if len(log) > 0 {
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "Your hand  %s\n", s.hand(view.MyHand(), true))
+	b.WriteString(status(view, names) + "\n")
+	if message != "" {
+		b.WriteString(s.style(message, bold) + "\n")
+	}
+	return b.String()
+}
+
+// hand writes cards side by side, numbered for picking when asked
+func (s styler) hand(cards []game.Card, numbered bool) string {
+	parts := make([]string, len(cards))
+	for i, card := range cards {
+		parts[i] = s.card(card)
+		if numbered {
+			parts[i] = fmt.Sprintf("%d:%s", i+1, parts[i])
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+// status tells the seat what the game is waiting for
+func status(view game.PlayerView, names []string) string {
+	switch {
+	case view.Phase == game.PhaseGameOver:
+		for i, size := range view.HandSizes {
+			if size == 0 && view.HasPlayed[i] {
+				return fmt.Sprintf("Game over, %s won", names[i])
+			}
+		}
+		return "Game over"
+	case view.CurrentPlayer != view.Seat:
+		return fmt.Sprintf("Waiting for %s, h for help", names[view.CurrentPlayer])
+	case view.Phase == game.PhaseColorSelection:
+		return "Choose a color: red, blue, green or yellow"
+	case view.HasDrawn:
+		return "Play the card you drew or p to pass"
+	default:
+		return "Your turn: play a card by its number, d to draw, h for help"
+	}
+}
+
+// describe writes an event as a line of the game log
+func (s styler) describe(event game.Event, names []string) string {
+	name := func(seat int) string {
+		if seat >= 0 && seat < len(names) {
+			return names[seat]
+		}
+		return "?"
+	}
+
+	switch event.Kind {
+	case game.EventPlay:
+		text := fmt.Sprintf("%s played %s", name(event.Player), s.card(event.Card))
+		if event.Card.Color == game.Wild && event.Color != game.Wild {
+			text += " and chose " + s.color(event.Color)
+		}
+		return text
+	case game.EventDraw:
+		return name(event.Player) + " drew a card"
+	case game.EventPenalty:
+		return fmt.Sprintf("%s took %d cards", name(event.Player), event.Count)
+	case game.EventPass:
+		return name(event.Player) + " passed"
+	case game.EventChooseColor:
+		return name(event.Player) + " chose " + s.color(event.Color)
+	case game.EventCallUno:
+		return name(event.Player) + " called UNO"
+	case game.EventShuffleHands:
+		return name(event.Player) + " shuffled every hand"
+	case game.EventSwapHands:
+		return fmt.Sprintf("%s swapped hands with %s", name(event.Player), name(event.Target))
+	case game.EventReshuffle:
+		return "The discard pile was shuffled into the draw pile"
+	case game.EventGameOver:
+		return name(event.Player) + " went out"
+	}
+	return event.Kind.String()
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+func TestCardStyle(t *testing.T) {
+	red := game.Card{Color: game.Red, Type: game.Number, Value: 7}
+	wild := game.Card{Color: game.Wild, Type: game.WildDrawFour}
+
+	if got := styler(false).card(red); got != "R7" {
+		t.Errorf("Expected plain R7, got %q", got)
+	}
+	if got := styler(true).card(red); got != bold+colorCodes[game.Red]+"R7"+reset {
+		t.Errorf("Expected R7 in red, got %q", got)
+	}
+
+	// Every letter of a wild card gets its own color
+	if got := styler(true).card(wild); strings.Count(got, reset) != 3 {
+		t.Errorf("Expected W+4 in three colors, got %q", got)
+	}
+}
+
+func TestScreen(t *testing.T) {
+	state, err := game.NewBuilder("Alice", "Bob").
+		Hand(0, "R7 W+4").
+		Hand(1, "B3").
+		Discard("R5").
+		Played(1).
+		CalledUno(1).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	screen := styler(false).screen(state.View(0), []string{"Alice", "Bob"}, []string{"Bob played B3"}, "Hello")
+	for _, want := range []string{"Alice (you)", "UNO!", "Discard R5", "Color Red", "Bob played B3", "1:R7  2:W+4", "Your turn", "Hello"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("Expected the screen to show %q, got:\n%s", want, screen)
+		}
+	}
+	if strings.Contains(screen, "\x1b[") {
+		t.Error("Expected no escape sequences without colors")
+	}
+
+	// Partners' hands show when the rules allow it
+	state, err = game.NewBuilder("A", "B", "C", "D").
+		Options(game.GameOptions{Teams: true, PartnersSeeHands: true}).
+		Hand(0, "R1").Hand(1, "R2").Hand(2, "G7 G8").Hand(3, "R4").
+		Discard("R5").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen = styler(false).screen(state.View(0), []string{"A", "B", "C", "D"}, nil, "")
+	if !strings.Contains(screen, "C (partner)") || !strings.Contains(screen, "G7  G8") {
+		t.Errorf("Expected to see the partner's hand, got:\n%s", screen)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	names := []string{"Alice", "Bob"}
+	tests := []struct {
+		event game.Event
+		want  string
+	}{
+		{game.Event{Kind: game.EventPlay, Player: 1, Card: game.Card{Color: game.Blue, Type: game.Skip}}, "Bob played BS"},
+		{game.Event{Kind: game.EventPlay, Player: 0, Card: game.Card{Color: game.Wild, Type: game.WildCard}, Color: game.Green}, "Alice played W and chose Green"},
+		{game.Event{Kind: game.EventPenalty, Player: 1, Count: 2}, "Bob took 2 cards"},
+		{game.Event{Kind: game.EventSwapHands, Player: 0, Target: 1}, "Alice swapped hands with Bob"},
+	}
+
+	for _, tt := range tests {
+		if got := styler(false).describe(tt.event, names); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
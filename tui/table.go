@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+)
+
+// maxBotMoves bounds the moves bots make in a row, in case they never hand the turn back
+const maxBotMoves = 10000
+
+// Table is one game shared by the people and bots seated at it. Every person plays
+// through the Game of their seat, and the bots move as soon as the people's moves
+// are made. It is safe for concurrent use
+type Table struct {
+	mu    sync.Mutex
+	names []string
+	table *bot.Table
+
+	listeners map[int]func(game.Event)
+	next      int
+}
+
+// NewTable deals a game. Seats holds the bot name for every bot seat and an empty
+// string for people, names holds the people's names. The seed decides the deal,
+// every shuffle and the bots' choices
+func NewTable(names []string, seats []string, opts game.GameOptions, seed uint64) (*Table, error) {
+	if len(names) != len(seats) {
+		return nil, fmt.Errorf("expected %d names, got %d", len(seats), len(names))
+	}
+
+	n := len(seats)
+	players := make([]*game.Player, n)
+	bots := make([]bot.Bot, n)
+	names = append([]string(nil), names...)
+	for seat, name := range seats {
+		if name == "" {
+			continue
+		}
+
+		b, err := bot.ByName(name, seed*uint64(n)+uint64(seat))
+		if err != nil {
+			return nil, err
+		}
+		bots[seat] = b
+		names[seat] = b.Name()
+	}
+	names = uniqueNames(names)
+	for i, name := range names {
+		players[i] = game.NewPlayer(name)
+	}
+
+	state, err := game.NewGameStateWithRandom(players, opts, rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)))
+	if err != nil {
+		return nil, err
+	}
+
+	rules := game.NewGameRules()
+	t := &Table{names: names, listeners: make(map[int]func(game.Event))}
+	rules.Subscribe(t.emit)
+
+	t.table, err = bot.NewTable(state, rules, bots)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bots may have something to do before the people, like choosing a color
+	if _, err := t.table.Run(maxBotMoves); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// NewLocal deals a game between the named person in seat 0 and the bots
+func NewLocal(name string, opponents []string, opts game.GameOptions, seed uint64) (Game, error) {
+	if len(opponents) == 0 {
+		return nil, errors.New("at least one opponent is required")
+	}
+
+	names := make([]string, len(opponents)+1)
+	names[0] = name
+	t, err := NewTable(names, append([]string{""}, opponents...), opts, seed)
+	if err != nil {
+		return nil, err
+	}
+	return t.Seat(0), nil
+}
+
+// Seat returns the game as the person at the seat plays it
+func (t *Table) Seat(seat int) Game {
+	return &seatGame{table: t, seat: seat}
+}
+
+// Over checks if the game has ended
+func (t *Table) Over() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.table.State.Phase == game.PhaseGameOver
+}
+
+// Leave hands the seat of a person who left to a bot, so the game goes on for the others
+func (t *Table) Leave(seat int, b bot.Bot) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if observer, ok := b.(bot.Observer); ok {
+		t.table.Rules.Subscribe(observer.Observe)
+	}
+	t.table.Seats[seat] = b
+
+	_, err := t.table.Run(maxBotMoves)
+	return err
+}
+
+// apply makes a person's moves, then lets the bots play until a person has to act
+// or the game is over
+func (t *Table) apply(seat int, moves []game.Move) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.table.Seats[seat] != nil {
+		return errors.New("a bot plays this seat")
+	}
+
+	for _, move := range moves {
+		if move.Player != seat {
+			return errors.New("moves can only be made for your own seat")
+		}
+		if err := t.table.Rules.Apply(t.table.State, move); err != nil {
+			return err
+		}
+	}
+
+	_, err := t.table.Run(maxBotMoves)
+	return err
+}
+
+func (t *Table) view(seat int) game.PlayerView {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.table.State.View(seat)
+}
+
+func (t *Table) watch(listener func(game.Event)) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := t.next
+	t.next++
+	t.listeners[id] = listener
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.listeners, id)
+	}
+}
+
+// emit passes an event on, the lock is held by the move that caused it
+func (t *Table) emit(event game.Event) {
+	for _, listener := range t.listeners {
+		listener(event)
+	}
+}
+
+// seatGame is the Game of one seat at a table
+type seatGame struct {
+	table *Table
+	seat  int
+}
+
+func (g *seatGame) Names() []string                        { return g.table.names }
+func (g *seatGame) Seat() int                              { return g.seat }
+func (g *seatGame) View() game.PlayerView                  { return g.table.view(g.seat) }
+func (g *seatGame) Apply(moves ...game.Move) error         { return g.table.apply(g.seat, moves) }
+func (g *seatGame) Watch(listener func(game.Event)) func() { return g.table.watch(listener) }
+
+// uniqueNames numbers repeated names, so two greedy bots become greedy and greedy 2
+func uniqueNames(names []string) []string {
+	seen := make(map[string]int)
+	unique := make([]string, len(names))
+	for i, name := range names {
+		seen[name]++
+		unique[i] = name
+		if seen[name] > 1 {
+			unique[i] = name + " " + strconv.Itoa(seen[name])
+		}
+	}
+	return unique
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/vtigo/uno-clone/bot"
+	"github.com/vtigo/uno-clone/game"
+)
+
+func TestLocal(t *testing.T) {
+	g, err := NewLocal("Me", []string{"greedy"}, game.GameOptions{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := g.Names(); len(names) != 2 || names[0] != "Me" {
+		t.Errorf("Expected me and the bot, got %v", names)
+	}
+
+	events := 0
+	stop := g.Watch(func(game.Event) { events++ })
+	defer stop()
+
+	// Whenever Apply returns the game waits on me or is over
+	for moves := 0; g.View().Phase != game.PhaseGameOver; moves++ {
+		view := g.View()
+		if len(view.LegalMoves) == 0 {
+			t.Fatalf("Expected the game to wait on me, got %+v", view)
+		}
+		if moves > 2000 {
+			t.Fatal("Expected the game to end")
+		}
+		if err := g.Apply(view.LegalMoves[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if events == 0 {
+		t.Error("Expected to watch the game's events")
+	}
+}
+
+func TestTable(t *testing.T) {
+	table, err := NewTable([]string{"Ann", "Bo"}, []string{"", ""}, game.GameOptions{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ann, bo := table.Seat(0), table.Seat(1)
+
+	events := 0
+	stop := bo.Watch(func(game.Event) { events++ })
+
+	// Both people play the same game from their own seat
+	view := ann.View()
+	if err := ann.Apply(view.LegalMoves[0]); err != nil {
+		t.Fatal(err)
+	}
+	if events == 0 || bo.View().CurrentPlayer != ann.View().CurrentPlayer {
+		t.Error("Expected Bo to see Ann's move")
+	}
+
+	stop()
+	before := events
+	if err := table.Seat(table.view(0).CurrentPlayer).Apply(game.Move{Kind: game.MoveDraw, Player: table.view(0).CurrentPlayer}); err != nil {
+		t.Fatal(err)
+	}
+	if events != before {
+		t.Error("Expected no events after stopping")
+	}
+
+	// Once Bo leaves a bot plays for them until the game ends
+	if err := table.Leave(1, bot.New(bot.Medium, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := bo.Apply(game.Move{Kind: game.MoveDraw, Player: 1}); err == nil {
+		t.Error("Expected error for a move on a bot's seat")
+	}
+	for moves := 0; !table.Over(); moves++ {
+		if moves > 2000 {
+			t.Fatal("Expected the game to end")
+		}
+		if err := ann.Apply(ann.View().LegalMoves[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTableErrors(t *testing.T) {
+	if _, err := NewLocal("Me", nil, game.GameOptions{}, 1); err == nil {
+		t.Error("Expected error without opponents")
+	}
+	if _, err := NewLocal("Me", []string{"nobody"}, game.GameOptions{}, 1); err == nil {
+		t.Error("Expected error for an unknown bot")
+	}
+	if _, err := NewTable([]string{"Me"}, []string{"", "greedy"}, game.GameOptions{}, 1); err == nil {
+		t.Error("Expected error for a missing name")
+	}
+
+	g, err := NewLocal("Me", []string{"random"}, game.GameOptions{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Apply(game.Move{Kind: game.MoveDraw, Player: 1}); err == nil {
+		t.Error("Expected error for a move for the bot's seat")
+	}
+}
+
+func TestUniqueNames(t *testing.T) {
+	names := uniqueNames([]string{"Me", "greedy", "greedy", "random"})
+	if names[1] != "greedy" || names[2] != "greedy 2" || names[3] != "random" {
+		t.Errorf("Expected repeated names to be numbered, got %v", names)
+	}
+}
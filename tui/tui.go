@@ -0,0 +1,65 @@
+// Package tui is a text-mode front end for playing in a terminal, for machines
+// where the game window cannot open. It draws the table with ANSI colors and reads
+// one command per line, against bots on this machine or people on a server
+package tui
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Game is a game the UI plays one seat of
+type Game interface {
+	Names() []string
+	Seat() int
+	View() game.PlayerView
+
+	// Apply makes the moves in order for the UI's seat, stopping at the first illegal one
+	Apply(moves ...game.Move) error
+
+	// Watch calls listener with the events of every move made in the game, until
+	// stop is called. The listener must not call back into the game
+	Watch(listener func(game.Event)) (stop func())
+}
+
+// Terminal is where the UI reads commands and draws the table
+// golang.org/x/term's Terminal fits, for sessions that need line editing
+type Terminal interface {
+	io.Writer
+	ReadLine() (string, error)
+}
+
+// LineCanceler is a Terminal that can stop waiting for a line. The UI uses it to
+// close as soon as another player ends the game, without waiting for a keypress
+type LineCanceler interface {
+	// ReadLineOrCancel reads the next line, or returns an empty one once cancel is closed
+	ReadLineOrCancel(cancel <-chan struct{}) (string, error)
+}
+
+// lineTerminal reads commands from a terminal that does its own line editing
+type lineTerminal struct {
+	io.Writer
+	lines *bufio.Scanner
+}
+
+// NewLineTerminal reads commands line by line from r, for standard input and tests
+func NewLineTerminal(r io.Reader, w io.Writer) Terminal {
+	return &lineTerminal{Writer: w, lines: bufio.NewScanner(r)}
+}
+
+// ReadLine prompts for and reads the next command
+func (t *lineTerminal) ReadLine() (string, error) {
+	if _, err := io.WriteString(t.Writer, "> "); err != nil {
+		return "", err
+	}
+
+	if !t.lines.Scan() {
+		if err := t.lines.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return t.lines.Text(), nil
+}
@@ -0,0 +1,266 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// logLines is how many recent events stay on screen
+const logLines = 6
+
+const help = `Commands:
+  3              play the third card of your hand
+  3 blue         play a wild card and choose its color, r, b, g and y work too
+  3 green Bob    play Wild Swap Hands, taking Bob's hand
+  3 uno          play a card and call UNO in one go
+  blue           choose the color for a wild card that started the game
+  d              draw a card
+  p              pass after drawing
+  u              call UNO
+  c Bob          catch Bob not calling UNO, c alone picks whoever can be caught
+  q              leave the game`
+
+// UI plays one seat of a game in a terminal
+type UI struct {
+	game  Game
+	term  Terminal
+	style styler
+
+	mu      sync.Mutex
+	log     []string
+	message string
+}
+
+// New creates a UI for the game, drawing with ANSI colors when ansi is set
+func New(g Game, t Terminal, ansi bool) *UI {
+	return &UI{game: g, term: t, style: styler(ansi)}
+}
+
+// Run draws the table and reads commands until the game is over or the player
+// leaves. Moves by other players redraw the table as they happen
+func (u *UI) Run() error {
+	redraw := make(chan struct{}, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	over := make(chan struct{})
+	var ended sync.Once
+
+	names := u.game.Names()
+	stop := u.game.Watch(func(event game.Event) {
+		if event.Kind == game.EventGameOver {
+			ended.Do(func() { close(over) })
+		}
+
+		u.mu.Lock()
+		u.log = append(u.log, u.style.describe(event, names))
+		if len(u.log) > logLines {
+			u.log = u.log[len(u.log)-logLines:]
+		}
+		u.mu.Unlock()
+
+		// The game is locked while it reports events, draw once the move is done
+		select {
+		case redraw <- struct{}{}:
+		default:
+		}
+	})
+	defer stop()
+
+	go func() {
+		for {
+			select {
+			case <-redraw:
+				u.draw()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	u.draw()
+	for {
+		// The game may have ended with the last move, whoever made it
+		if u.game.View().Phase == game.PhaseGameOver {
+			return nil
+		}
+
+		line, err := u.readLine(over)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Another player ended the game while we waited, the last redraw may not have happened
+		if u.game.View().Phase == game.PhaseGameOver {
+			u.draw()
+			return nil
+		}
+
+		quit, message := u.command(strings.TrimSpace(line))
+		if quit {
+			return nil
+		}
+
+		u.mu.Lock()
+		u.message = message
+		u.mu.Unlock()
+		u.draw()
+	}
+}
+
+// readLine reads the next command, giving up when the game is over if the terminal can
+func (u *UI) readLine(over <-chan struct{}) (string, error) {
+	if canceler, ok := u.term.(LineCanceler); ok {
+		return canceler.ReadLineOrCancel(over)
+	}
+	return u.term.ReadLine()
+}
+
+// draw writes the whole table
+func (u *UI) draw() {
+	view := u.game.View()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	io.WriteString(u.term, u.style.screen(view, u.game.Names(), u.log, u.message))
+}
+
+// command carries out one line typed by the player, returning whether they quit
+// and what to tell them
+func (u *UI) command(line string) (bool, string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, ""
+	}
+
+	view := u.game.View()
+	seat := view.Seat
+	var moves []game.Move
+
+	switch word := strings.ToLower(fields[0]); word {
+	case "q", "quit", "exit":
+		return true, ""
+	case "h", "help", "?":
+		return false, help
+	case "d", "draw":
+		moves = append(moves, game.Move{Kind: game.MoveDraw, Player: seat})
+	case "p", "pass":
+		moves = append(moves, game.Move{Kind: game.MovePass, Player: seat})
+	case "u", "uno":
+		moves = append(moves, game.Move{Kind: game.MoveCallUno, Player: seat})
+	case "c", "challenge":
+		move, err := u.challenge(view, fields[1:])
+		if err != nil {
+			return false, sentence(err)
+		}
+		moves = append(moves, move)
+	default:
+		index, err := strconv.Atoi(word)
+		if err != nil {
+			// A color alone resolves a wild card that started the game
+			move, err := u.choice(game.Move{Kind: game.MoveChooseColor, Player: seat}, fields)
+			if err != nil {
+				return false, fmt.Sprintf("Unknown command %q, h for help", line)
+			}
+			moves = append(moves, move)
+			break
+		}
+
+		hand := view.MyHand()
+		if index < 1 || index > len(hand) {
+			return false, fmt.Sprintf("You have no card %d", index)
+		}
+
+		move := game.Move{Kind: game.MovePlay, Player: seat, CardIndex: index - 1}
+		rest := fields[1:]
+		callUno := len(rest) > 0 && strings.EqualFold(rest[len(rest)-1], "uno")
+		if callUno {
+			rest = rest[:len(rest)-1]
+		}
+
+		if hand[index-1].Color == game.Wild {
+			if len(rest) == 0 {
+				return false, fmt.Sprintf("Which color? For example: %d blue", index)
+			}
+			if move, err = u.choice(move, rest); err != nil {
+				return false, sentence(err)
+			}
+		}
+
+		moves = append(moves, move)
+		if callUno {
+			moves = append(moves, game.Move{Kind: game.MoveCallUno, Player: seat})
+		}
+	}
+
+	if err := u.game.Apply(moves...); err != nil {
+		var illegal *game.IllegalMoveError
+		if errors.As(err, &illegal) {
+			return false, sentence(illegal.Reason)
+		}
+		return false, sentence(err)
+	}
+	return false, ""
+}
+
+// choice fills in the color, and the target if one is named, of a wild card move
+func (u *UI) choice(move game.Move, fields []string) (game.Move, error) {
+	if err := move.Color.UnmarshalText([]byte(fields[0])); err != nil || move.Color == game.Wild {
+		return move, fmt.Errorf("unknown color %q, pick red, blue, green or yellow", fields[0])
+	}
+
+	if len(fields) > 1 {
+		target, err := u.seatOf(strings.Join(fields[1:], " "))
+		if err != nil {
+			return move, err
+		}
+		move.Target = target
+	} else {
+		// Without a name, swap with the first other player
+		move.Target = (move.Player + 1) % len(u.game.Names())
+	}
+	return move, nil
+}
+
+// challenge finds the player to catch, whoever can be caught when nobody is named
+func (u *UI) challenge(view game.PlayerView, fields []string) (game.Move, error) {
+	if len(fields) > 0 {
+		target, err := u.seatOf(strings.Join(fields, " "))
+		return game.Move{Kind: game.MoveChallenge, Player: view.Seat, Target: target}, err
+	}
+
+	for _, move := range view.LegalMoves {
+		if move.Kind == game.MoveChallenge {
+			return move, nil
+		}
+	}
+	return game.Move{}, errors.New("nobody can be caught right now")
+}
+
+// seatOf finds a player by name, ignoring case
+func (u *UI) seatOf(name string) (int, error) {
+	for i, n := range u.game.Names() {
+		if strings.EqualFold(n, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("nobody is called %q", name)
+}
+
+// sentence turns an error into a message for the player
+func sentence(err error) string {
+	text := err.Error()
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// scripted types the commands one after the other
+type scripted struct {
+	mu    sync.Mutex
+	lines []string
+	out   strings.Builder
+}
+
+func (s *scripted) ReadLine() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.lines) == 0 {
+		return "", io.EOF
+	}
+	line := s.lines[0]
+	s.lines = s.lines[1:]
+	return line, nil
+}
+
+func (s *scripted) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.out.Write(p)
+}
+
+func (s *scripted) output() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.out.String()
+}
+
+// fixed is a game set up with the builder, nobody else moves
+type fixed struct {
+	mu       sync.Mutex
+	state    *game.GameState
+	rules    *game.GameRules
+	listener func(game.Event)
+}
+
+func newFixed(t *testing.T, b *game.Builder) *fixed {
+	state, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fixed{state: state, rules: game.NewGameRules()}
+	f.rules.Subscribe(func(event game.Event) {
+		if f.listener != nil {
+			f.listener(event)
+		}
+	})
+	return f
+}
+
+func (f *fixed) Names() []string { return []string{"Alice", "Bob"} }
+func (f *fixed) Seat() int       { return 0 }
+func (f *fixed) View() game.PlayerView {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state.View(0)
+}
+
+func (f *fixed) Apply(moves ...game.Move) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, move := range moves {
+		if err := f.rules.Apply(f.state, move); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fixed) Watch(listener func(game.Event)) func() {
+	f.listener = listener
+	return func() { f.listener = nil }
+}
+
+func run(t *testing.T, g Game, lines ...string) string {
+	term := &scripted{lines: lines}
+	if err := New(g, term, false).Run(); err != nil {
+		t.Fatalf("Expected the UI to run, got %v", err)
+	}
+	return term.output()
+}
+
+func TestPlayCommands(t *testing.T) {
+	g := newFixed(t, game.NewBuilder("Alice", "Bob").Hand(0, "G2 R7 B1 W").Hand(1, "R3 B4 B5").Discard("R5"))
+
+	out := run(t, g, "2")
+	if top, _ := g.state.DiscardPile.Top(); top != (game.Card{Color: game.Red, Type: game.Number, Value: 7}) {
+		t.Errorf("Expected R7 to be played, got %v", top)
+	}
+	if !strings.Contains(out, "Alice played R7") {
+		t.Errorf("Expected the play in the log, got:\n%s", out)
+	}
+
+	// Bob's turn now, it goes back to Alice after his play
+	g.rules.Apply(g.state, game.Move{Kind: game.MovePlay, Player: 1, CardIndex: 0})
+
+	out = run(t, g, "2", "2 purple", "2 b")
+	if !strings.Contains(out, "Which color? For example: 2 blue") || !strings.Contains(out, `Unknown color "purple"`) {
+		t.Errorf("Expected to be asked for a color, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Alice played W and chose Blue") || g.state.ActiveColor != game.Blue {
+		t.Errorf("Expected the wild to be played for blue, got:\n%s", out)
+	}
+
+	// The rules explain why a card does not fit
+	g.rules.Apply(g.state, game.Move{Kind: game.MovePlay, Player: 1, CardIndex: 0})
+	out = run(t, g, "1")
+	if g.state.Players[0].HandSize() != 2 || !strings.Contains(out, "Card cannot be played on top of the current discard pile") {
+		t.Errorf("Expected G2 to be refused with the rules' reason, got:\n%s", out)
+	}
+}
+
+func TestUnoCommands(t *testing.T) {
+	g := newFixed(t, game.NewBuilder("Alice", "Bob").Hand(0, "R7 R8").Hand(1, "B3 B4").Discard("R5").Played(0))
+
+	run(t, g, "1 uno")
+	if !g.state.Players[0].HasCalledUno {
+		t.Error("Expected UNO to be called with the play")
+	}
+
+	g = newFixed(t, game.NewBuilder("Alice", "Bob").Hand(0, "R7 R8").Hand(1, "B3").Discard("R5").Played(1))
+	out := run(t, g, "c", "c")
+	if g.state.Players[1].HandSize() != 3 {
+		t.Errorf("Expected Bob to be caught, got %d cards", g.state.Players[1].HandSize())
+	}
+	if !strings.Contains(out, "Nobody can be caught right now") {
+		t.Errorf("Expected nobody to be left to catch, got:\n%s", out)
+	}
+}
+
+func TestOtherCommands(t *testing.T) {
+	g := newFixed(t, game.NewBuilder("Alice", "Bob").Hand(0, "B1").Hand(1, "B3").Discard("W").DrawPile("G4").Phase(game.PhaseColorSelection))
+
+	out := run(t, g, "h", "dance", "9", "green", "d", "p", "q", "d")
+	for _, want := range []string{"Commands:", `Unknown command "dance"`, "You have no card 9", "Alice chose Green", "Alice drew a card", "Alice passed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q, got:\n%s", want, out)
+		}
+	}
+
+	// Quitting leaves the last command unread
+	if g.state.Players[0].HandSize() != 2 {
+		t.Errorf("Expected one card drawn, got %d cards", g.state.Players[0].HandSize())
+	}
+}
+
+// Test that the UI closes after the move that ends the game, without another line
+func TestGameOverEndsRun(t *testing.T) {
+	g := newFixed(t, game.NewBuilder("Alice", "Bob").Hand(0, "R7").Hand(1, "B3 B4").Discard("R5").Played(0).CalledUno(0))
+
+	term := &scripted{lines: []string{"1", "d"}}
+	if err := New(g, term, false).Run(); err != nil {
+		t.Fatalf("Expected the UI to run, got %v", err)
+	}
+	if len(term.lines) != 1 || !strings.Contains(term.output(), "Game over, Alice won") {
+		t.Errorf("Expected the UI to stop right after the winning play, %d lines left and output:\n%s", len(term.lines), term.output())
+	}
+}
+
+// canceling waits for lines that never come, until it is cancelled
+type canceling struct {
+	scripted
+	reading chan struct{}
+}
+
+func (c *canceling) ReadLineOrCancel(cancel <-chan struct{}) (string, error) {
+	c.reading <- struct{}{}
+	<-cancel
+	return "", nil
+}
+
+// Test that the UI closes when another player ends the game while it waits for a line
+func TestOpponentEndsGame(t *testing.T) {
+	g := newFixed(t, game.NewBuilder("Alice", "Bob").Hand(0, "R7 R8").Hand(1, "B3").Discard("B5").Turn(1).Played(1).CalledUno(1))
+
+	term := &canceling{reading: make(chan struct{})}
+	done := make(chan error, 1)
+	go func() {
+		done <- New(g, term, false).Run()
+	}()
+
+	<-term.reading
+	if err := g.Apply(game.Move{Kind: game.MovePlay, Player: 1, CardIndex: 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the UI to close when Bob won")
+	}
+	if !strings.Contains(term.output(), "Game over, Bob won") {
+		t.Errorf("Expected the final table to be drawn, got:\n%s", term.output())
+	}
+}
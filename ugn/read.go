@@ -0,0 +1,408 @@
+package ugn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Read reads a game written in UNO Game Notation. Every move is checked by playing
+// it through game.GameRules, so a game that loads is a game that could be played
+// Unknown header tags are ignored
+func Read(r io.Reader) (*Game, error) {
+	tags := make(map[string]string)
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "["):
+			if len(lines) > 0 {
+				return nil, fmt.Errorf("line %d: header tag after the moves", number)
+			}
+
+			name, value, err := parseTag(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", number, err)
+			}
+			if _, ok := tags[name]; ok {
+				return nil, fmt.Errorf("line %d: tag %s appears twice", number, name)
+			}
+			tags[name] = value
+		default:
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	replay, names, err := readStart(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	state, rules := replay.Playback()
+	for _, line := range lines {
+		if err := readLine(line, state, rules, replay, names); err != nil {
+			return nil, err
+		}
+	}
+
+	if result, ok := tags["Result"]; ok {
+		got := unfinished
+		if winner := state.Winner(); winner != -1 {
+			got = names[winner]
+		}
+		if result != got {
+			return nil, fmt.Errorf("the result is %s but the moves end with %s", result, got)
+		}
+	}
+
+	g := &Game{Event: tags["Event"], Date: tags["Date"], Seed: tags["Seed"], Replay: replay}
+	if g.Event == "?" {
+		g.Event = ""
+	}
+	if g.Date == "????.??.??" {
+		g.Date = ""
+	}
+	return g, nil
+}
+
+// parseTag reads a header tag like [Name "value"]
+func parseTag(line string) (string, string, error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", fmt.Errorf("unterminated tag %q", line)
+	}
+
+	name, quoted, _ := strings.Cut(line[1:len(line)-1], " ")
+	quoted = strings.TrimSpace(quoted)
+	if name == "" || len(quoted) < 2 || quoted[0] != '"' || quoted[len(quoted)-1] != '"' {
+		return "", "", fmt.Errorf("invalid tag %q", line)
+	}
+
+	var value strings.Builder
+	escaped := false
+	for _, r := range quoted[1 : len(quoted)-1] {
+		switch {
+		case escaped:
+			value.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			return "", "", fmt.Errorf("unescaped quote in tag %q", line)
+		default:
+			value.WriteRune(r)
+		}
+	}
+	if escaped {
+		return "", "", fmt.Errorf("invalid tag %q", line)
+	}
+	return name, value.String(), nil
+}
+
+// readStart builds the replay's starting deal from the header tags and returns it
+// with the player names
+func readStart(tags map[string]string) (*game.Replay, []string, error) {
+	for _, name := range []string{"Players", "Turn", "Color", "Discard", "DrawPile"} {
+		if _, ok := tags[name]; !ok {
+			return nil, nil, fmt.Errorf("missing %s tag", name)
+		}
+	}
+
+	opts, err := parseRules(tags["Rules"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := strings.Split(tags["Players"], ",")
+	seen := make(map[string]bool)
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+		if !validName(names[i]) || seen[names[i]] {
+			return nil, nil, fmt.Errorf("invalid player name %q", names[i])
+		}
+		seen[names[i]] = true
+	}
+
+	if opts.Teams && len(names) != 4 {
+		return nil, nil, errors.New("four players are required for a team game")
+	} else if !opts.Teams && len(names) != 2 {
+		return nil, nil, errors.New("two players are required")
+	}
+
+	state := &game.GameState{
+		Players:      make([]*game.Player, len(names)),
+		LastPlayedBy: -1,
+		Options:      opts,
+		Reversed:     tags["Reversed"] == "yes",
+	}
+
+	var dealt []game.Card
+	for i, name := range names {
+		hand, err := readCards(tags, "Hand"+strconv.Itoa(i+1))
+		if err != nil {
+			return nil, nil, err
+		}
+		dealt = append(dealt, hand...)
+
+		player := game.NewPlayer(name)
+		player.Hand = game.NewHand(hand...)
+		state.Players[i] = player
+	}
+
+	discard, err := readCards(tags, "Discard")
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(discard) == 0 {
+		return nil, nil, errors.New("the discard pile is empty")
+	}
+	drawPile, err := readCards(tags, "DrawPile")
+	if err != nil {
+		return nil, nil, err
+	}
+	state.DiscardPile = game.NewDeckOf(discard)
+	state.DrawPile = game.NewDeckOf(drawPile)
+	dealt = append(append(dealt, discard...), drawPile...)
+
+	if !sameCards(dealt, game.NewDeckWithOptions(opts).Cards()) {
+		return nil, nil, errors.New("the cards dealt are not the deck of the rules")
+	}
+
+	state.CurrentPlayer = indexOf(names, tags["Turn"])
+	if state.CurrentPlayer == -1 {
+		return nil, nil, fmt.Errorf("unknown player %q in the Turn tag", tags["Turn"])
+	}
+	state.Players[state.CurrentPlayer].IsMyTurn = true
+
+	if err := state.ActiveColor.UnmarshalText([]byte(tags["Color"])); err != nil {
+		return nil, nil, err
+	}
+
+	switch tags["Phase"] {
+	case phasePlay, "":
+		state.Phase = game.PhasePlay
+	case phaseChoose:
+		state.Phase = game.PhaseColorSelection
+	default:
+		return nil, nil, fmt.Errorf("unknown phase %q", tags["Phase"])
+	}
+
+	if opts.Teams {
+		state.Teams = make([]int, len(names))
+		for i := range names {
+			state.Teams[i] = i % 2
+		}
+	}
+
+	if err := state.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	replay := &game.Replay{Start: state}
+	for i := 1; ; i++ {
+		name := "Shuffle" + strconv.Itoa(i)
+		if _, ok := tags[name]; !ok {
+			break
+		}
+
+		cards, err := readCards(tags, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		replay.Shuffles = append(replay.Shuffles, cards)
+	}
+
+	return replay, names, nil
+}
+
+// readCards reads a header tag holding cards in compact notation
+func readCards(tags map[string]string, name string) ([]game.Card, error) {
+	text, ok := tags[name]
+	if !ok {
+		return nil, fmt.Errorf("missing %s tag", name)
+	}
+
+	cards, err := game.ParseHand(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s tag: %v", name, err)
+	}
+	return cards, nil
+}
+
+// sameCards checks if two piles hold the same cards in any order
+func sameCards(a, b []game.Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := make(map[game.Card]int)
+	for _, card := range a {
+		counts[card]++
+	}
+	for _, card := range b {
+		counts[card]--
+		if counts[card] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// readLine plays the moves of one numbered round on the state
+func readLine(line string, state *game.GameState, rules *game.GameRules, replay *game.Replay, names []string) error {
+	// The round number is only there for people
+	if first, rest, _ := strings.Cut(line, " "); strings.HasSuffix(first, ".") {
+		if _, err := strconv.Atoi(strings.TrimSuffix(first, ".")); err == nil {
+			line = rest
+		}
+	}
+
+	for _, turn := range strings.Split(line, ";") {
+		turn = strings.TrimSpace(turn)
+		seat := moverOf(turn, names)
+		if seat == -1 {
+			return fmt.Errorf("%q: unknown player", turn)
+		}
+
+		for _, text := range strings.Split(strings.TrimSpace(turn[len(names[seat]):]), ",") {
+			move, err := parseMove(strings.TrimSpace(text), seat, state, names)
+			if err == nil {
+				err = rules.Apply(state, move)
+			}
+			if err != nil {
+				return fmt.Errorf("%q: %v", turn, err)
+			}
+			replay.Add(move)
+		}
+	}
+	return nil
+}
+
+// moverOf returns the seat of the player whose name starts the turn, the longest
+// name matching if one name starts another
+func moverOf(turn string, names []string) int {
+	seat := -1
+	for i, name := range names {
+		if (turn == name || strings.HasPrefix(turn, name+" ")) && (seat == -1 || len(name) > len(names[seat])) {
+			seat = i
+		}
+	}
+	return seat
+}
+
+// parseMove reads one move made by the player at the seat
+func parseMove(text string, seat int, state *game.GameState, names []string) (game.Move, error) {
+	move := game.Move{Player: seat}
+
+	switch word, rest, _ := strings.Cut(text, " "); strings.ToLower(word) {
+	case "":
+		return move, errors.New("missing move")
+	case "draw":
+		move.Kind = game.MoveDraw
+		return move, nil
+	case "pass":
+		move.Kind = game.MovePass
+		return move, nil
+	case "uno":
+		move.Kind = game.MoveCallUno
+		return move, nil
+	case "challenge":
+		move.Kind = game.MoveChallenge
+		move.Target = indexOf(names, strings.TrimSpace(rest))
+		if move.Target == -1 {
+			return move, fmt.Errorf("unknown player %q to challenge", rest)
+		}
+		return move, nil
+	}
+
+	code, choice, hasChoice := strings.Cut(text, "{")
+	if hasChoice {
+		if !strings.HasSuffix(choice, "}") {
+			return move, fmt.Errorf("unterminated choice in %q", text)
+		}
+
+		var err error
+		move.Color, move.Target, err = parseChoice(strings.TrimSuffix(choice, "}"), names)
+		if err != nil {
+			return move, err
+		}
+	}
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		move.Kind = game.MoveChooseColor
+		return move, nil
+	}
+
+	card, err := game.ParseCard(code)
+	if err != nil {
+		return move, err
+	}
+	if card.Color == game.Wild && !hasChoice {
+		return move, fmt.Errorf("missing color for %s", code)
+	}
+
+	move.Kind = game.MovePlay
+	move.CardIndex = findCard(&state.Players[seat].Hand, card, state.HasDrawn && state.CurrentPlayer == seat)
+	if move.CardIndex == -1 {
+		return move, fmt.Errorf("%s does not hold %s", names[seat], code)
+	}
+	return move, nil
+}
+
+// parseChoice reads the color and swap target inside the braces after a wild card
+func parseChoice(text string, names []string) (game.CardColor, int, error) {
+	colorName, targetName, _ := strings.Cut(strings.TrimSpace(text), " ")
+
+	var color game.CardColor
+	if err := color.UnmarshalText([]byte(colorName)); err != nil {
+		return color, 0, err
+	}
+
+	targetName = strings.TrimSpace(targetName)
+	if targetName == "" {
+		return color, 0, nil
+	}
+
+	target := indexOf(names, targetName)
+	if target == -1 {
+		return color, 0, fmt.Errorf("unknown player %q to swap with", targetName)
+	}
+	return color, target, nil
+}
+
+// findCard returns the hand index of the card, searching from the end after a draw
+// since only the drawn card may be played then
+func findCard(hand *game.Hand, card game.Card, drawn bool) int {
+	for i := range hand.Len() {
+		at := i
+		if drawn {
+			at = hand.Len() - 1 - i
+		}
+		if hand.At(at) == card {
+			return at
+		}
+	}
+	return -1
+}
@@ -0,0 +1,139 @@
+package ugn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		names []string
+		opts  game.GameOptions
+	}{
+		{[]string{"Alice", "Bob"}, game.GameOptions{}},
+		{[]string{"Alice", "Bob"}, game.ModernOptions()},
+		{[]string{"Ann", "Bo", "Cy", "Di"}, game.GameOptions{Teams: true, PartnersSeeHands: true, SwapHandsCards: 2}},
+	}
+
+	for _, tt := range tests {
+		shuffled := false
+		for seed := uint64(1); seed <= 20; seed++ {
+			state, replay := recordRandomGame(t, tt.names, tt.opts, seed)
+			shuffled = shuffled || len(replay.Shuffles) > 0
+
+			text := writeGame(t, &Game{Event: "Test", Date: "2026.10.18", Seed: "1", Replay: replay})
+			g, err := Read(strings.NewReader(text))
+			if err != nil {
+				t.Fatalf("Expected the game to read back, got %v in:\n%s", err, text)
+			}
+
+			if g.Event != "Test" || g.Date != "2026.10.18" || g.Seed != "1" {
+				t.Errorf("Expected the header to read back, got %+v", g)
+			}
+
+			final, err := g.Replay.Final()
+			if err != nil {
+				t.Fatalf("Expected the read game to replay, got %v", err)
+			}
+			if final.Hash() != state.Hash() {
+				t.Errorf("Expected the read game to end like the recorded one with %+v, seed %d", tt.opts, seed)
+			}
+
+			if again := writeGame(t, g); again != text {
+				t.Errorf("Expected the read game to be written the same, got:\n%s\nwant:\n%s", again, text)
+			}
+		}
+
+		if !shuffled {
+			t.Errorf("Expected some game to shuffle with %+v", tt.opts)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	_, replay := recordRandomGame(t, []string{"Alice", "Bob"}, game.GameOptions{}, 3)
+	text := writeGame(t, &Game{Replay: replay})
+	header, moves, _ := strings.Cut(text, "\n\n")
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"missing tag", strings.Replace(text, `[Turn "`, `[Whose "`, 1)},
+		{"repeated tag", `[Event "a"]` + "\n" + text},
+		{"unterminated tag", strings.Replace(text, `[Event "?"]`, `[Event "?"`, 1)},
+		{"tag after moves", text + `[Event "b"]` + "\n"},
+		{"unknown rule", strings.Replace(text, `[Rules "`, `[Rules "jump-in `, 1)},
+		{"three players", strings.Replace(text, `[Players "Alice, Bob"]`, `[Players "Alice, Bob, Cy"]`, 1)},
+		{"missing card", strings.Replace(text, `[DrawPile "`, `[DrawPile "R0 `, 1)},
+		{"wrong result", strings.Replace(text, `[Result "`, `[Result "Nobody`, 1)},
+		{"unknown player", header + "\n\n1. Carol draw\n"},
+		{"illegal move", header + "\n\n1. Alice pass\n"},
+		{"unknown move", header + "\n\n1. Alice dance\n"},
+		{"card not held", header + "\n\n1. Alice W+4 {Red}, W+4 {Red}, W+4 {Red}, W+4 {Red}, W+4 {Red}\n"},
+		{"wild without color", header + "\n\n1. Alice W\n"},
+		{"truncated moves", header + "\n\n" + moves[:len(moves)/2]},
+	}
+
+	for _, tt := range tests {
+		if _, err := Read(strings.NewReader(tt.text)); err == nil {
+			t.Errorf("Expected error for %s", tt.name)
+		}
+	}
+}
+
+func TestReadByHand(t *testing.T) {
+	text := `[Players "Alice, Bob"]
+[Turn "Alice"]
+[Color "red"]
+[Hand1 "R7 W+4 B2 G3 G4 G5 G6"]
+[Hand2 "B3 B4 B5 B6 B7 B8 B9"]
+[Discard "R5"]
+[DrawPile "` + restOfDeck(t, "R7 W+4 B2 G3 G4 G5 G6 B3 B4 B5 B6 B7 B8 B9 R5") + `"]
+
+1. Alice r7 ; Bob draw, pass
+2. Alice W+4 {blue}, B2 ; Bob B3
+`
+	g, err := Read(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Expected the game to read, got %v", err)
+	}
+	if len(g.Replay.Moves) != 6 {
+		t.Errorf("Expected 6 moves, got %d", len(g.Replay.Moves))
+	}
+
+	final, err := g.Replay.Final()
+	if err != nil {
+		t.Fatal(err)
+	}
+	top, _ := final.DiscardPile.Top()
+	if top != (game.Card{Color: game.Blue, Type: game.Number, Value: 3}) {
+		t.Errorf("Expected B3 on top, got %v", top)
+	}
+}
+
+// restOfDeck returns the classic deck in notation without the given cards
+func restOfDeck(t *testing.T, dealt string) string {
+	cards, err := game.ParseHand(dealt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deck := game.NewDeckWithOptions(game.GameOptions{}).Cards()
+	for _, card := range cards {
+		for i := range deck {
+			if deck[i] == card {
+				deck = append(deck[:i], deck[i+1:]...)
+				break
+			}
+		}
+	}
+
+	text, err := game.FormatHand(deck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return text
+}
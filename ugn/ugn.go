@@ -0,0 +1,140 @@
+// Package ugn reads and writes games in UNO Game Notation, a text format in the
+// spirit of PGN for chess that is short enough to paste into a chat or a bug report
+//
+// A game starts with header tags, then lists the moves with one numbered line per
+// round. Every line holds the turns of the round separated by semicolons, each turn
+// being a player's name followed by their moves separated by commas:
+//
+//	[Event "Friday night"]
+//	[Date "2026.10.18"]
+//	[Players "Alice, Bob"]
+//	[Rules "shuffle-hands=1 custom-rule=draw-two"]
+//	[Seed "42"]
+//	[Result "Alice"]
+//	[Turn "Alice"]
+//	[Color "red"]
+//	[Phase "play"]
+//	[Hand1 "R7 BS G2 Y+2 W B0 G9"]
+//	[Hand2 "..."]
+//	[Discard "R5"]
+//	[DrawPile "... top card last"]
+//
+//	1. Alice R7 ; Bob draw, pass
+//	2. Alice W+4 {Blue} ; Bob B3, uno ; Alice challenge Bob
+//
+// Cards use the compact notation of game.ParseCard. Wild cards are followed by the
+// chosen color in braces, and Wild Swap Hands also by the target: WSW {Green Bob}.
+// A color chosen for a wild that started the discard pile stands alone: {Green}.
+// The other moves are draw, pass, uno and challenge followed by a name
+//
+// The starting deal is part of the header, and so is the outcome of every shuffle
+// during play as Shuffle1, Shuffle2... tags: the draw pile after a reshuffle, or the
+// hands in seat order after Wild Shuffle Hands. That makes games load back exactly
+package ugn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Game is a recorded game with the information of its header
+type Game struct {
+	Event  string // What the game was played for, "?" when unknown
+	Date   string // When the game was played as YYYY.MM.DD, "????.??.??" when unknown
+	Seed   string // The seed the game was dealt with, if any
+	Replay *game.Replay
+}
+
+// formatRules writes the house rules as key=value pairs, leaving out the defaults
+func formatRules(opts game.GameOptions) string {
+	var fields []string
+	if opts.ShuffleHandsCards > 0 {
+		fields = append(fields, "shuffle-hands="+strconv.Itoa(opts.ShuffleHandsCards))
+	}
+	if opts.SwapHandsCards > 0 {
+		fields = append(fields, "swap-hands="+strconv.Itoa(opts.SwapHandsCards))
+	}
+	if opts.CustomizableCards > 0 {
+		fields = append(fields, "customizable="+strconv.Itoa(opts.CustomizableCards))
+
+		rule, _ := opts.CustomRule.MarshalText()
+		fields = append(fields, "custom-rule="+string(rule))
+	}
+	if opts.Teams {
+		fields = append(fields, "teams")
+	}
+	if opts.PartnersSeeHands {
+		fields = append(fields, "partners-see-hands")
+	}
+	return strings.Join(fields, " ")
+}
+
+// parseRules reads house rules written by formatRules
+func parseRules(s string) (game.GameOptions, error) {
+	var opts game.GameOptions
+	for _, field := range strings.Fields(s) {
+		key, value, _ := strings.Cut(field, "=")
+
+		var err error
+		switch key {
+		case "shuffle-hands":
+			opts.ShuffleHandsCards, err = strconv.Atoi(value)
+		case "swap-hands":
+			opts.SwapHandsCards, err = strconv.Atoi(value)
+		case "customizable":
+			opts.CustomizableCards, err = strconv.Atoi(value)
+		case "custom-rule":
+			err = opts.CustomRule.UnmarshalText([]byte(value))
+		case "teams":
+			opts.Teams = true
+		case "partners-see-hands":
+			opts.PartnersSeeHands = true
+		default:
+			err = fmt.Errorf("unknown rule %q", key)
+		}
+
+		if err != nil {
+			return opts, fmt.Errorf("invalid rules %q: %v", s, err)
+		}
+	}
+	return opts, nil
+}
+
+// validName checks if a player's name can be written and read back unchanged
+func validName(name string) bool {
+	if strings.TrimSpace(name) != name || name == "" || strings.ContainsAny(name, ",;{}[]\"\\\n") {
+		return false
+	}
+
+	// A name must not look like a round number or a move
+	first, _, _ := strings.Cut(name, " ")
+	if _, err := strconv.Atoi(strings.TrimSuffix(first, ".")); err == nil {
+		return false
+	}
+	return true
+}
+
+// playerNames returns the names to write for the players, numbered when their own
+// names are missing, repeated or cannot be written
+func playerNames(state *game.GameState) []string {
+	names := make([]string, len(state.Players))
+	seen := make(map[string]bool)
+	usable := true
+	for i, player := range state.Players {
+		names[i] = player.Name
+		if !validName(player.Name) || seen[player.Name] {
+			usable = false
+		}
+		seen[player.Name] = true
+	}
+
+	if !usable {
+		for i := range names {
+			names[i] = fmt.Sprintf("P%d", i+1)
+		}
+	}
+	return names
+}
@@ -0,0 +1,243 @@
+package ugn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// Phase tag values
+const (
+	phasePlay   = "play"
+	phaseChoose = "choose-color"
+)
+
+// unfinished is the Result tag of a game nobody has won yet
+const unfinished = "*"
+
+// Write writes the game in UNO Game Notation
+// The replay has to start from a fresh deal, before anyone made a move
+func Write(w io.Writer, g *Game) error {
+	if g.Replay == nil || g.Replay.Start == nil {
+		return errors.New("the game has no replay")
+	}
+
+	start := g.Replay.Start
+	if err := checkFresh(start); err != nil {
+		return err
+	}
+
+	final, err := g.Replay.Final()
+	if err != nil {
+		return err
+	}
+
+	names := playerNames(start)
+	out := bufio.NewWriter(w)
+
+	result := unfinished
+	if winner := final.Winner(); winner != -1 {
+		result = names[winner]
+	}
+
+	phase := phasePlay
+	if start.Phase == game.PhaseColorSelection {
+		phase = phaseChoose
+	}
+
+	color, err := start.ActiveColor.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	writeTag(out, "Event", orDefault(g.Event, "?"))
+	writeTag(out, "Date", orDefault(g.Date, "????.??.??"))
+	writeTag(out, "Players", strings.Join(names, ", "))
+	writeTag(out, "Rules", formatRules(start.Options))
+	if g.Seed != "" {
+		writeTag(out, "Seed", g.Seed)
+	}
+	writeTag(out, "Result", result)
+	writeTag(out, "Turn", names[start.CurrentPlayer])
+	writeTag(out, "Color", string(color))
+	writeTag(out, "Phase", phase)
+	if start.Reversed {
+		writeTag(out, "Reversed", "yes")
+	}
+
+	for i, player := range start.Players {
+		if err := writeCards(out, "Hand"+strconv.Itoa(i+1), player.Hand.Cards()); err != nil {
+			return err
+		}
+	}
+	if err := writeCards(out, "Discard", start.DiscardPile.Cards()); err != nil {
+		return err
+	}
+	if err := writeCards(out, "DrawPile", start.DrawPile.Cards()); err != nil {
+		return err
+	}
+	for i, shuffle := range g.Replay.Shuffles {
+		if err := writeCards(out, "Shuffle"+strconv.Itoa(i+1), shuffle); err != nil {
+			return err
+		}
+	}
+
+	if err := writeMoves(out, g.Replay, names); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// checkFresh makes sure the game can be written: the notation only knows the deal
+// and the moves after it, not what happened before
+func checkFresh(state *game.GameState) error {
+	if state.Phase != game.PhasePlay && state.Phase != game.PhaseColorSelection {
+		return errors.New("the game has not been dealt or is already over")
+	}
+
+	view := state.View(state.CurrentPlayer)
+	fresh := state.LastPlayedBy == -1 && !state.HasDrawn
+	for i := range state.Players {
+		fresh = fresh && !view.HasPlayed[i] && !view.CalledUno[i]
+	}
+	if !fresh {
+		return errors.New("the replay must start from the deal, before the first move")
+	}
+	return nil
+}
+
+func orDefault(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// writeTag writes a header tag, escaping quotes and backslashes in the value
+func writeTag(w *bufio.Writer, name, value string) {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(value)
+	fmt.Fprintf(w, "[%s \"%s\"]\n", name, value)
+}
+
+// writeCards writes a header tag holding cards in compact notation
+func writeCards(w *bufio.Writer, name string, cards []game.Card) error {
+	text, err := game.FormatHand(cards)
+	if err != nil {
+		return err
+	}
+	writeTag(w, name, text)
+	return nil
+}
+
+// writeMoves writes the numbered rounds. Consecutive moves by the same player make
+// a turn, and a round starts whenever the player who moved first starts a turn
+func writeMoves(w *bufio.Writer, replay *game.Replay, names []string) error {
+	first := replay.Start.CurrentPlayer
+	round := 0
+	var turns []string
+	var moves []string
+	mover := -1
+
+	endTurn := func() {
+		if len(moves) > 0 {
+			turns = append(turns, names[mover]+" "+strings.Join(moves, ", "))
+		}
+		moves = nil
+	}
+	endRound := func() {
+		endTurn()
+		if len(turns) > 0 {
+			round++
+			fmt.Fprintf(w, "%d. %s\n", round, strings.Join(turns, " ; "))
+		}
+		turns = nil
+	}
+
+	w.WriteString("\n")
+	err := replay.Walk(func(i int, state *game.GameState, move game.Move) error {
+		text, err := formatMove(state, move, names)
+		if err != nil {
+			return fmt.Errorf("move %d: %v", i+1, err)
+		}
+
+		if move.Player != mover {
+			if move.Player == first {
+				endRound()
+			} else {
+				endTurn()
+			}
+			mover = move.Player
+		}
+		moves = append(moves, text)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	endRound()
+	return nil
+}
+
+// formatMove writes a move made on the state
+func formatMove(state *game.GameState, move game.Move, names []string) (string, error) {
+	if move.Player < 0 || move.Player >= len(state.Players) {
+		return "", fmt.Errorf("invalid player %d", move.Player)
+	}
+
+	switch move.Kind {
+	case game.MovePlay:
+		hand := &state.Players[move.Player].Hand
+		if move.CardIndex < 0 || move.CardIndex >= hand.Len() {
+			return "", fmt.Errorf("invalid card index %d", move.CardIndex)
+		}
+
+		card := hand.At(move.CardIndex)
+		code, err := card.Notation()
+		if err != nil {
+			return "", err
+		}
+		if card.Color != game.Wild {
+			return code, nil
+		}
+		return code + " " + formatChoice(move, card.Type == game.WildSwapHands, names), nil
+
+	case game.MoveChooseColor:
+		top, err := state.DiscardPile.Top()
+		if err != nil {
+			return "", err
+		}
+		return formatChoice(move, top.Type == game.WildSwapHands && state.LastPlayedBy != -1, names), nil
+
+	case game.MoveDraw:
+		return "draw", nil
+
+	case game.MovePass:
+		return "pass", nil
+
+	case game.MoveCallUno:
+		return "uno", nil
+
+	case game.MoveChallenge:
+		if move.Target < 0 || move.Target >= len(names) {
+			return "", fmt.Errorf("invalid challenge target %d", move.Target)
+		}
+		return "challenge " + names[move.Target], nil
+	}
+	return "", fmt.Errorf("unknown move kind %d", int(move.Kind))
+}
+
+// formatChoice writes the color chosen for a wild card and the swap target if any,
+// like {Blue} or {Green Bob}
+func formatChoice(move game.Move, swap bool, names []string) string {
+	choice := move.Color.String()
+	if swap && move.Target >= 0 && move.Target < len(names) {
+		choice += " " + names[move.Target]
+	}
+	return "{" + choice + "}"
+}
@@ -0,0 +1,106 @@
+package ugn
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/vtigo/uno-clone/game"
+)
+
+// recordRandomGame plays a game with random legal moves, recording it
+func recordRandomGame(t *testing.T, names []string, opts game.GameOptions, seed uint64) (*game.GameState, *game.Replay) {
+	players := make([]*game.Player, len(names))
+	for i, name := range names {
+		players[i] = game.NewPlayer(name)
+	}
+
+	r := rand.New(rand.NewPCG(seed, seed))
+	state, err := game.NewGameStateWithRandom(players, opts, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := game.NewGameRules()
+	replay := game.Record(state, rules)
+	for state.Phase != game.PhaseGameOver && len(replay.Moves) < 2000 {
+		legal := game.LegalMoves(state, state.CurrentPlayer)
+		move := legal[r.IntN(len(legal))]
+		if err := rules.Apply(state, move); err != nil {
+			t.Fatal(err)
+		}
+		replay.Add(move)
+	}
+	return state, replay
+}
+
+func writeGame(t *testing.T, g *Game) string {
+	var buf bytes.Buffer
+	if err := Write(&buf, g); err != nil {
+		t.Fatalf("Expected the game to be written, got %v", err)
+	}
+	return buf.String()
+}
+
+func TestWrite(t *testing.T) {
+	state, replay := recordRandomGame(t, []string{"Alice", "Bob"}, game.ModernOptions(), 1)
+	text := writeGame(t, &Game{Date: "2026.10.18", Seed: "1", Replay: replay})
+
+	winner := "*"
+	if state.Winner() != -1 {
+		winner = state.Players[state.Winner()].Name
+	}
+
+	for _, tag := range []string{
+		`[Event "?"]`,
+		`[Date "2026.10.18"]`,
+		`[Players "Alice, Bob"]`,
+		`[Seed "1"]`,
+		`[Result "` + winner + `"]`,
+		`[Hand1 "`,
+		`[DrawPile "`,
+	} {
+		if !strings.Contains(text, tag) {
+			t.Errorf("Expected the header to contain %s, got:\n%s", tag, text)
+		}
+	}
+
+	if !strings.Contains(text, "\n\n1. ") || !strings.Contains(text, "\n2. ") {
+		t.Errorf("Expected numbered rounds, got:\n%s", text)
+	}
+}
+
+func TestWriteNames(t *testing.T) {
+	_, replay := recordRandomGame(t, []string{"Sam", "Sam"}, game.GameOptions{}, 1)
+	text := writeGame(t, &Game{Replay: replay})
+	if !strings.Contains(text, `[Players "P1, P2"]`) {
+		t.Errorf("Expected repeated names to be numbered, got:\n%s", text)
+	}
+
+	for _, name := range []string{"", " Sam", "Sam, Jo", "{Sam}", "12. Sam", "Sam \"the man\""} {
+		if validName(name) {
+			t.Errorf("Expected %q to be an invalid name", name)
+		}
+	}
+	for _, name := range []string{"Sam", "Player 1", "Jo-Ann", "R2-D2"} {
+		if !validName(name) {
+			t.Errorf("Expected %q to be a valid name", name)
+		}
+	}
+}
+
+func TestWriteMidGame(t *testing.T) {
+	state, replay := recordRandomGame(t, []string{"Alice", "Bob"}, game.GameOptions{}, 1)
+
+	// A replay has to start from the deal
+	replay.Start = state
+	var buf bytes.Buffer
+	if err := Write(&buf, &Game{Replay: replay}); err == nil {
+		t.Error("Expected error for a replay that does not start from the deal")
+	}
+
+	if err := Write(&buf, &Game{}); err == nil {
+		t.Error("Expected error for a game without a replay")
+	}
+}
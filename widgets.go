@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Size of a letter in Ebiten's debug font, which draws text when there is no font,
+// and the line height of all text
+const (
+	glyphWidth  = 6
+	glyphHeight = 16
+)
+
+// fontSize is the size of text drawn in a font, about as tall as the debug font
+const fontSize = 14
+
+// face draws all text, nil for Ebiten's debug font. It follows the theme in use, see UseTheme
+var face text.Face
+
+// newFace reads TrueType or OpenType data into a face, nil data giving a nil face
+func newFace(data []byte) (text.Face, error) {
+	if data == nil {
+		return nil, nil
+	}
+	source, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return &text.GoTextFace{Source: source, Size: fontSize}, nil
+}
+
+// Button size and the gap between buttons in a column
+const (
+	buttonWidth  = 240
+	buttonHeight = 40
+	buttonGap    = 16
+)
+
+// UIPalette holds the colors of the screens, which a theme can change
+type UIPalette struct {
+	Background  color.RGBA
+	Button      color.RGBA
+	ButtonHover color.RGBA
+	ButtonEdge  color.RGBA
+	Focus       color.RGBA // Marks what is being edited
+	Playable    color.RGBA // Marks the cards that can be played
+}
+
+// defaultPalette is the green table of the built in look
+var defaultPalette = UIPalette{
+	Background:  color.RGBA{0x1b, 0x5e, 0x20, 0xff},
+	Button:      color.RGBA{0x2e, 0x2e, 0x2e, 0xff},
+	ButtonHover: color.RGBA{0x4a, 0x4a, 0x4a, 0xff},
+	ButtonEdge:  color.RGBA{0xf5, 0xf5, 0xf5, 0xff},
+	Focus:       color.RGBA{0xff, 0xd5, 0x4f, 0xff},
+	Playable:    color.RGBA{0xff, 0xee, 0x66, 0xff},
+}
+
+// palette colors everything drawn, switched along with the theme
+var palette = defaultPalette
+
+// button is a labelled rectangle doing something when clicked
+type button struct {
+	label   string
+	rect    image.Rectangle
+	onClick func() error
+	hover   bool
+}
+
+// update clicks the button if the cursor is on it
+func (b *button) update(in Input) error {
+	b.hover = in.Cursor().In(b.rect)
+	if b.hover && in.Clicked() && b.onClick != nil {
+		return b.onClick()
+	}
+	return nil
+}
+
+func (b *button) draw(dst *ebiten.Image) {
+	fill := palette.Button
+	if b.hover {
+		fill = palette.ButtonHover
+	}
+	drawRect(dst, b.rect, fill)
+	strokeRect(dst, b.rect, palette.ButtonEdge)
+	drawTextCentered(dst, b.label, b.rect)
+}
+
+// buttoned is a screen made of buttons, which tests click by label
+type buttoned interface {
+	buttons() []*button
+}
+
+// updateButtons passes the input to every button, stopping at the first error
+func updateButtons(in Input, buttons []*button) error {
+	for _, b := range buttons {
+		if err := b.update(in); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func drawButtons(dst *ebiten.Image, buttons []*button) {
+	for _, b := range buttons {
+		b.draw(dst)
+	}
+}
+
+// buttonColumn lays out buttons one under the other, centered across the screen
+func buttonColumn(top int, buttons ...*button) []*button {
+	x := (ScreenWidth - buttonWidth) / 2
+	for i, b := range buttons {
+		y := top + i*(buttonHeight+buttonGap)
+		b.rect = image.Rect(x, y, x+buttonWidth, y+buttonHeight)
+	}
+	return buttons
+}
+
+// drawText writes a line with its top left corner at x, y
+func drawText(dst *ebiten.Image, line string, x, y int) {
+	if face == nil {
+		ebitenutil.DebugPrintAt(dst, line, x, y)
+		return
+	}
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	op.ColorScale.ScaleWithColor(color.White)
+	text.Draw(dst, line, face, op)
+}
+
+// textWidth returns how wide drawText draws a line, in pixels
+func textWidth(line string) int {
+	if face == nil {
+		return len(line) * glyphWidth
+	}
+	width, _ := text.Measure(line, face, 0)
+	return int(math.Ceil(width))
+}
+
+// drawTextCentered writes a line of text in the middle of the rectangle
+func drawTextCentered(dst *ebiten.Image, line string, rect image.Rectangle) {
+	x := rect.Min.X + (rect.Dx()-textWidth(line))/2
+	y := rect.Min.Y + (rect.Dy()-glyphHeight)/2
+	drawText(dst, line, x, y)
+}
+
+// drawHeading writes a line centered across the screen
+func drawHeading(dst *ebiten.Image, line string, y int) {
+	drawTextCentered(dst, line, image.Rect(0, y, ScreenWidth, y+glyphHeight))
+}
+
+func drawRect(dst *ebiten.Image, rect image.Rectangle, clr color.Color) {
+	vector.DrawFilledRect(dst, float32(rect.Min.X), float32(rect.Min.Y), float32(rect.Dx()), float32(rect.Dy()), clr, false)
+}
+
+func strokeRect(dst *ebiten.Image, rect image.Rectangle, clr color.Color) {
+	vector.StrokeRect(dst, float32(rect.Min.X), float32(rect.Min.Y), float32(rect.Dx()), float32(rect.Dy()), 2, clr, false)
+}